@@ -0,0 +1,109 @@
+package resp
+
+import "net/http"
+
+// APIRootDocument represents the entry point document of an API.
+// It lists the top-level resources so clients can discover them
+// by following the links instead of hard-coding URLs.
+type APIRootDocument struct {
+	// Title is the human-readable name of the API.
+	Title string `json:"title,omitempty"`
+
+	// Version is the version of the API.
+	Version string `json:"version,omitempty"`
+
+	// ServiceDesc is the URL of a machine-readable API description,
+	// e.g. an OpenAPI document (RFC 8631 "service-desc").
+	ServiceDesc string `json:"-"`
+
+	// ServiceDoc is the URL of human-readable API documentation
+	// (RFC 8631 "service-doc").
+	ServiceDoc string `json:"-"`
+
+	// Resources is the list of top-level resources.
+	Resources []APIResource `json:"-"`
+}
+
+// APIResource represents a link to a top-level resource of the API.
+type APIResource struct {
+	Rel   string `json:"rel"`             // relation (resource name)
+	Href  string `json:"href"`            // URL of the resource
+	Type  string `json:"type,omitempty"`  // media type of the resource
+	Title string `json:"title,omitempty"` // human-readable title
+}
+
+// apiRootBody is the JSON representation of the APIRootDocument.
+type apiRootBody struct {
+	Title   string        `json:"title,omitempty"`
+	Version string        `json:"version,omitempty"`
+	Links   []APIResource `json:"links"`
+}
+
+// links returns all links of the document, including the
+// service description and documentation links.
+func (d APIRootDocument) links() []APIResource {
+	links := make([]APIResource, 0, len(d.Resources)+2)
+	if d.ServiceDesc != "" {
+		links = append(links, APIResource{
+			Rel:  "service-desc",
+			Href: d.ServiceDesc,
+		})
+	}
+
+	if d.ServiceDoc != "" {
+		links = append(links, APIResource{
+			Rel:  "service-doc",
+			Href: d.ServiceDoc,
+		})
+	}
+
+	return append(links, d.Resources...)
+}
+
+// APIRoot sends the API entry point document.
+//
+// Every resource (and the service description and documentation, if
+// set) is sent both as a Link header and as an item of the "links"
+// array of the JSON body.
+//
+// Example usage:
+//
+//	func Root(w http.ResponseWriter, r *http.Request) {
+//	    resp.APIRoot(w, resp.APIRootDocument{
+//	        Title:       "Example API",
+//	        Version:     "1.0",
+//	        ServiceDesc: "/openapi.json",
+//	        ServiceDoc:  "/docs",
+//	        Resources: []resp.APIResource{
+//	            {Rel: "users", Href: "/users"},
+//	            {Rel: "orders", Href: "/orders"},
+//	        },
+//	    })
+//	}
+func APIRoot(
+	w http.ResponseWriter,
+	root APIRootDocument,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).APIRoot(root)
+}
+
+// APIRoot sends the API entry point document.
+// See the APIRoot function for details.
+func (r *Response) APIRoot(root APIRootDocument) error {
+	links := root.links()
+	for _, link := range links {
+		AddLink(LinkHeader{
+			URI:   link.Href,
+			Rel:   link.Rel,
+			Type:  link.Type,
+			Title: link.Title,
+		})(r)
+	}
+
+	return r.JSON(apiRootBody{
+		Title:   root.Title,
+		Version: root.Version,
+		Links:   links,
+	})
+}
@@ -0,0 +1,66 @@
+package resp
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAPIRoot tests the APIRoot function.
+func TestAPIRoot(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := APIRoot(w, APIRootDocument{
+		Title:       "Example API",
+		Version:     "1.0",
+		ServiceDesc: "/openapi.json",
+		ServiceDoc:  "/docs",
+		Resources: []APIResource{
+			{Rel: "users", Href: "/users", Type: MIMEApplicationJSON},
+		},
+	})
+	if err != nil {
+		t.Fatalf("APIRoot() returned error: %v", err)
+	}
+
+	want := []string{
+		`</openapi.json>; rel="service-desc"`,
+		`</docs>; rel="service-doc"`,
+		`</users>; rel="users"; type="application/json"`,
+	}
+	got := w.Header().Values(HeaderLink)
+	if len(got) != len(want) {
+		t.Fatalf("APIRoot() Link = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("APIRoot() Link[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	var body apiRootBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("APIRoot() body is not JSON: %v", err)
+	}
+
+	if body.Title != "Example API" || body.Version != "1.0" {
+		t.Errorf("APIRoot() body = %+v", body)
+	}
+
+	if len(body.Links) != 3 || body.Links[2].Href != "/users" {
+		t.Errorf("APIRoot() links = %+v", body.Links)
+	}
+}
+
+// TestAPIRoot_Empty tests that an empty document has an empty
+// links array instead of null.
+func TestAPIRoot_Empty(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := APIRoot(w, APIRootDocument{}); err != nil {
+		t.Fatalf("APIRoot() returned error: %v", err)
+	}
+
+	if got := w.Body.String(); got != "{\"links\":[]}\n" {
+		t.Errorf("APIRoot() body = %q", got)
+	}
+}
@@ -0,0 +1,148 @@
+package resp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// VersionPlaceholder is the placeholder of the version number
+// in the media type pattern of NegotiateVersion.
+const VersionPlaceholder = "{n}"
+
+// NegotiateVersion selects the API version requested by the media type
+// of the Accept header (media type versioning), e.g. the version 2 for
+// "application/vnd.myapp.v2+json" with the pattern
+// "application/vnd.myapp.v{n}+json". The supported versions are listed
+// in the order of server preference that is used when several versions
+// have the same quality.
+//
+// If the Accept header is missing, or no supported version is named
+// but other media ranges are acceptable (e.g. "application/json" or
+// "*/*"), the first supported version is selected. If the client asks
+// only for the unsupported versions, NegotiateVersion sends the 406 Not
+// Acceptable response (see Negotiate) and returns false.
+//
+// The Content-Type header is set to the media type of the selected
+// version, and the Accept header is merged into the Vary header (see
+// WithoutAutoVary).
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    v, ok := resp.NegotiateVersion(w, r,
+//	        "application/vnd.myapp.v{n}+json", 2, 1)
+//	    if !ok {
+//	        return // 406 is already sent
+//	    }
+//
+//	    if v == 1 {
+//	        resp.JSON(w, toV1(user))
+//	        return
+//	    }
+//	    resp.JSON(w, user)
+//	}
+func NegotiateVersion(
+	w http.ResponseWriter,
+	r *http.Request,
+	pattern string,
+	supported ...int,
+) (int, bool) {
+	return NewResponse(w).NegotiateVersion(r, pattern, supported...)
+}
+
+// NegotiateVersion selects the API version requested by the media type
+// of the Accept header. See the NegotiateVersion function for details.
+func (r *Response) NegotiateVersion(
+	req *http.Request,
+	pattern string,
+	supported ...int,
+) (int, bool) {
+	r.varyOn(HeaderAccept)
+
+	variants := make([]Variant, len(supported))
+	for i, v := range supported {
+		variants[i] = Variant{
+			MediaType: versionMediaType(pattern, v),
+		}
+	}
+
+	accept := strings.Join(req.Header.Values(HeaderAccept), ",")
+	named, other := splitVersionRanges(parseAccept(accept), pattern)
+
+	best, bestQ := -1, 0.0
+	for i, v := range variants {
+		if q := quality(named, v); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	// The other media ranges (e.g. "application/json" or "*/*")
+	// accept the default version.
+	if best < 0 && len(supported) > 0 &&
+		(len(named) == 0 || acceptsAny(other)) {
+		best = 0
+	}
+
+	if best < 0 {
+		r.notAcceptable(req, variants)
+		return 0, false
+	}
+
+	r.httpWriter.Header().Set(HeaderContentType, variants[best].MediaType)
+	return supported[best], true
+}
+
+// versionMediaType returns the media type of the version.
+func versionMediaType(pattern string, version int) string {
+	return strings.Replace(pattern,
+		VersionPlaceholder, strconv.Itoa(version), 1)
+}
+
+// splitVersionRanges splits the media ranges into the ranges
+// that name the media types of the pattern and the other ones.
+func splitVersionRanges(
+	ranges []acceptRange,
+	pattern string,
+) (named, other []acceptRange) {
+	for _, a := range ranges {
+		if matchesVersionPattern(a.mediaType, pattern) {
+			named = append(named, a)
+		} else {
+			other = append(other, a)
+		}
+	}
+
+	return named, other
+}
+
+// acceptsAny returns true if any of the media ranges is acceptable.
+func acceptsAny(ranges []acceptRange) bool {
+	for _, a := range ranges {
+		if a.q > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesVersionPattern returns true if the media type
+// matches the pattern with any version.
+func matchesVersionPattern(mediaType, pattern string) bool {
+	pattern = strings.ToLower(pattern)
+	prefix, suffix, ok := strings.Cut(pattern, VersionPlaceholder)
+	if !ok {
+		return mediaType == pattern
+	}
+
+	if len(mediaType) <= len(prefix)+len(suffix) ||
+		!strings.HasPrefix(mediaType, prefix) ||
+		!strings.HasSuffix(mediaType, suffix) {
+		return false
+	}
+
+	version := mediaType[len(prefix) : len(mediaType)-len(suffix)]
+	_, err := strconv.Atoi(version)
+	return err == nil
+}
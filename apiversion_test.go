@@ -0,0 +1,77 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNegotiateVersion tests the NegotiateVersion function.
+func TestNegotiateVersion(t *testing.T) {
+	const pattern = "application/vnd.myapp.v{n}+json"
+
+	tests := []struct {
+		name   string
+		accept string
+		want   int
+		ok     bool
+	}{
+		{"No accept", "", 2, true},
+		{"Exact", "application/vnd.myapp.v1+json", 1, true},
+		{"Case-insensitive", "application/VND.MyApp.V1+JSON", 1, true},
+		{"Generic", "application/json", 2, true},
+		{"Wildcard", "*/*", 2, true},
+		{
+			"Quality",
+			"application/vnd.myapp.v2+json;q=0.5, " +
+				"application/vnd.myapp.v1+json",
+			1, true,
+		},
+		{
+			"Unsupported with fallback",
+			"application/vnd.myapp.v3+json, */*;q=0.1",
+			2, true,
+		},
+		{"Unsupported", "application/vnd.myapp.v3+json", 0, false},
+		{"Excluded", "application/vnd.myapp.v2+json;q=0", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			if tt.accept != "" {
+				r.Header.Set(HeaderAccept, tt.accept)
+			}
+
+			got, ok := NegotiateVersion(w, r, pattern, 2, 1)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("NegotiateVersion() = %d, %v, want %d, %v",
+					got, ok, tt.want, tt.ok)
+			}
+
+			if v := w.Header().Get(HeaderVary); v != HeaderAccept {
+				t.Errorf("Vary = %q, want %q", v, HeaderAccept)
+			}
+
+			if !ok {
+				if w.Code != StatusNotAcceptable {
+					t.Errorf("status = %d, want %d",
+						w.Code, StatusNotAcceptable)
+				}
+				return
+			}
+
+			want := versionMediaType(pattern, tt.want)
+			if ct := w.Header().Get(HeaderContentType); ct != want {
+				t.Errorf("Content-Type = %q, want %q", ct, want)
+			}
+
+			// The Content-Type isn't replaced by the JSON response.
+			JSON(w, R{"id": 1})
+			if ct := w.Header().Get(HeaderContentType); ct != want {
+				t.Errorf("JSON() Content-Type = %q, want %q", ct, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package resp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// randFloat returns a pseudo-random number in [0.0, 1.0).
+// It is a variable to allow tests to control sampling.
+var randFloat = rand.Float64
+
+// ArchiveRecord is the summary of a request and its response
+// passed to an Archiver. The headers listed in ArchiveConfig.Redact
+// are replaced with the RedactedValue.
+type ArchiveRecord struct {
+	Time          time.Time     `json:"time"`
+	Method        string        `json:"method"`
+	URL           string        `json:"url"`
+	RequestHeader http.Header   `json:"request_header,omitempty"`
+	Status        int           `json:"status"`
+	Bytes         int64         `json:"bytes"`
+	Duration      time.Duration `json:"duration"`
+	ContentType   string        `json:"content_type,omitempty"`
+	Header        http.Header   `json:"header,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// Archiver is the interface of a sink (e.g. object storage, file)
+// that retains the summaries of the written responses.
+// See the WithArchiver option.
+type Archiver interface {
+	// Archive stores the record. It is called in a separate
+	// goroutine, so it must be safe for concurrent use.
+	Archive(ctx context.Context, record ArchiveRecord) error
+}
+
+// RedactedValue replaces the values of the redacted headers.
+const RedactedValue = "[REDACTED]"
+
+// ArchiveConfig controls the archival of the responses.
+type ArchiveConfig struct {
+	// SampleRate is the fraction of the responses to archive,
+	// from 0 (none) to 1 (all).
+	SampleRate float64
+
+	// Redact is the list of the request and response headers
+	// whose values are replaced with the RedactedValue.
+	Redact []string
+
+	// Timeout limits the duration of the Archive call;
+	// zero means no limit.
+	Timeout time.Duration
+}
+
+// DefaultArchiveConfig returns the configuration used by
+// WithArchiver when no configuration is provided.
+//
+// The defaults are:
+//   - SampleRate: 1 (all responses are archived)
+//   - Redact: Authorization, Proxy-Authorization, Cookie, Set-Cookie
+//   - Timeout: 30 seconds
+func DefaultArchiveConfig() ArchiveConfig {
+	return ArchiveConfig{
+		SampleRate: 1,
+		Redact: []string{
+			HeaderAuthorization,
+			HeaderProxyAuthorization,
+			HeaderCookie,
+			HeaderSetCookie,
+		},
+		Timeout: 30 * time.Second,
+	}
+}
+
+// WithArchiver ships a redacted summary of the request and the
+// response to the archiver after the response is written, for
+// compliance with the data retention rules. The archiver is called
+// asynchronously, so it doesn't delay the handler; its errors are
+// reported to the logger (see WithLogger).
+//
+// Example Usage:
+//
+//	config := resp.DefaultArchiveConfig()
+//	config.SampleRate = 0.1
+//
+//	resp.JSON(w, data, resp.WithArchiver(archiver, r, config))
+func WithArchiver(
+	a Archiver,
+	req *http.Request,
+	config ...ArchiveConfig,
+) Option {
+	cfg := DefaultArchiveConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(r *Response) *Response {
+		r.afterWrite = append(r.afterWrite, func(info ResponseInfo) {
+			if cfg.SampleRate <= 0 ||
+				(cfg.SampleRate < 1 && randFloat() >= cfg.SampleRate) {
+				return
+			}
+
+			record := ArchiveRecord{
+				Time:        r.createdAt,
+				Method:      req.Method,
+				Status:      info.Status,
+				Bytes:       info.Bytes,
+				Duration:    info.Duration,
+				ContentType: info.ContentType,
+				RequestHeader: redactHeader(
+					req.Header, cfg.Redact),
+				Header: redactHeader(
+					r.httpWriter.Header(), cfg.Redact),
+			}
+
+			if req.URL != nil {
+				record.URL = req.URL.String()
+			}
+
+			if info.Err != nil {
+				record.Error = info.Err.Error()
+			}
+
+			go r.archive(a, record, cfg.Timeout)
+		})
+		return r
+	}
+}
+
+// archive sends the record to the archiver and logs its error.
+func (r *Response) archive(
+	a Archiver,
+	record ArchiveRecord,
+	timeout time.Duration,
+) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := a.Archive(ctx, record); err != nil && r.logger != nil {
+		r.logger.LogAttrs(ctx, slog.LevelError,
+			"resp: failed to archive response",
+			slog.String("url", record.URL),
+			slog.Int("status", record.Status),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// redactHeader returns a copy of the header with the values
+// of the listed headers replaced with the RedactedValue.
+func redactHeader(h http.Header, redact []string) http.Header {
+	if h == nil {
+		return nil
+	}
+
+	result := h.Clone()
+	for _, name := range redact {
+		key := http.CanonicalHeaderKey(name)
+		if values, ok := result[key]; ok {
+			result[key] = make([]string, len(values))
+			for i := range values {
+				result[key][i] = RedactedValue
+			}
+		}
+	}
+
+	return result
+}
+
+// writerArchiver is an Archiver that writes the records
+// as JSON lines to the writer.
+type writerArchiver struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterArchiver returns an Archiver that writes the records as
+// JSON lines to the writer (e.g. a file). Writes are serialized, so
+// the archiver is safe for concurrent use. Object storage sinks
+// (S3, GCS) can be plugged in by implementing the Archiver interface.
+func NewWriterArchiver(w io.Writer) Archiver {
+	return &writerArchiver{w: w}
+}
+
+// Archive writes the record as a JSON line.
+func (a *writerArchiver) Archive(
+	_ context.Context,
+	record ArchiveRecord,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return json.NewEncoder(a.w).Encode(record)
+}
@@ -0,0 +1,187 @@
+package resp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chanArchiver sends the archived records to the channel.
+type chanArchiver struct {
+	records chan ArchiveRecord
+	err     error
+}
+
+// Archive sends the record to the channel.
+func (a *chanArchiver) Archive(_ context.Context, rec ArchiveRecord) error {
+	a.records <- rec
+	return a.err
+}
+
+// syncBuffer is a buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write writes the data to the buffer.
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the contents of the buffer.
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestWithArchiver tests the archival of the response summary.
+func TestWithArchiver(t *testing.T) {
+	a := &chanArchiver{records: make(chan ArchiveRecord, 1)}
+	req := httptest.NewRequest(http.MethodPost, "/users?id=1", nil)
+	req.Header.Set(HeaderAuthorization, "Bearer secret")
+	req.Header.Set("X-Request-Id", "42")
+
+	w := httptest.NewRecorder()
+	err := JSON(w, R{"ok": true},
+		WithStatus(StatusCreated),
+		WithCookie(&http.Cookie{Name: "session", Value: "secret"}),
+		WithArchiver(a, req),
+	)
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	var rec ArchiveRecord
+	select {
+	case rec = <-a.records:
+	case <-time.After(time.Second):
+		t.Fatal("WithArchiver() record isn't archived")
+	}
+
+	if rec.Method != http.MethodPost || rec.URL != "/users?id=1" {
+		t.Errorf("WithArchiver() request = %s %s", rec.Method, rec.URL)
+	}
+
+	if rec.Status != StatusCreated || rec.Bytes != 12 {
+		t.Errorf("WithArchiver() status = %d, bytes = %d",
+			rec.Status, rec.Bytes)
+	}
+
+	if got := rec.RequestHeader.Get(HeaderAuthorization); got != RedactedValue {
+		t.Errorf("WithArchiver() Authorization = %q", got)
+	}
+
+	if got := rec.RequestHeader.Get("X-Request-Id"); got != "42" {
+		t.Errorf("WithArchiver() X-Request-Id = %q", got)
+	}
+
+	if got := rec.Header.Get(HeaderSetCookie); got != RedactedValue {
+		t.Errorf("WithArchiver() Set-Cookie = %q", got)
+	}
+
+	if !strings.HasPrefix(w.Header().Get(HeaderSetCookie), "session=secret") {
+		t.Error("WithArchiver() must not redact the sent headers")
+	}
+}
+
+// TestWithArchiverSampling tests the sampling of the archived responses.
+func TestWithArchiverSampling(t *testing.T) {
+	defer func(f func() float64) { randFloat = f }(randFloat)
+	randFloat = func() float64 { return 0.5 }
+
+	tests := []struct {
+		rate float64
+		want bool
+	}{
+		{rate: 0, want: false},
+		{rate: 0.25, want: false},
+		{rate: 0.75, want: true},
+		{rate: 1, want: true},
+	}
+
+	for _, tt := range tests {
+		a := &chanArchiver{records: make(chan ArchiveRecord, 1)}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		config := DefaultArchiveConfig()
+		config.SampleRate = tt.rate
+
+		NoContent(httptest.NewRecorder(), WithArchiver(a, req, config))
+
+		select {
+		case <-a.records:
+			if !tt.want {
+				t.Errorf("rate %v: record must not be archived", tt.rate)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if tt.want {
+				t.Errorf("rate %v: record isn't archived", tt.rate)
+			}
+		}
+	}
+}
+
+// TestWithArchiverLogsError tests that the archiver errors are logged.
+func TestWithArchiverLogsError(t *testing.T) {
+	var buf syncBuffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	a := &chanArchiver{
+		records: make(chan ArchiveRecord, 1),
+		err:     errors.New("bucket is gone"),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	String(httptest.NewRecorder(), "ok",
+		WithLogger(logger), WithArchiver(a, req))
+	<-a.records
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "bucket is gone") {
+		if time.Now().After(deadline) {
+			t.Fatalf("WithArchiver() log = %q", buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestNewWriterArchiver tests the JSON lines archiver.
+func TestNewWriterArchiver(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewWriterArchiver(&buf)
+
+	for _, status := range []int{StatusOK, StatusNotFound} {
+		err := a.Archive(context.Background(), ArchiveRecord{
+			Method: http.MethodGet,
+			URL:    "/",
+			Status: status,
+		})
+		if err != nil {
+			t.Fatalf("Archive() error = %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Archive() lines = %d, want 2", len(lines))
+	}
+
+	var rec ArchiveRecord
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("Archive() invalid JSON: %v", err)
+	}
+
+	if rec.Status != StatusNotFound {
+		t.Errorf("Archive() status = %d", rec.Status)
+	}
+}
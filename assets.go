@@ -0,0 +1,149 @@
+package resp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// AssetsCacheControl is the Cache-Control header of the fingerprinted
+// assets: their content never changes under the same URL.
+const AssetsCacheControl = "public, max-age=31536000, immutable"
+
+// fingerprintLen is the length of the fingerprint of the asset,
+// the hex-encoded prefix of the SHA-256 hash of its content.
+const fingerprintLen = 12
+
+// Assets serves the static assets of the file system (e.g. embed.FS)
+// under the URL prefix, with the fingerprinted URLs: the fingerprint
+// of the content is inserted before the file extension, e.g.
+// /static/app.3f2a9c81b0de.css, so the assets can be cached forever
+// and the new versions are loaded immediately.
+//
+// The fingerprinted assets are sent with the AssetsCacheControl header,
+// the plain ones (without the fingerprint) with "no-cache", so they are
+// revalidated. The Content-Type is detected from the file extension.
+// The fingerprints are computed on the first use and cached, so the
+// file system must not change (use a new Assets for a new version).
+//
+// Example Usage:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	sub, _ := fs.Sub(static, "static")
+//	assets := resp.NewAssets(sub, "/static/")
+//	http.Handle("/static/", assets)
+//
+//	// In the template: <link rel="stylesheet" href="{{ asset "app.css" }}">
+//	funcs := template.FuncMap{"asset": assets.URL}
+type Assets struct {
+	fsys   fs.FS
+	prefix string
+
+	mu           sync.RWMutex
+	fingerprints map[string]string
+}
+
+// NewAssets returns the Assets of the file system served under the URL
+// prefix. The prefix ends with a slash, e.g. "/static/".
+func NewAssets(fsys fs.FS, prefix string) *Assets {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &Assets{
+		fsys:         fsys,
+		prefix:       prefix,
+		fingerprints: make(map[string]string),
+	}
+}
+
+// URL returns the fingerprinted URL of the asset, e.g. "app.css"
+// becomes "/static/app.3f2a9c81b0de.css". If the asset can't be read,
+// the URL without the fingerprint is returned.
+func (a *Assets) URL(name string) string {
+	name = cleanAssetName(name)
+
+	sum, err := a.fingerprint(name)
+	if err != nil {
+		return a.prefix + name
+	}
+
+	ext := path.Ext(name)
+	return a.prefix + strings.TrimSuffix(name, ext) + "." + sum + ext
+}
+
+// ServeHTTP serves the asset of the request path (after the prefix).
+// The fingerprinted path is served only if the fingerprint matches the
+// actual content, otherwise the 404 error response is sent.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, a.prefix)
+	name = cleanAssetName(name)
+
+	cacheControl := "no-cache"
+	if original, ok := a.original(name); ok {
+		name = original
+		cacheControl = AssetsCacheControl
+	}
+
+	ServeFS(w, r, a.fsys, name, AddCacheControl(cacheControl))
+}
+
+// original returns the name of the asset of the fingerprinted name,
+// and false if the name has no valid fingerprint.
+func (a *Assets) original(name string) (string, bool) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	i := strings.LastIndexByte(base, '.')
+	if i < 0 || len(base)-i-1 != fingerprintLen {
+		return "", false
+	}
+
+	original := base[:i] + ext
+	sum, err := a.fingerprint(original)
+	if err != nil || sum != base[i+1:] {
+		return "", false
+	}
+
+	return original, true
+}
+
+// fingerprint returns the cached fingerprint of the asset.
+func (a *Assets) fingerprint(name string) (string, error) {
+	a.mu.RLock()
+	sum, ok := a.fingerprints[name]
+	a.mu.RUnlock()
+	if ok {
+		return sum, nil
+	}
+
+	f, err := a.fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	sum = hex.EncodeToString(h.Sum(nil))[:fingerprintLen]
+
+	a.mu.Lock()
+	a.fingerprints[name] = sum
+	a.mu.Unlock()
+
+	return sum, nil
+}
+
+// cleanAssetName returns the clean relative name of the asset.
+func cleanAssetName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,93 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestAssets tests the fingerprinted URLs and serving of the Assets.
+func TestAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.css":       {Data: []byte("body{color:red}")},
+		"js/app.min.js": {Data: []byte("console.log(1)")},
+	}
+	assets := NewAssets(fsys, "/static")
+
+	css := assets.URL("app.css")
+	if !strings.HasPrefix(css, "/static/app.") ||
+		!strings.HasSuffix(css, ".css") ||
+		len(css) != len("/static/app..css")+fingerprintLen {
+		t.Fatalf("URL() = %q", css)
+	}
+
+	js := assets.URL("/js/app.min.js")
+	if !strings.HasPrefix(js, "/static/js/app.min.") {
+		t.Errorf("URL() = %q", js)
+	}
+
+	if got := assets.URL("missing.css"); got != "/static/missing.css" {
+		t.Errorf("URL() = %q, want %q", got, "/static/missing.css")
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantType     string
+		cacheControl string
+	}{
+		{
+			name:         "Fingerprinted",
+			path:         css,
+			wantStatus:   StatusOK,
+			wantType:     "text/css; charset=utf-8",
+			cacheControl: AssetsCacheControl,
+		},
+		{
+			name:         "Fingerprinted in directory",
+			path:         js,
+			wantStatus:   StatusOK,
+			wantType:     "text/javascript; charset=utf-8",
+			cacheControl: AssetsCacheControl,
+		},
+		{
+			name:         "Plain",
+			path:         "/static/app.css",
+			wantStatus:   StatusOK,
+			wantType:     "text/css; charset=utf-8",
+			cacheControl: "no-cache",
+		},
+		{
+			name:       "Stale fingerprint",
+			path:       "/static/app.000000000000.css",
+			wantStatus: StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			assets.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != StatusOK {
+				return
+			}
+
+			if got := w.Header().Get(HeaderContentType); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+
+			got := w.Header().Get(HeaderCacheControl)
+			if got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+		})
+	}
+}
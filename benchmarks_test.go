@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -146,6 +147,20 @@ func BenchmarkStream(b *testing.B) {
 	}
 }
 
+// BenchmarkStreamReader benchmarks Stream response
+// of a reader without the WriteTo method
+func BenchmarkStreamReader(b *testing.B) {
+	data := bytes.NewReader(bytes.Repeat([]byte("stream data "), 1024))
+	w := helperNewRecorder()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data.Seek(0, io.SeekStart)
+		Stream(w, struct{ io.Reader }{data})
+	}
+}
+
 // BenchmarkRedirect benchmarks Redirect response
 func BenchmarkRedirect(b *testing.B) {
 	w := helperNewRecorder()
@@ -193,6 +208,20 @@ func BenchmarkResponseChaining(b *testing.B) {
 	}
 }
 
+// BenchmarkSetHeader benchmarks setting single and list headers
+func BenchmarkSetHeader(b *testing.B) {
+	w := helperNewRecorder()
+	response := NewResponse(w)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		response.SetHeader(HeaderContentType, MIMEApplicationJSON)
+		response.SetHeader(HeaderCacheControl, "no-cache")
+		response.AddHeader(HeaderETag, `"v1"`)
+	}
+}
+
 // BenchmarkServeFileAsDownload benchmarks serving file as download
 func BenchmarkServeFileAsDownload(b *testing.B) {
 	w := helperNewRecorder()
@@ -203,3 +232,84 @@ func BenchmarkServeFileAsDownload(b *testing.B) {
 		ServeFileAsDownload(w, "test.txt", data)
 	}
 }
+
+// uncachedOnlyFields extracts the fields re-reflecting the type for
+// every element, as OnlyFields did before the metadata cache.
+func uncachedOnlyFields(data []testStruct, fields ...string) []R {
+	result := make([]R, len(data))
+	for i := range data {
+		allowed := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			allowed[field] = true
+		}
+
+		rv := reflect.ValueOf(data[i])
+		rt := rv.Type()
+		r := make(R)
+		for j := 0; j < rv.NumField(); j++ {
+			if name := rt.Field(j).Name; allowed[name] {
+				r[name] = rv.Field(j).Interface()
+			}
+		}
+		result[i] = r
+	}
+
+	return result
+}
+
+// BenchmarkOnlyFieldsSlice benchmarks OnlyFields on a large slice
+func BenchmarkOnlyFieldsSlice(b *testing.B) {
+	data := make([]testStruct, 1000)
+	for i := range data {
+		data[i] = largeData[i%len(largeData)]
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		OnlyFields(data, "Name", "Email")
+	}
+}
+
+// BenchmarkOnlyFieldsSliceUncached benchmarks the field extraction
+// without the metadata cache, for comparison with OnlyFields
+func BenchmarkOnlyFieldsSliceUncached(b *testing.B) {
+	data := make([]testStruct, 1000)
+	for i := range data {
+		data[i] = largeData[i%len(largeData)]
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		uncachedOnlyFields(data, "Name", "Email")
+	}
+}
+
+// BenchmarkExcludeFieldsSlice benchmarks ExcludeFields on a large slice
+func BenchmarkExcludeFieldsSlice(b *testing.B) {
+	data := make([]testStruct, 1000)
+	for i := range data {
+		data[i] = largeData[i%len(largeData)]
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ExcludeFields(data, "Address")
+	}
+}
+
+// BenchmarkPickSlice benchmarks PickSlice on a large slice
+func BenchmarkPickSlice(b *testing.B) {
+	data := make([]testStruct, 1000)
+	for i := range data {
+		data[i] = largeData[i%len(largeData)]
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		PickSlice(data, "Name", "Email")
+	}
+}
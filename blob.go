@@ -0,0 +1,45 @@
+package resp
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Blob sends the raw bytes with the explicit content type, e.g. an
+// image, a WebAssembly module or a font. The Content-Length header is
+// set from the size of the data. If the content type is empty, the
+// Content-Type set with the options (or application/octet-stream) is
+// used.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    png, _ := chart.Render()
+//	    resp.Blob(w, "image/png", png, resp.AddCacheControl("no-store"))
+//	}
+func Blob(
+	w http.ResponseWriter,
+	contentType string,
+	data []byte,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).Blob(contentType, data)
+}
+
+// Blob sends the raw bytes with the explicit content type.
+// See the Blob function for details.
+func (r *Response) Blob(contentType string, data []byte) (err error) {
+	defer r.finish(&err)
+
+	header := r.httpWriter.Header()
+	if contentType != "" {
+		header.Set(HeaderContentType, contentType)
+	}
+	header.Set(HeaderContentLength, strconv.Itoa(len(data)))
+
+	r.prepare(StatusOK, MIMEOctetStream)
+	r.setBodyDigest(data)
+	r.writeHeader(r.statusCode)
+	_, err = r.write(data)
+	return err
+}
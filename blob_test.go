@@ -0,0 +1,54 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestBlob tests the Blob function.
+func TestBlob(t *testing.T) {
+	data := []byte{0x00, 0x61, 0x73, 0x6d}
+
+	tests := []struct {
+		name        string
+		contentType string
+		opts        []Option
+		want        string
+	}{
+		{
+			name:        "Explicit type",
+			contentType: "application/wasm",
+			want:        "application/wasm",
+		},
+		{
+			name: "Type from options",
+			opts: []Option{AsTextPlain()},
+			want: MIMETextPlain,
+		},
+		{
+			name: "Default type",
+			want: MIMEOctetStream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := Blob(w, tt.contentType, data, tt.opts...); err != nil {
+				t.Fatalf("Blob() error = %v", err)
+			}
+
+			if got := w.Header().Get(HeaderContentType); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+
+			if got := w.Header().Get(HeaderContentLength); got != "4" {
+				t.Errorf("Content-Length = %q, want %q", got, "4")
+			}
+
+			if w.Code != StatusOK || w.Body.String() != string(data) {
+				t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+			}
+		})
+	}
+}
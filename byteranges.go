@@ -0,0 +1,292 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// ErrRangeNotSatisfiable is returned by ParseRange
+// if none of the ranges overlaps the content.
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// MaxRanges is the maximum number of the ranges of the Range header
+// accepted by ParseRange. The requests with many small ranges are
+// expensive to serve and are used for the denial-of-service attacks.
+const MaxRanges = 100
+
+// ByteRange is a range of the content bytes requested
+// with the Range header.
+type ByteRange struct {
+	Start  int64 // offset of the first byte
+	Length int64 // number of bytes
+}
+
+// contentRange returns the value of the Content-Range
+// header of the range of the content of the size.
+func (br ByteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d",
+		br.Start, br.Start+br.Length-1, size)
+}
+
+// ParseRange parses the value of the Range header (e.g. "bytes=0-99,
+// 200-") for the content of the size. The ranges that start beyond the
+// end of the content are skipped, and the ranges that end beyond it are
+// shortened. An error is returned if the header is malformed, has more
+// than MaxRanges ranges, or if none of the ranges overlaps the content
+// (ErrRangeNotSatisfiable). As ServeRanges does, the caller sends the
+// 416 (Range Not Satisfiable) response for ErrRangeNotSatisfiable (see
+// errors.Is) and ignores the header with the other errors, sending the
+// full content (RFC 9110, 14.2). An empty header yields no ranges.
+func ParseRange(header string, size int64) ([]ByteRange, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return nil, fmt.Errorf("invalid range %q", header)
+	}
+
+	specs := strings.Split(header[len(prefix):], ",")
+	if len(specs) > MaxRanges {
+		return nil, fmt.Errorf("too many ranges: %d, the limit is %d",
+			len(specs), MaxRanges)
+	}
+
+	var ranges []ByteRange
+	skipped := false
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %q", header)
+		}
+
+		first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+		var br ByteRange
+		if first == "" {
+			// The suffix range, e.g. "-500" is the last 500 bytes.
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid range %q", header)
+			}
+
+			if n > size {
+				n = size
+			}
+			br = ByteRange{Start: size - n, Length: n}
+		} else {
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, fmt.Errorf("invalid range %q", header)
+			}
+
+			end := size - 1
+			if last != "" {
+				end, err = strconv.ParseInt(last, 10, 64)
+				if err != nil || end < start {
+					return nil, fmt.Errorf("invalid range %q", header)
+				}
+				if end >= size {
+					end = size - 1
+				}
+			}
+			br = ByteRange{Start: start, Length: end - start + 1}
+		}
+
+		if br.Start >= size || br.Length <= 0 {
+			skipped = true
+			continue
+		}
+		ranges = append(ranges, br)
+	}
+
+	if len(ranges) == 0 && skipped {
+		return nil, ErrRangeNotSatisfiable
+	}
+
+	return ranges, nil
+}
+
+// ServeRanges sends the ranges of the content requested with the Range
+// header of the request: a single range is sent with the 206 (Partial
+// Content) status and the Content-Range header, several ranges as the
+// multipart/byteranges body, where each part has its own Content-Range
+// header. If the request has no Range header, the whole content is
+// sent; if the ranges can't be satisfied, the 416 (Range Not
+// Satisfiable) status is sent. Like http.ServeContent, the whole content
+// is sent as well if the ranges are malformed, exceed MaxRanges, or
+// their total length exceeds the size of the content (e.g. the
+// overlapping ranges that would multiply the body).
+//
+// The content is read with io.ReaderAt, so it suits the sources that
+// can't seek, e.g. the ranged reads of an object store. The conditional
+// headers aren't evaluated; use ServeContent for io.ReadSeeker content.
+// If the Content-Type isn't set with the options, application/octet-stream
+// is used.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    obj := bucket.Object(key) // implements io.ReaderAt
+//	    resp.ServeRanges(w, r, obj, obj.Size(),
+//	        resp.AddContentType("video/mp4"))
+//	}
+func ServeRanges(
+	w http.ResponseWriter,
+	req *http.Request,
+	content io.ReaderAt,
+	size int64,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).ServeRanges(req, content, size)
+}
+
+// ServeRanges sends the ranges of the content requested with the
+// request. See the ServeRanges function for details.
+func (r *Response) ServeRanges(
+	req *http.Request,
+	content io.ReaderAt,
+	size int64,
+) (err error) {
+	defer r.finish(&err)
+
+	header := r.httpWriter.Header()
+	header.Set(HeaderAcceptRanges, "bytes")
+
+	ranges, err := ParseRange(req.Header.Get(HeaderRange), size)
+	if errors.Is(err, ErrRangeNotSatisfiable) {
+		header.Set(HeaderContentRange,
+			"bytes */"+strconv.FormatInt(size, 10))
+		r.writeHeader(StatusRequestedRangeNotSatisfiable)
+		return nil
+	}
+
+	// A malformed Range header is ignored (RFC 9110, 14.2).
+	r.prepare(StatusOK, MIMEOctetStream)
+	if err != nil || len(ranges) == 0 || rangesLength(ranges) > size {
+		ranges = []ByteRange{{Start: 0, Length: size}}
+	} else {
+		r.statusCode = StatusPartialContent
+	}
+
+	if len(ranges) == 1 {
+		br := ranges[0]
+		if r.statusCode == StatusPartialContent {
+			header.Set(HeaderContentRange, br.contentRange(size))
+		}
+		header.Set(HeaderContentLength, strconv.FormatInt(br.Length, 10))
+		r.writeHeader(r.statusCode)
+		return writeRange(r.body(), content, br)
+	}
+
+	return r.writeByteRanges(content, size, ranges)
+}
+
+// rangesLength returns the total length of the ranges.
+func rangesLength(ranges []ByteRange) int64 {
+	var n int64
+	for _, br := range ranges {
+		n += br.Length
+	}
+
+	return n
+}
+
+// writeByteRanges writes the ranges of the content
+// as the multipart/byteranges body.
+func (r *Response) writeByteRanges(
+	content io.ReaderAt,
+	size int64,
+	ranges []ByteRange,
+) error {
+	header := r.httpWriter.Header()
+	ctype := header.Get(HeaderContentType)
+
+	// The body is generated twice: without the content to find out
+	// the Content-Length, and with the content, with the same boundary.
+	counter := &countingWriter{}
+	mw := multipart.NewWriter(counter)
+	for _, br := range ranges {
+		_, err := mw.CreatePart(rangePartHeader(ctype, br, size))
+		if err != nil {
+			return err
+		}
+		counter.n += br.Length
+	}
+	mw.Close()
+
+	header.Set(HeaderContentType,
+		MIMEMultipartByteranges+"; boundary="+mw.Boundary())
+	header.Set(HeaderContentLength, strconv.FormatInt(counter.n, 10))
+	r.writeHeader(r.statusCode)
+
+	body := multipart.NewWriter(r.body())
+	body.SetBoundary(mw.Boundary())
+	for _, br := range ranges {
+		part, err := body.CreatePart(rangePartHeader(ctype, br, size))
+		if err != nil {
+			return err
+		}
+
+		if err := writeRange(part, content, br); err != nil {
+			return err
+		}
+	}
+
+	return body.Close()
+}
+
+// writeRange copies the range of the content to the writer.
+func writeRange(
+	w io.Writer,
+	content io.ReaderAt,
+	br ByteRange,
+) error {
+	sr := io.NewSectionReader(content, br.Start, br.Length)
+	n, err := copyBuffer(w, sr)
+	if err != nil {
+		return err
+	}
+
+	if n < br.Length {
+		return fmt.Errorf("content is shorter than the range %d-%d: %w",
+			br.Start, br.Start+br.Length-1, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// rangePartHeader returns the headers of the
+// multipart/byteranges part of the range.
+func rangePartHeader(
+	ctype string,
+	br ByteRange,
+	size int64,
+) textproto.MIMEHeader {
+	h := textproto.MIMEHeader{}
+	h.Set(HeaderContentType, ctype)
+	h.Set(HeaderContentRange, br.contentRange(size))
+	return h
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int64
+}
+
+// Write counts the bytes.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,255 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestParseRange tests the ParseRange function.
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    []ByteRange
+		wantErr bool
+		wantNot bool // the error is ErrRangeNotSatisfiable
+	}{
+		{name: "Empty"},
+		{
+			name:   "Single",
+			header: "bytes=0-4",
+			want:   []ByteRange{{0, 5}},
+		},
+		{
+			name:   "Open end",
+			header: "bytes=7-",
+			want:   []ByteRange{{7, 6}},
+		},
+		{
+			name:   "Suffix",
+			header: "bytes=-6",
+			want:   []ByteRange{{7, 6}},
+		},
+		{
+			name:   "Multiple with clamped end",
+			header: "bytes=0-1, 10-99",
+			want:   []ByteRange{{0, 2}, {10, 3}},
+		},
+		{
+			name:   "Unsatisfiable skipped",
+			header: "bytes=0-1,50-60",
+			want:   []ByteRange{{0, 2}},
+		},
+		{
+			name:    "Unsatisfiable",
+			header:  "bytes=50-60",
+			wantErr: true,
+			wantNot: true,
+		},
+		{
+			name:    "Malformed",
+			header:  "bytes=4-1",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown unit",
+			header:  "items=0-1",
+			wantErr: true,
+		},
+		{
+			name:    "Too many ranges",
+			header:  "bytes=" + strings.Repeat("0-0,", MaxRanges) + "1-1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRange(tt.header, 13)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange() error = %v, wantErr %v",
+					err, tt.wantErr)
+			}
+
+			if errors.Is(err, ErrRangeNotSatisfiable) != tt.wantNot {
+				t.Errorf("ParseRange() error = %v, not satisfiable %v",
+					err, tt.wantNot)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestServeRanges tests the ServeRanges function
+// with the full content and a single range.
+func TestServeRanges(t *testing.T) {
+	content := strings.NewReader("Hello, World!")
+
+	tests := []struct {
+		name       string
+		rangeValue string
+		wantStatus int
+		wantBody   string
+		wantRange  string
+	}{
+		{
+			name:       "Full content",
+			wantStatus: StatusOK,
+			wantBody:   "Hello, World!",
+		},
+		{
+			name:       "Malformed range",
+			rangeValue: "bytes=x-y",
+			wantStatus: StatusOK,
+			wantBody:   "Hello, World!",
+		},
+		{
+			name:       "Single range",
+			rangeValue: "bytes=7-11",
+			wantStatus: StatusPartialContent,
+			wantBody:   "World",
+			wantRange:  "bytes 7-11/13",
+		},
+		{
+			name:       "Not satisfiable",
+			rangeValue: "bytes=20-",
+			wantStatus: StatusRequestedRangeNotSatisfiable,
+			wantRange:  "bytes */13",
+		},
+		{
+			name:       "Overlapping ranges",
+			rangeValue: "bytes=0-,0-,0-",
+			wantStatus: StatusOK,
+			wantBody:   "Hello, World!",
+		},
+		{
+			name:       "Too many ranges",
+			rangeValue: "bytes=" + strings.Repeat("0-0,", MaxRanges) + "1-1",
+			wantStatus: StatusOK,
+			wantBody:   "Hello, World!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.rangeValue != "" {
+				req.Header.Set(HeaderRange, tt.rangeValue)
+			}
+
+			w := httptest.NewRecorder()
+			if err := ServeRanges(w, req, content, 13); err != nil {
+				t.Fatalf("ServeRanges() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+
+			if got := w.Header().Get(HeaderContentRange); got != tt.wantRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantRange)
+			}
+
+			if got := w.Header().Get(HeaderAcceptRanges); got != "bytes" {
+				t.Errorf("Accept-Ranges = %q, want %q", got, "bytes")
+			}
+		})
+	}
+}
+
+// TestServeRangesMultipart tests that several ranges are
+// sent as the multipart/byteranges body.
+func TestServeRangesMultipart(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(HeaderRange, "bytes=0-4,7-11")
+
+	w := httptest.NewRecorder()
+	content := strings.NewReader("Hello, World!")
+	err := ServeRanges(w, req, content, 13, AsTextPlain())
+	if err != nil {
+		t.Fatalf("ServeRanges() error = %v", err)
+	}
+
+	if w.Code != StatusPartialContent {
+		t.Errorf("status = %d, want %d", w.Code, StatusPartialContent)
+	}
+
+	length := strconv.Itoa(w.Body.Len())
+	if got := w.Header().Get(HeaderContentLength); got != length {
+		t.Errorf("Content-Length = %q, want %q", got, length)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(
+		w.Header().Get(HeaderContentType))
+	if err != nil || mediaType != MIMEMultipartByteranges {
+		t.Fatalf("Content-Type = %q", w.Header().Get(HeaderContentType))
+	}
+
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	wants := []struct{ body, contentRange string }{
+		{"Hello", "bytes 0-4/13"},
+		{"World", "bytes 7-11/13"},
+	}
+	for _, want := range wants {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+
+		body, _ := io.ReadAll(part)
+		if string(body) != want.body {
+			t.Errorf("part body = %q, want %q", body, want.body)
+		}
+
+		if got := part.Header.Get(HeaderContentRange); got != want.contentRange {
+			t.Errorf("part Content-Range = %q, want %q",
+				got, want.contentRange)
+		}
+
+		if got := part.Header.Get(HeaderContentType); got != MIMETextPlain {
+			t.Errorf("part Content-Type = %q, want %q", got, MIMETextPlain)
+		}
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("NextPart() error = %v, want %v", err, io.EOF)
+	}
+}
+
+// TestServeReaderAsDownloadReaderAt tests the range support
+// of ServeReaderAsDownload for io.ReaderAt readers.
+func TestServeReaderAsDownloadReaderAt(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(HeaderRange, "bytes=7-11")
+
+	// The struct hides the Seek method of the reader.
+	content := struct {
+		io.Reader
+		io.ReaderAt
+	}{strings.NewReader("Hello, World!"), strings.NewReader("Hello, World!")}
+
+	w := httptest.NewRecorder()
+	err := ServeReaderAsDownload(w, "hello.txt", content, 13,
+		WithRangeSupport(req))
+	if err != nil {
+		t.Fatalf("ServeReaderAsDownload() error = %v", err)
+	}
+
+	if w.Code != StatusPartialContent || w.Body.String() != "World" {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,603 @@
+package resp
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a response stored by the Cache middleware.
+type CachedResponse struct {
+	Status   int         // status code
+	Header   http.Header // headers
+	Body     []byte      // body
+	StoredAt time.Time   // time when the response was stored
+	Expires  time.Time   // time when the response expires
+
+	// RequestHeader are the values of the request headers named by the
+	// Vary header of the response, which must match the values of the
+	// request the response is served to.
+	RequestHeader http.Header
+}
+
+// CacheStore stores the responses of the Cache middleware, e.g. in
+// memory (see MemoryCache) or in a shared store for several instances.
+// The implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the response stored with the key, or false if there
+	// is no response or its ttl is over.
+	Get(key string) (*CachedResponse, bool)
+
+	// Set stores the response with the key for the ttl. The ttl may
+	// exceed the lifetime of the response (see Expires) to keep the
+	// stale response (see CacheConfig.StaleWhileRevalidate).
+	Set(key string, res *CachedResponse, ttl time.Duration)
+}
+
+// CacheKeyFunc returns the cache key of the request. The key must
+// differ for the requests with different responses, e.g. include the
+// Accept-Language header if the response depends on it. The empty key
+// means the request isn't cached.
+type CacheKeyFunc func(r *http.Request) string
+
+// CacheConfig controls the Cache middleware.
+type CacheConfig struct {
+	// Store stores the responses. By default
+	// each middleware has its own MemoryCache.
+	Store CacheStore
+
+	// MaxBodySize is the maximum size of the body of a cached response.
+	// The larger responses are sent, but aren't cached. Zero means
+	// no limit.
+	MaxBodySize int64
+
+	// StaleWhileRevalidate is the period after the expiration of a
+	// cached response during which it is still served, immediately and
+	// with the Warning header, while the handler refreshes it in the
+	// background (RFC 5861). Zero means the expired responses are
+	// never served.
+	StaleWhileRevalidate time.Duration
+}
+
+// staleWarning is the Warning header value of the stale
+// responses (RFC 7234, 5.5.1).
+const staleWarning = `110 - "Response is Stale"`
+
+// cacheableStatus are the status codes of the responses
+// that are cacheable by default (RFC 9110, 15.1).
+var cacheableStatus = map[int]bool{
+	StatusOK:                   true,
+	StatusNonAuthoritativeInfo: true,
+	StatusNoContent:            true,
+	StatusMultipleChoices:      true,
+	StatusMovedPermanently:     true,
+	StatusPermanentRedirect:    true,
+	StatusNotFound:             true,
+	StatusMethodNotAllowed:     true,
+	StatusGone:                 true,
+	StatusRequestURITooLong:    true,
+	StatusNotImplemented:       true,
+}
+
+// Cache returns a middleware that caches the responses of the GET
+// requests for the ttl and serves them to the GET and HEAD requests
+// with the same key (see CacheKeyFunc; by default the host and the
+// URL of the request), e.g. for expensive read-only endpoints. The
+// status code, the headers and the body are stored, and the Age header
+// is set for the cached responses. The conditional requests are
+// revalidated against the ETag and Last-Modified headers of the cached
+// response, so the 304 (Not Modified) response is sent if the client
+// has its actual copy.
+//
+// If the StaleWhileRevalidate period of the config is set, the expired
+// response is served within it with the Warning header, and the request
+// is repeated in the background (once per key at a time, detached from
+// the client's context) to store the fresh response, so the
+// latency-sensitive endpoints never wait for the handler once the
+// response is cached.
+//
+// The response with the Vary header (e.g. set by CORS, Negotiate or
+// Compress) is served only to the requests with the same values of the
+// request headers it names, and each of these variants is stored
+// separately.
+//
+// Only the responses with the cacheable status codes (e.g. 200, 404)
+// are stored, and the responses with the Set-Cookie header, the
+// "Vary: *" header, or the no-store, no-cache or private directives of
+// the Cache-Control header are not. The requests with the Authorization
+// header are neither served from the cache nor their responses stored,
+// unless the response has the public, s-maxage or must-revalidate
+// directive (RFC 9111, 3.5). The response is sent to the client
+// while it is written, so the first request isn't delayed.
+//
+// Example Usage:
+//
+//	byLang := func(r *http.Request) string {
+//	    return r.URL.RequestURI() + "|" + r.Header.Get("Accept-Language")
+//	}
+//
+//	cache := resp.Cache(time.Minute, byLang,
+//	    resp.CacheConfig{MaxBodySize: 1 << 20})
+//	mux.Handle("/reports/", cache(reportsHandler))
+func Cache(
+	ttl time.Duration,
+	keyFunc CacheKeyFunc,
+	config ...CacheConfig,
+) func(http.Handler) http.Handler {
+	var cfg CacheConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryCache()
+	}
+
+	if keyFunc == nil {
+		keyFunc = defaultCacheKey
+	}
+
+	// The responses are kept in the store for the stale period too.
+	storeTTL := ttl + max(cfg.StaleWhileRevalidate, 0)
+	// The response is stored with the key, to be found by the requests
+	// that don't know its Vary header yet, and with the key of its
+	// variant, to be found if the key holds another variant.
+	store := func(key string, r *http.Request, cw *cacheWriter) {
+		res := cw.response()
+		res.Expires = res.StoredAt.Add(ttl)
+
+		res.RequestHeader = varyRequestHeader(r, res.Header)
+
+		cfg.Store.Set(key, res, storeTTL)
+		variant := varyKey(r, res.Header)
+		if variant != "" {
+			cfg.Store.Set(key+variant, res, storeTTL)
+		}
+	}
+
+	// lookup returns the stored response of the key
+	// that can be served to the request.
+	lookup := func(key string, r *http.Request) (*CachedResponse, bool) {
+		res, ok := cfg.Store.Get(key)
+		if ok && !varyMatch(r, res) {
+			res, ok = cfg.Store.Get(key + varyKey(r, res.Header))
+			ok = ok && varyMatch(r, res)
+		}
+
+		return res, ok && sharedFor(r, res.Header)
+	}
+
+	var (
+		mu         sync.Mutex
+		refreshing = make(map[string]bool)
+	)
+
+	// refresh repeats the request in the background and stores the
+	// response, unless the key is already being refreshed.
+	refresh := func(next http.Handler, key string, r *http.Request) {
+		mu.Lock()
+		if refreshing[key] {
+			mu.Unlock()
+			return
+		}
+		refreshing[key] = true
+		mu.Unlock()
+
+		req := r.Clone(context.WithoutCancel(r.Context()))
+		req.Method = http.MethodGet
+		req.Header.Del(HeaderIfNoneMatch)
+		req.Header.Del(HeaderIfModifiedSince)
+
+		go func() {
+			defer func() {
+				// The panic of the handler must not crash the server:
+				// the stale response is served until the next refresh.
+				recover()
+
+				mu.Lock()
+				delete(refreshing, key)
+				mu.Unlock()
+			}()
+
+			cw := &cacheWriter{
+				ResponseWriter: &discardWriter{header: make(http.Header)},
+				max:            cfg.MaxBodySize,
+			}
+			next.ServeHTTP(cw, req)
+			if cw.cacheable() && sharedFor(req, cw.header) {
+				store(key, req, cw)
+			}
+		}()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet &&
+					r.Method != http.MethodHead {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				key := keyFunc(r)
+				if key == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				if res, ok := lookup(key, r); ok {
+					now := time.Now()
+					if now.Before(res.Expires) {
+						serveCached(w, r, res)
+						return
+					}
+
+					stale := res.Expires.Add(cfg.StaleWhileRevalidate)
+					if now.Before(stale) {
+						refresh(next, key, r)
+						w.Header().Set(HeaderWarning, staleWarning)
+						serveCached(w, r, res)
+						return
+					}
+				}
+
+				cw := &cacheWriter{ResponseWriter: w, max: cfg.MaxBodySize}
+				next.ServeHTTP(cw, r)
+				if r.Method == http.MethodGet && cw.cacheable() &&
+					sharedFor(r, cw.header) {
+					store(key, r, cw)
+				}
+			})
+	}
+}
+
+// sharedFor reports whether the response with the header may be stored
+// for or served to the request by a shared cache: the responses to the
+// requests with the Authorization header only if they are explicitly
+// marked as shareable (RFC 9111, 3.5).
+func sharedFor(r *http.Request, header http.Header) bool {
+	if r.Header.Get(HeaderAuthorization) == "" {
+		return true
+	}
+
+	return hasCacheDirective(header, "public", "s-maxage", "must-revalidate")
+}
+
+// varyRequestHeader returns the values of the request headers named
+// by the Vary header of the response, or nil if there is no Vary header.
+func varyRequestHeader(r *http.Request, header http.Header) http.Header {
+	names := varyNames(header)
+	if len(names) == 0 {
+		return nil
+	}
+
+	values := make(http.Header, len(names))
+	for _, name := range names {
+		values[name] = r.Header.Values(name)
+	}
+
+	return values
+}
+
+// varyMatch reports whether the request has the same values of the
+// request headers named by the Vary header as the stored response.
+func varyMatch(r *http.Request, res *CachedResponse) bool {
+	for _, name := range varyNames(res.Header) {
+		got := strings.Join(r.Header.Values(name), ",")
+		if got != strings.Join(res.RequestHeader.Values(name), ",") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// defaultCacheKey returns the host and the URL of the request.
+func defaultCacheKey(r *http.Request) string {
+	return r.Host + r.URL.RequestURI()
+}
+
+// serveCached sends the cached response, or the 304 (Not Modified)
+// response if the conditional request matches it.
+func serveCached(
+	w http.ResponseWriter,
+	r *http.Request,
+	res *CachedResponse,
+) {
+	age := int64(time.Since(res.StoredAt) / time.Second)
+	w.Header().Set(HeaderAge, strconv.FormatInt(max(age, 0), 10))
+
+	if res.Status == StatusOK && cachedNotModified(r, res.Header) {
+		header := w.Header()
+		for key, values := range res.Header {
+			header[key] = append([]string(nil), values...)
+		}
+
+		header.Del(HeaderContentType)
+		header.Del(HeaderContentLength)
+		header.Del(HeaderContentEncoding)
+		w.WriteHeader(StatusNotModified)
+		return
+	}
+
+	writeStored(w, r, res)
+}
+
+// writeStored sends the status code, the headers and the body
+// (unless the request is HEAD) of the stored response.
+func writeStored(
+	w http.ResponseWriter,
+	r *http.Request,
+	res *CachedResponse,
+) {
+	header := w.Header()
+	for key, values := range res.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	w.WriteHeader(res.Status)
+	if r.Method != http.MethodHead {
+		w.Write(res.Body)
+	}
+}
+
+// cachedNotModified reports whether the conditional request
+// matches the validators of the cached response.
+func cachedNotModified(r *http.Request, header http.Header) bool {
+	if inm := r.Header.Get(HeaderIfNoneMatch); inm != "" {
+		etag := header.Get(HeaderETag)
+		return etag != "" && etagMatch(inm, etag)
+	}
+
+	ims, err := http.ParseTime(r.Header.Get(HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+
+	modtime, err := http.ParseTime(header.Get(HeaderLastModified))
+	return err == nil && !modtime.After(ims)
+}
+
+// hasCacheDirective reports whether the Cache-Control
+// header has any of the directives.
+func hasCacheDirective(header http.Header, names ...string) bool {
+	for _, value := range header.Values(HeaderCacheControl) {
+		for _, d := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(d), "=")
+			for _, n := range names {
+				if strings.EqualFold(name, n) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// cacheWriter is the http.ResponseWriter used by Cache. It sends the
+// response to the client and records it for the cache.
+type cacheWriter struct {
+	http.ResponseWriter
+	max         int64
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+	tooLarge    bool
+}
+
+// WriteHeader records the status code and the headers and sends them.
+func (w *cacheWriter) WriteHeader(code int) {
+	if !w.wroteHeader && (code >= 200 || code == StatusSwitchingProtocols) {
+		w.wroteHeader = true
+		w.status = code
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the data and writes it,
+// sending the status code first if needed.
+func (w *cacheWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(StatusOK)
+	}
+
+	if !w.tooLarge {
+		if w.max > 0 && int64(w.body.Len()+len(p)) > w.max {
+			w.tooLarge = true
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(p)
+		}
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// response returns the recorded response.
+func (w *cacheWriter) response() *CachedResponse {
+	return &CachedResponse{
+		Status:   w.status,
+		Header:   w.header,
+		Body:     w.body.Bytes(),
+		StoredAt: time.Now(),
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter,
+// for use with http.ResponseController.
+func (w *cacheWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// discardWriter is the http.ResponseWriter that discards the response,
+// used by Cache to record the background refreshes.
+type discardWriter struct {
+	header http.Header
+}
+
+// Header returns the headers of the response.
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+// Write discards the data.
+func (w *discardWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// WriteHeader does nothing.
+func (w *discardWriter) WriteHeader(int) {}
+
+// cacheable reports whether the recorded response can be stored.
+func (w *cacheWriter) cacheable() bool {
+	return w.shareable() && cacheableStatus[w.status] &&
+		!hasCacheDirective(w.header, "no-cache")
+}
+
+// shareable reports whether the recorded response is complete and can
+// be sent to other clients, i.e. it isn't personal (the Set-Cookie
+// header, the private or no-store directives) and depends on the
+// request only as described by its Vary header.
+func (w *cacheWriter) shareable() bool {
+	if !w.wroteHeader || w.tooLarge {
+		return false
+	}
+
+	if len(w.header.Values(HeaderSetCookie)) > 0 ||
+		hasCacheDirective(w.header, "no-store", "private") {
+		return false
+	}
+
+	for _, name := range varyNames(w.header) {
+		if name == "*" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DefaultMemoryCacheSize is the default maximum
+// number of the responses stored in MemoryCache.
+const DefaultMemoryCacheSize = 10000
+
+// MemoryCache is the in-memory CacheStore. It is safe for concurrent
+// use. The expired responses are removed when they are requested and
+// periodically when new responses are stored. The number of the stored
+// responses is limited: when the limit is reached, the least recently
+// used response is removed, so the clients can't grow the cache without
+// limit (e.g. by changing the query string).
+type MemoryCache struct {
+	mu        sync.Mutex
+	items     map[string]*list.Element
+	order     *list.List // most recently used first
+	size      int
+	lastSweep time.Time
+}
+
+// memoryCacheItem is the response stored in MemoryCache until the
+// expiration time, which is later than the Expires time of the
+// response if the stale responses are kept.
+type memoryCacheItem struct {
+	key     string
+	res     *CachedResponse
+	expires time.Time
+}
+
+// memoryCacheSweep is the interval between the
+// removals of the expired responses of MemoryCache.
+const memoryCacheSweep = time.Minute
+
+// NewMemoryCache returns the empty in-memory cache store that holds at
+// most size responses (DefaultMemoryCacheSize if the size is omitted or
+// isn't positive).
+func NewMemoryCache(size ...int) *MemoryCache {
+	n := DefaultMemoryCacheSize
+	if len(size) > 0 && size[0] > 0 {
+		n = size[0]
+	}
+
+	return &MemoryCache{
+		items:     make(map[string]*list.Element),
+		order:     list.New(),
+		size:      n,
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the response stored with the key,
+// or false if there is no response or it is expired.
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := e.Value.(*memoryCacheItem)
+	if !time.Now().Before(item.expires) {
+		c.remove(e)
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+	return item.res, true
+}
+
+// Set stores the response with the key for the ttl, removing the least
+// recently used response if the cache is full.
+func (c *MemoryCache) Set(key string, res *CachedResponse, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) >= memoryCacheSweep {
+		for _, e := range c.items {
+			if !now.Before(e.Value.(*memoryCacheItem).expires) {
+				c.remove(e)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if res.Expires.IsZero() {
+		res.Expires = now.Add(ttl)
+	}
+
+	item := &memoryCacheItem{key: key, res: res, expires: now.Add(ttl)}
+	if e, ok := c.items[key]; ok {
+		e.Value = item
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(item)
+	for len(c.items) > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove removes the element of the response from the cache.
+func (c *MemoryCache) remove(e *list.Element) {
+	c.order.Remove(e)
+	delete(c.items, e.Value.(*memoryCacheItem).key)
+}
+
+// Len returns the number of the stored responses,
+// including the expired ones that aren't removed yet.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
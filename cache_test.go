@@ -0,0 +1,363 @@
+package resp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCache tests that the Cache middleware serves
+// the stored responses.
+func TestCache(t *testing.T) {
+	calls := 0
+	h := Cache(time.Minute, nil)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			String(w, fmt.Sprintf("call %d", calls),
+				AddETag(`"v1"`), WithHeader("X-Report", "1"))
+		}))
+
+	send := func(method string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/report?id=1", nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodGet)
+	if w.Body.String() != "call 1" || w.Header().Get(HeaderAge) != "" {
+		t.Fatalf("first response = %q, Age %q",
+			w.Body.String(), w.Header().Get(HeaderAge))
+	}
+
+	w = send(http.MethodGet)
+	if w.Body.String() != "call 1" || w.Header().Get(HeaderAge) != "0" {
+		t.Errorf("cached response = %q, Age %q, want %q, Age 0",
+			w.Body.String(), w.Header().Get(HeaderAge), "call 1")
+	}
+
+	if w.Header().Get("X-Report") != "1" {
+		t.Errorf("cached response lost the X-Report header")
+	}
+
+	w = send(http.MethodHead)
+	if w.Code != StatusOK || w.Body.Len() != 0 {
+		t.Errorf("HEAD response = %d %q, want %d without the body",
+			w.Code, w.Body.String(), StatusOK)
+	}
+
+	w = send(http.MethodGet, HeaderIfNoneMatch, `"v1"`)
+	if w.Code != StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("conditional response = %d %q, want %d",
+			w.Code, w.Body.String(), StatusNotModified)
+	}
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+
+	// The unsafe methods aren't cached.
+	send(http.MethodPost)
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
+
+// TestCache_NotCacheable tests that the responses
+// that must not be stored aren't cached.
+func TestCache_NotCacheable(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter)
+		config  CacheConfig
+	}{
+		{
+			name: "No store",
+			handler: func(w http.ResponseWriter) {
+				String(w, "ok", AddCacheControl("no-store"))
+			},
+		},
+		{
+			name: "Private",
+			handler: func(w http.ResponseWriter) {
+				String(w, "ok", AddCacheControl("private, max-age=60"))
+			},
+		},
+		{
+			name: "Set-Cookie",
+			handler: func(w http.ResponseWriter) {
+				String(w, "ok", WithCookie(&http.Cookie{
+					Name: "session", Value: "1",
+				}))
+			},
+		},
+		{
+			name: "Vary all",
+			handler: func(w http.ResponseWriter) {
+				String(w, "ok", WithHeader(HeaderVary, "*"))
+			},
+		},
+		{
+			name: "Server error",
+			handler: func(w http.ResponseWriter) {
+				Error(w, 500, "failed")
+			},
+		},
+		{
+			name: "Too large",
+			handler: func(w http.ResponseWriter) {
+				String(w, "too large")
+			},
+			config: CacheConfig{MaxBodySize: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryCache()
+			tt.config.Store = store
+			h := Cache(time.Minute, nil, tt.config)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					tt.handler(w)
+				}))
+
+			h.ServeHTTP(httptest.NewRecorder(),
+				httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if store.Len() != 0 {
+				t.Errorf("stored responses = %d, want 0", store.Len())
+			}
+		})
+	}
+}
+
+// TestCache_KeyFunc tests that the requests
+// with the empty key aren't cached.
+func TestCache_KeyFunc(t *testing.T) {
+	store := NewMemoryCache()
+	key := func(r *http.Request) string {
+		return r.URL.Query().Get("lang")
+	}
+
+	h := Cache(time.Minute, key, CacheConfig{Store: store})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			String(w, "ok")
+		}))
+
+	for _, target := range []string{"/?lang=uk", "/?lang=en", "/"} {
+		h.ServeHTTP(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	if store.Len() != 2 {
+		t.Errorf("stored responses = %d, want 2", store.Len())
+	}
+}
+
+// TestCache_Authorization tests that the responses to the authorized
+// requests aren't shared unless they are marked as shareable.
+func TestCache_Authorization(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		want         string
+	}{
+		{"Private by default", "", "anonymous"},
+		{"Public", "public, max-age=60", "Bearer alice"},
+		{"Shared max age", "s-maxage=60", "Bearer alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Cache(time.Minute, nil)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					user := r.Header.Get(HeaderAuthorization)
+					if user == "" {
+						user = "anonymous"
+					}
+
+					if tt.cacheControl != "" {
+						w.Header().Set(HeaderCacheControl, tt.cacheControl)
+					}
+					String(w, user)
+				}))
+
+			req := httptest.NewRequest("GET", "/me", nil)
+			req.Header.Set(HeaderAuthorization, "Bearer alice")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "/me", nil))
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("anonymous body = %q, want %q", got, tt.want)
+			}
+
+			// The stored anonymous response isn't served
+			// to the authorized request.
+			req = httptest.NewRequest("GET", "/me", nil)
+			req.Header.Set(HeaderAuthorization, "Bearer bob")
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if tt.cacheControl == "" && w.Body.String() != "Bearer bob" {
+				t.Errorf("authorized body = %q, want %q",
+					w.Body.String(), "Bearer bob")
+			}
+		})
+	}
+}
+
+// TestCache_Vary tests that the response is served only to the
+// requests with the same values of the headers named by its Vary header.
+func TestCache_Vary(t *testing.T) {
+	calls := 0
+	h := Cache(time.Minute, nil)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set(HeaderVary, "Origin")
+			String(w, r.Header.Get("Origin"))
+		}))
+
+	tests := []struct {
+		origin    string
+		wantCalls int
+	}{
+		{"https://a.com", 1},
+		{"https://b.com", 2},
+		{"https://a.com", 2},
+		{"https://b.com", 2},
+		{"", 3},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/data", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if got := w.Body.String(); got != tt.origin {
+			t.Errorf("Origin %q: body = %q, want %q", tt.origin, got, tt.origin)
+		}
+
+		if calls != tt.wantCalls {
+			t.Errorf("Origin %q: calls = %d, want %d",
+				tt.origin, calls, tt.wantCalls)
+		}
+	}
+}
+
+// TestMemoryCache tests that MemoryCache drops the expired responses.
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache()
+	c.Set("fresh", &CachedResponse{Status: StatusOK}, time.Minute)
+	c.Set("stale", &CachedResponse{Status: StatusOK}, -time.Second)
+
+	if _, ok := c.Get("fresh"); !ok {
+		t.Error("Get(fresh) = false, want true")
+	}
+
+	if _, ok := c.Get("stale"); ok {
+		t.Error("Get(stale) = true, want false")
+	}
+
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+}
+
+// TestCache_StaleWhileRevalidate tests that the expired response is
+// served with the Warning header while it is refreshed in the background.
+func TestCache_StaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int32
+	refreshed := make(chan struct{})
+	store := NewMemoryCache()
+	h := Cache(time.Minute, nil, CacheConfig{
+		Store:                store,
+		StaleWhileRevalidate: time.Minute,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if n > 1 {
+			defer close(refreshed)
+		}
+		String(w, fmt.Sprintf("v%d", n))
+	}))
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+		return w
+	}
+
+	if w := get(); w.Body.String() != "v1" {
+		t.Fatalf("body = %q, want v1", w.Body.String())
+	}
+
+	// The response expired 30 seconds ago.
+	res, _ := store.Get("example.com/report")
+	res.Expires = time.Now().Add(-30 * time.Second)
+
+	w := get()
+	if w.Body.String() != "v1" {
+		t.Errorf("stale body = %q, want v1", w.Body.String())
+	}
+
+	if got := w.Header().Get(HeaderWarning); got != staleWarning {
+		t.Errorf("Warning = %q, want %q", got, staleWarning)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("response isn't refreshed")
+	}
+
+	// The refreshed response is stored after the handler returns.
+	deadline := time.Now().Add(time.Second)
+	for {
+		w = get()
+		if w.Body.String() == "v2" || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if w.Body.String() != "v2" {
+		t.Errorf("refreshed body = %q, want v2", w.Body.String())
+	}
+
+	if got := w.Header().Get(HeaderWarning); got != "" {
+		t.Errorf("Warning = %q, want empty", got)
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("handler calls = %d, want 2", n)
+	}
+}
+
+// TestMemoryCache_Size tests that MemoryCache removes
+// the least recently used response when it is full.
+func TestMemoryCache_Size(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", &CachedResponse{Status: StatusOK}, time.Minute)
+	c.Set("b", &CachedResponse{Status: StatusOK}, time.Minute)
+	c.Get("a")
+	c.Set("c", &CachedResponse{Status: StatusOK}, time.Minute)
+
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := c.Get(key); ok != want {
+			t.Errorf("Get(%s) = %v, want %v", key, ok, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package resp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// APICapabilities describes what a resource supports. It is used by
+// the Capabilities function to answer OPTIONS requests.
+//
+// The Methods, AcceptPatch and AcceptPost fields are sent as the
+// Allow, Accept-Patch and Accept-Post headers. If any of MediaTypes,
+// AuthSchemes or RateLimits is set, the whole document is also sent
+// as a JSON body.
+type APICapabilities struct {
+	// Methods is the list of supported HTTP methods.
+	// OPTIONS is added automatically.
+	Methods []string `json:"methods"`
+
+	// AcceptPatch is the list of media types accepted by PATCH.
+	AcceptPatch []string `json:"accept_patch,omitempty"`
+
+	// AcceptPost is the list of media types accepted by POST.
+	AcceptPost []string `json:"accept_post,omitempty"`
+
+	// MediaTypes is the list of media types the resource can produce.
+	MediaTypes []string `json:"media_types,omitempty"`
+
+	// AuthSchemes is the list of supported authentication schemes,
+	// e.g. "Bearer" or "Basic".
+	AuthSchemes []string `json:"auth_schemes,omitempty"`
+
+	// RateLimits is the list of rate limits applied to the resource.
+	RateLimits []RateLimit `json:"rate_limits,omitempty"`
+}
+
+// RateLimit describes a rate limit applied to a resource.
+type RateLimit struct {
+	Limit  int    `json:"limit"`           // number of requests
+	Period int    `json:"period"`          // window in seconds
+	Scope  string `json:"scope,omitempty"` // e.g. "user" or "ip"
+}
+
+// hasDocument returns true if the capabilities contain data
+// that can't be expressed by headers only.
+func (c APICapabilities) hasDocument() bool {
+	return len(c.MediaTypes) > 0 ||
+		len(c.AuthSchemes) > 0 ||
+		len(c.RateLimits) > 0
+}
+
+// Capabilities answers an OPTIONS request with the capabilities
+// of the resource.
+//
+// It sets the Allow, Accept-Patch and Accept-Post headers. If the
+// capabilities contain media types, auth schemes or rate limits,
+// the document is sent as a JSON body with status 200 OK, otherwise
+// the response is sent with status 204 No Content.
+//
+// Example usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    if r.Method == http.MethodOptions {
+//	        resp.Capabilities(w, resp.APICapabilities{
+//	            Methods:     []string{"GET", "POST"},
+//	            AcceptPost:  []string{resp.MIMEApplicationJSON},
+//	            AuthSchemes: []string{"Bearer"},
+//	        })
+//	        return
+//	    }
+//	    // ...
+//	}
+func Capabilities(
+	w http.ResponseWriter,
+	caps APICapabilities,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).Capabilities(caps)
+}
+
+// Capabilities sends the capabilities of the resource as a response
+// to an OPTIONS request. See the Capabilities function for details.
+func (r *Response) Capabilities(caps APICapabilities) error {
+	methods := make([]string, 0, len(caps.Methods)+1)
+	hasOptions := false
+	for _, m := range caps.Methods {
+		m = strings.ToUpper(m)
+		if m == http.MethodOptions {
+			hasOptions = true
+		}
+		methods = append(methods, m)
+	}
+
+	if !hasOptions {
+		methods = append(methods, http.MethodOptions)
+	}
+	caps.Methods = methods
+
+	r.httpWriter.Header().Set(HeaderAllow, strings.Join(methods, ", "))
+	if len(caps.AcceptPatch) > 0 {
+		r.httpWriter.Header().Set(
+			HeaderAcceptPatch,
+			strings.Join(caps.AcceptPatch, ", "),
+		)
+	}
+
+	if len(caps.AcceptPost) > 0 {
+		r.httpWriter.Header().Set(
+			HeaderAcceptPost,
+			strings.Join(caps.AcceptPost, ", "),
+		)
+	}
+
+	if !caps.hasDocument() {
+		return r.NoContent()
+	}
+
+	return r.JSON(caps)
+}
@@ -0,0 +1,80 @@
+package resp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCapabilities_HeadersOnly tests the Capabilities function
+// when there is nothing to send in the body.
+func TestCapabilities_HeadersOnly(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := Capabilities(w, APICapabilities{
+		Methods:     []string{"get", "PATCH"},
+		AcceptPatch: []string{"application/merge-patch+json"},
+	})
+	if err != nil {
+		t.Fatalf("Capabilities() returned error: %v", err)
+	}
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Capabilities() status = %d, want %d",
+			w.Code, http.StatusNoContent)
+	}
+
+	if got := w.Header().Get(HeaderAllow); got != "GET, PATCH, OPTIONS" {
+		t.Errorf("Capabilities() Allow = %q", got)
+	}
+
+	got := w.Header().Get(HeaderAcceptPatch)
+	if got != "application/merge-patch+json" {
+		t.Errorf("Capabilities() Accept-Patch = %q", got)
+	}
+
+	if got := w.Header().Get(HeaderAcceptPost); got != "" {
+		t.Errorf("Capabilities() unexpected Accept-Post = %q", got)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("Capabilities() unexpected body %q", w.Body.String())
+	}
+}
+
+// TestCapabilities_Document tests the Capabilities function
+// when the JSON document must be sent.
+func TestCapabilities_Document(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := Capabilities(w, APICapabilities{
+		Methods:     []string{"OPTIONS", "POST"},
+		AcceptPost:  []string{MIMEApplicationJSON},
+		MediaTypes:  []string{MIMEApplicationJSON},
+		AuthSchemes: []string{"Bearer"},
+		RateLimits:  []RateLimit{{Limit: 100, Period: 60, Scope: "user"}},
+	})
+	if err != nil {
+		t.Fatalf("Capabilities() returned error: %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Capabilities() status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if got := w.Header().Get(HeaderAllow); got != "OPTIONS, POST" {
+		t.Errorf("Capabilities() Allow = %q", got)
+	}
+
+	var caps APICapabilities
+	if err := json.Unmarshal(w.Body.Bytes(), &caps); err != nil {
+		t.Fatalf("Capabilities() body is not JSON: %v", err)
+	}
+
+	if len(caps.RateLimits) != 1 || caps.RateLimits[0].Limit != 100 {
+		t.Errorf("Capabilities() rate limits = %v", caps.RateLimits)
+	}
+
+	if len(caps.AuthSchemes) != 1 || caps.AuthSchemes[0] != "Bearer" {
+		t.Errorf("Capabilities() auth schemes = %v", caps.AuthSchemes)
+	}
+}
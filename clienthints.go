@@ -0,0 +1,144 @@
+package resp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithClientHints asks the client to send the client hints (RFC 8942)
+// in the next requests: the hints are merged into the Accept-CH header,
+// and into the Vary header (see WithoutAutoVary), since the responses
+// to the requests with the hints depend on them. The hints that aren't
+// valid structured field tokens are skipped.
+//
+// Example Usage:
+//
+//	resp.HTML(w, page, resp.WithClientHints(
+//	    resp.HeaderSecCHUAPlatform,
+//	    resp.HeaderSecCHPrefersColorScheme,
+//	    resp.HeaderDPR))
+//	// Accept-CH: Sec-CH-UA-Platform, Sec-CH-Prefers-Color-Scheme, DPR
+//	// Vary: Sec-CH-UA-Platform, Sec-CH-Prefers-Color-Scheme, DPR
+func WithClientHints(hints ...string) Option {
+	return func(r *Response) *Response {
+		valid := validClientHints(hints)
+		mergeHeaderList(r.httpWriter.Header(), HeaderAcceptCH, valid...)
+		r.varyOn(valid...)
+		return r
+	}
+}
+
+// WithCriticalClientHints asks the client to send the client hints like
+// WithClientHints, and marks them as critical with the Critical-CH
+// header: the client that supports them retries the request at once
+// with the hints, instead of using them only in the next requests.
+//
+// Example Usage:
+//
+//	resp.HTML(w, page,
+//	    resp.WithCriticalClientHints(resp.HeaderSecCHPrefersColorScheme))
+func WithCriticalClientHints(hints ...string) Option {
+	return func(r *Response) *Response {
+		valid := validClientHints(hints)
+		WithClientHints(valid...)(r)
+		mergeHeaderList(r.httpWriter.Header(), HeaderCriticalCH, valid...)
+		return r
+	}
+}
+
+// WithClientHintsDelegation delegates the client hints to the
+// third-party origins (e.g. an image CDN) with the Permissions-Policy
+// header, since the browsers send the hints only to the same origin by
+// default. The hints are also requested with WithClientHints. The
+// origins are the serialized origins, e.g. "https://cdn.example.com".
+//
+// Example Usage:
+//
+//	resp.HTML(w, page, resp.WithClientHintsDelegation(
+//	    []string{"https://cdn.example.com"},
+//	    resp.HeaderDPR, resp.HeaderViewportWidth))
+//	// Accept-CH: DPR, Viewport-Width
+//	// Permissions-Policy: ch-dpr=(self "https://cdn.example.com"),
+//	//     ch-viewport-width=(self "https://cdn.example.com")
+func WithClientHintsDelegation(origins []string, hints ...string) Option {
+	return func(r *Response) *Response {
+		valid := validClientHints(hints)
+		WithClientHints(valid...)(r)
+
+		allowlist := SFInnerList{Items: []SFItem{{Value: SFToken("self")}}}
+		for _, origin := range origins {
+			allowlist.Items = append(allowlist.Items, SFItem{Value: origin})
+		}
+
+		directives := make([]string, 0, len(valid))
+		for _, hint := range valid {
+			directive, err := FormatStructuredField(SFDictionary{{
+				Key:   clientHintFeature(hint),
+				Value: allowlist,
+			}})
+			if err == nil {
+				directives = append(directives, directive)
+			}
+		}
+
+		mergeHeaderList(r.httpWriter.Header(),
+			HeaderPermissionsPolicy, directives...)
+		return r
+	}
+}
+
+// validClientHints returns the hints that are valid structured field
+// tokens, as required by the Accept-CH and the Critical-CH headers.
+func validClientHints(hints []string) []string {
+	valid := make([]string, 0, len(hints))
+	for _, hint := range hints {
+		if hint = strings.TrimSpace(hint); isSFToken(hint) {
+			valid = append(valid, hint)
+		}
+	}
+
+	return valid
+}
+
+// clientHintFeature returns the name of the policy-controlled feature
+// of the client hint, e.g. "ch-ua-platform" for "Sec-CH-UA-Platform".
+func clientHintFeature(hint string) string {
+	name := strings.ToLower(strings.TrimSpace(hint))
+	name = strings.TrimPrefix(name, "sec-")
+	if !strings.HasPrefix(name, "ch-") {
+		name = "ch-" + name
+	}
+
+	return name
+}
+
+// mergeHeaderList merges the items into the comma-separated list
+// of the header. The items that are already present (compared
+// case-insensitively) are skipped.
+func mergeHeaderList(h http.Header, key string, items ...string) {
+	var list []string
+	existing := make(map[string]bool)
+	for _, value := range h.Values(key) {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" && !existing[strings.ToLower(item)] {
+				existing[strings.ToLower(item)] = true
+				list = append(list, item)
+			}
+		}
+	}
+
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || existing[strings.ToLower(item)] {
+			continue
+		}
+
+		existing[strings.ToLower(item)] = true
+		list = append(list, item)
+	}
+
+	if len(list) > 0 {
+		h.Set(key, strings.Join(list, ", "))
+	}
+}
@@ -0,0 +1,83 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWithClientHints tests the client hints options.
+func TestWithClientHints(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{
+			name: "Accept-CH",
+			opts: []Option{
+				WithClientHints(HeaderSecCHUAPlatform, HeaderDPR),
+				WithClientHints("dpr", HeaderWidth),
+			},
+			want: map[string]string{
+				HeaderAcceptCH:          "Sec-CH-UA-Platform, DPR, Width",
+				HeaderVary:              "Sec-Ch-Ua-Platform, Dpr, Width",
+				HeaderCriticalCH:        "",
+				HeaderPermissionsPolicy: "",
+			},
+		},
+		{
+			name: "Critical-CH",
+			opts: []Option{
+				WithClientHints(HeaderDPR),
+				WithCriticalClientHints(HeaderSecCHPrefersColorScheme),
+			},
+			want: map[string]string{
+				HeaderAcceptCH:   "DPR, Sec-CH-Prefers-Color-Scheme",
+				HeaderCriticalCH: "Sec-CH-Prefers-Color-Scheme",
+			},
+		},
+		{
+			name: "Delegation",
+			opts: []Option{
+				WithHeader(HeaderPermissionsPolicy, "geolocation=()"),
+				WithClientHintsDelegation(
+					[]string{"https://cdn.example.com"},
+					HeaderDPR, HeaderSecCHUAPlatform),
+			},
+			want: map[string]string{
+				HeaderAcceptCH: "DPR, Sec-CH-UA-Platform",
+				HeaderPermissionsPolicy: "geolocation=(), " +
+					`ch-dpr=(self "https://cdn.example.com"), ` +
+					`ch-ua-platform=(self "https://cdn.example.com")`,
+			},
+		},
+		{
+			name: "Without auto Vary",
+			opts: []Option{
+				WithoutAutoVary(),
+				WithClientHints(HeaderDPR),
+			},
+			want: map[string]string{
+				HeaderAcceptCH: "DPR",
+				HeaderVary:     "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := String(w, "ok", tt.opts...); err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+
+			for key, want := range tt.want {
+				got := strings.Join(w.Header().Values(key), ", ")
+				if got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package resp
+
+import (
+	"compress/gzip"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressConfig controls the Compress middleware.
+type CompressConfig struct {
+	// Level is the gzip compression level, from gzip.HuffmanOnly to
+	// gzip.BestCompression. Zero (and an invalid level) means
+	// gzip.DefaultCompression.
+	Level int
+
+	// ContentTypes are the media types of the compressed responses.
+	// A type ending with "/" (e.g. "text/") matches all its subtypes,
+	// and a type starting with "+" (e.g. "+json") matches the structured
+	// syntax suffix. By default the text, JSON, XML and JavaScript
+	// responses are compressed (see defaultCompressTypes).
+	ContentTypes []string
+
+	// Levels are the compression levels of the media types (matched
+	// like ContentTypes) that override the Level, e.g. the best
+	// compression for the large exports and the best speed for the
+	// interactive endpoints. The exact type takes precedence over the
+	// suffix and the top-level type. The invalid levels are ignored.
+	Levels map[string]int
+}
+
+// validCompressionLevel reports whether the gzip level is valid.
+func validCompressionLevel(level int) bool {
+	return level >= gzip.HuffmanOnly && level <= gzip.BestCompression
+}
+
+// WithCompressionLevel sets the gzip compression level of the response
+// compressed by the Compress middleware, from gzip.HuffmanOnly to
+// gzip.BestCompression, overriding the levels of the middleware config.
+// The invalid level is ignored. The option has no effect without the
+// Compress middleware or after the headers are sent.
+//
+// Example Usage:
+//
+//	// A large export that is downloaded rarely.
+//	resp.JSON(w, report, resp.WithCompressionLevel(gzip.BestCompression))
+func WithCompressionLevel(level int) Option {
+	return func(r *Response) *Response {
+		cw := findCompressWriter(r.httpWriter)
+		if cw != nil && validCompressionLevel(level) {
+			cw.levelOverride = &level
+		}
+		return r
+	}
+}
+
+// findCompressWriter returns the compressWriter
+// of the writer, or nil if there is none.
+func findCompressWriter(w http.ResponseWriter) *compressWriter {
+	for {
+		switch v := w.(type) {
+		case *compressWriter:
+			return v
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// defaultCompressTypes are the media types compressed by default.
+var defaultCompressTypes = []string{
+	"text/",
+	"+json",
+	"+xml",
+	MIMEApplicationJSON,
+	MIMEApplicationXML,
+	MIMEApplicationJavaScript,
+	"application/x-ndjson",
+	"image/svg+xml",
+}
+
+// gzipPools are the pools of the gzip writers, one per compression
+// level, so the writers (and their large internal state) are reused
+// between the responses.
+var gzipPools [gzip.BestCompression - gzip.HuffmanOnly + 1]sync.Pool
+
+// getGzipWriter returns the pooled gzip writer
+// of the level that writes to the dst.
+func getGzipWriter(dst io.Writer, level int) *gzip.Writer {
+	if gw, ok := gzipPools[level-gzip.HuffmanOnly].Get().(*gzip.Writer); ok {
+		gw.Reset(dst)
+		return gw
+	}
+
+	// The level is valid, so there is no error.
+	gw, _ := gzip.NewWriterLevel(dst, level)
+	return gw
+}
+
+// putGzipWriter returns the closed gzip writer of the level to the pool.
+func putGzipWriter(gw *gzip.Writer, level int) {
+	gw.Reset(io.Discard)
+	gzipPools[level-gzip.HuffmanOnly].Put(gw)
+}
+
+// Compress returns a middleware that compresses the responses with
+// gzip if the client accepts it (see the Accept-Encoding header), e.g.
+// for the JSON APIs and the server-rendered pages. Only the responses
+// of the compressible media types (see CompressConfig.ContentTypes) are
+// compressed, except the HEAD requests, the partial responses, the
+// responses without body and the responses that already have the
+// Content-Encoding header. The Accept-Encoding header is merged into
+// the Vary header of the compressible responses (see WithoutAutoVary),
+// and the Content-Length header is removed from the compressed ones.
+//
+// Only the gzip coding is supported: the brotli coding isn't
+// implemented by the standard library, and the package has no
+// dependency for it.
+//
+// The Content-Digest of the compressed response (see WithContentDigest)
+// is computed over the compressed data (RFC 9530) and sent in the
+// trailers.
+//
+// The compression level is set per response with WithCompressionLevel,
+// or per media type (see CompressConfig.Levels).
+//
+// The gzip writers are pooled. The flushes of the response (e.g. of
+// StreamJSONChan, see WithFlushBatch, or of http.ResponseController)
+// flush the compressed data to the client first, so the streamed
+// elements are delivered promptly instead of waiting in the
+// compression buffer.
+//
+// Example Usage:
+//
+//	handler := resp.Compress(resp.CompressConfig{Level: gzip.BestSpeed})
+//	http.ListenAndServe(":8080", handler(mux))
+func Compress(config ...CompressConfig) func(http.Handler) http.Handler {
+	var cfg CompressConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	if cfg.Level == 0 || !validCompressionLevel(cfg.Level) {
+		cfg.Level = gzip.DefaultCompression
+	}
+
+	if cfg.ContentTypes == nil {
+		cfg.ContentTypes = defaultCompressTypes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				cw := &compressWriter{
+					ResponseWriter: w,
+					config:         &cfg,
+					accepts: r.Method != http.MethodHead &&
+						acceptsGzip(r.Header.Get(HeaderAcceptEncoding)),
+				}
+				defer cw.close()
+
+				next.ServeHTTP(cw, r)
+			})
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding
+// header accepts the gzip coding (RFC 9110, 12.5.3).
+func acceptsGzip(header string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "x-gzip" && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+
+		// The explicit gzip coding overrides the "*".
+		if coding != "*" {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+
+	return accepted
+}
+
+// compressWriter is the http.ResponseWriter used by Compress.
+type compressWriter struct {
+	http.ResponseWriter
+	config      *CompressConfig
+	accepts     bool
+	wroteHeader bool
+	gw          *gzip.Writer
+	level       int
+
+	// levelOverride is the level set with WithCompressionLevel.
+	levelOverride *int
+
+	// noAutoVary is set with WithoutAutoVary.
+	noAutoVary bool
+
+	// digest is the algorithm of the digest of the compressed data
+	// requested with WithContentDigest, and digestHash hashes it.
+	digest     DigestAlgorithm
+	digestHash hash.Hash
+}
+
+// varyOn merges the request headers into the Vary header,
+// unless automatic Vary management is disabled (see Response.varyOn).
+func (w *compressWriter) varyOn(names ...string) {
+	if w.noAutoVary {
+		return
+	}
+	mergeVary(w.ResponseWriter.Header(), names...)
+}
+
+// WriteHeader decides whether the response is compressed
+// and sends the status code with the headers.
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader || (code < 200 && code != StatusSwitchingProtocols) {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.ResponseWriter.Header()
+	if !w.encodable(code, header) {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	w.varyOn(HeaderAcceptEncoding)
+	if w.accepts {
+		header.Del(HeaderContentLength)
+		header.Set(HeaderContentEncoding, "gzip")
+		w.level = w.compressionLevel(header.Get(HeaderContentType))
+
+		var dst io.Writer = w.ResponseWriter
+		if w.digest != "" {
+			w.digestHash = w.digest.newHash()
+			dst = io.MultiWriter(w.ResponseWriter, w.digestHash)
+			header.Add(HeaderTrailer, HeaderContentDigest)
+			header.Add(HeaderTrailer, HeaderDigest)
+		}
+		w.gw = getGzipWriter(dst, w.level)
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// encodable reports whether the response with the status code
+// and the headers is of the compressible kind.
+func (w *compressWriter) encodable(code int, header http.Header) bool {
+	return code != StatusSwitchingProtocols && code != StatusNoContent &&
+		code != StatusNotModified && code != StatusPartialContent &&
+		header.Get(HeaderContentEncoding) == "" &&
+		w.compressible(header.Get(HeaderContentType))
+}
+
+// compresses reports whether the response with the status code
+// and the headers will be compressed when the headers are sent.
+func (w *compressWriter) compresses(code int, header http.Header) bool {
+	return w.accepts && !w.wroteHeader && code >= 200 &&
+		w.encodable(code, header)
+}
+
+// Write writes the data, compressed if needed, sending
+// the status code first if needed.
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		// The type must be detected before the data is compressed.
+		header := w.ResponseWriter.Header()
+		if _, ok := header[HeaderContentType]; !ok {
+			header.Set(HeaderContentType, http.DetectContentType(p))
+		}
+		w.WriteHeader(StatusOK)
+	}
+
+	if w.gw != nil {
+		return w.gw.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// FlushError sends the buffered compressed data and flushes the
+// underlying writer. If the status code isn't sent yet, StatusOK is
+// sent first, so the headers are decided before they reach the client.
+func (w *compressWriter) FlushError() error {
+	if !w.wroteHeader {
+		w.WriteHeader(StatusOK)
+	}
+
+	if w.gw != nil {
+		if err := w.gw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush is like FlushError, for the handlers that use http.Flusher.
+func (w *compressWriter) Flush() {
+	w.FlushError()
+}
+
+// Unwrap returns the original http.ResponseWriter,
+// for use with http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close writes the end of the compressed data, sends its digest in the
+// trailers if it is requested and returns the gzip writer to the pool.
+func (w *compressWriter) close() {
+	if w.gw == nil {
+		return
+	}
+
+	w.gw.Close()
+	putGzipWriter(w.gw, w.level)
+	w.gw = nil
+
+	if w.digestHash != nil {
+		setDigestHeaders(w.ResponseWriter.Header(), w.digest,
+			w.digestHash.Sum(nil))
+	}
+}
+
+// compressionLevel returns the compression level of the response.
+func (w *compressWriter) compressionLevel(contentType string) int {
+	if w.levelOverride != nil {
+		return *w.levelOverride
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	keys := []string{mediaType}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		keys = append(keys, mediaType[i:])
+	}
+	if top, _, ok := strings.Cut(mediaType, "/"); ok {
+		keys = append(keys, top+"/")
+	}
+
+	for _, key := range keys {
+		level, ok := w.config.Levels[key]
+		if ok && validCompressionLevel(level) {
+			return level
+		}
+	}
+
+	return w.config.Level
+}
+
+// compressible reports whether the media type is compressed.
+func (w *compressWriter) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range w.config.ContentTypes {
+		if matchMediaType(mediaType, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchMediaType reports whether the media type matches the
+// type of CompressConfig.ContentTypes: the exact type, the
+// top-level type ("text/") or the suffix ("+json").
+func matchMediaType(mediaType, t string) bool {
+	switch {
+	case strings.HasSuffix(t, "/"):
+		return strings.HasPrefix(mediaType, t)
+	case strings.HasPrefix(t, "+"):
+		return strings.HasSuffix(mediaType, t)
+	}
+
+	return strings.EqualFold(mediaType, t)
+}
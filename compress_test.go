@@ -0,0 +1,309 @@
+package resp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gunzip returns the decompressed data, up to the last flush.
+func gunzip(t *testing.T, data []byte) string {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+
+	got, err := io.ReadAll(gr)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+
+	return string(got)
+}
+
+// TestCompress tests that the Compress middleware compresses
+// the compressible responses accepted by the client.
+func TestCompress(t *testing.T) {
+	body := strings.Repeat(`{"name":"value"}`, 100)
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		contentType    string
+		status         int
+		wantGzip       bool
+		wantVary       bool
+	}{
+		{"JSON", "GET", "gzip, br", MIMEApplicationJSON, 200, true, true},
+		{"Suffix", "GET", "gzip", "application/problem+json", 400, true, true},
+		{"Wildcard", "GET", "*", MIMETextPlain, 200, true, true},
+		{"Not accepted", "GET", "br", MIMEApplicationJSON, 200, false, true},
+		{"Rejected", "GET", "*, gzip;q=0", MIMEApplicationJSON, 200, false, true},
+		{"Image", "GET", "gzip", "image/png", 200, false, false},
+		{"HEAD", "HEAD", "gzip", MIMEApplicationJSON, 200, false, true},
+		{"No content", "GET", "gzip", MIMEApplicationJSON, 204, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compress()(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(HeaderContentType, tt.contentType)
+					w.Header().Set(HeaderContentLength, "1600")
+					w.WriteHeader(tt.status)
+					if tt.status != StatusNoContent {
+						io.WriteString(w, body)
+					}
+				}))
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set(HeaderAcceptEncoding, tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			gzipped := w.Header().Get(HeaderContentEncoding) == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("Content-Encoding = %q, want gzip %v",
+					w.Header().Get(HeaderContentEncoding), tt.wantGzip)
+			}
+
+			vary := w.Header().Get(HeaderVary) == HeaderAcceptEncoding
+			if vary != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding %v",
+					w.Header().Get(HeaderVary), tt.wantVary)
+			}
+
+			if !tt.wantGzip {
+				return
+			}
+
+			if got := w.Header().Get(HeaderContentLength); got != "" {
+				t.Errorf("Content-Length = %q, want empty", got)
+			}
+
+			if got := gunzip(t, w.Body.Bytes()); got != body {
+				t.Errorf("body = %q, want %q", got, body)
+			}
+		})
+	}
+}
+
+// snapshotRecorder is a recorder that
+// records the body sent at each flush.
+type snapshotRecorder struct {
+	*httptest.ResponseRecorder
+	flushed [][]byte
+}
+
+// Flush records the body.
+func (w *snapshotRecorder) Flush() {
+	w.flushed = append(w.flushed, bytes.Clone(w.Body.Bytes()))
+	w.ResponseRecorder.Flush()
+}
+
+// TestCompress_Flush tests that the flushes of the streaming
+// responses send the compressed data immediately.
+func TestCompress_Flush(t *testing.T) {
+	w := &snapshotRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h := Compress()(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ch := make(chan int, 2)
+			ch <- 1
+			ch <- 2
+			close(ch)
+			StreamJSONChan(w, ch)
+		}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(HeaderAcceptEncoding, "gzip")
+	h.ServeHTTP(w, req)
+
+	want := []string{"[", "[1", "[1,2", "[1,2]\n"}
+	if len(w.flushed) != len(want) {
+		t.Fatalf("flushes = %d, want %d", len(w.flushed), len(want))
+	}
+
+	for i, data := range w.flushed {
+		if got := gunzip(t, data); got != want[i] {
+			t.Errorf("flush %d body = %q, want %q", i, got, want[i])
+		}
+	}
+
+	if got := gunzip(t, w.Body.Bytes()); got != "[1,2]\n" {
+		t.Errorf("body = %q, want %q", got, "[1,2]\n")
+	}
+}
+
+// TestWithCompressionLevel tests the compression levels
+// of the response and of the media types.
+func TestWithCompressionLevel(t *testing.T) {
+	config := CompressConfig{
+		Level: gzip.BestSpeed,
+		Levels: map[string]int{
+			"text/":     gzip.HuffmanOnly,
+			"text/csv":  gzip.BestCompression,
+			"+json":     gzip.NoCompression,
+			"text/html": 42,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		opts        []Option
+		want        int
+	}{
+		{"Default", MIMEApplicationJSON, nil, gzip.BestSpeed},
+		{"Top-level type", MIMETextPlain, nil, gzip.HuffmanOnly},
+		{"Exact type", "text/csv; charset=utf-8", nil, gzip.BestCompression},
+		{"Suffix", "application/ld+json", nil, gzip.NoCompression},
+		{"Invalid level", MIMETextHTML, nil, gzip.HuffmanOnly},
+		{
+			"Option",
+			MIMETextPlain,
+			[]Option{WithCompressionLevel(gzip.BestCompression)},
+			gzip.BestCompression,
+		},
+		{
+			"Invalid option",
+			MIMEApplicationJSON,
+			[]Option{WithCompressionLevel(10)},
+			gzip.BestSpeed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var level int
+			h := Compress(config)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					opts := append(tt.opts, AddContentType(tt.contentType))
+					String(w, "data", opts...)
+					level = findCompressWriter(w).level
+				}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(HeaderAcceptEncoding, "gzip")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if level != tt.want {
+				t.Errorf("level = %d, want %d", level, tt.want)
+			}
+
+			if got := gunzip(t, w.Body.Bytes()); got != "data" {
+				t.Errorf("body = %q, want %q", got, "data")
+			}
+		})
+	}
+}
+
+// TestCompress_FlushFirst tests that the flush before the first write
+// sends the headers of the compressed response.
+func TestCompress_FlushFirst(t *testing.T) {
+	h := Compress()(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(HeaderContentType, MIMETextPlain)
+			http.NewResponseController(w).Flush()
+			io.WriteString(w, "event")
+		}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(HeaderAcceptEncoding, "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	// The headers sent to the client, not the ones changed later.
+	sent := w.Result().Header
+	if got := sent.Get(HeaderContentEncoding); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	if got := gunzip(t, w.Body.Bytes()); got != "event" {
+		t.Errorf("body = %q, want %q", got, "event")
+	}
+}
+
+// TestCompress_WithoutAutoVary tests that WithoutAutoVary disables
+// the Vary header of the compressed response.
+func TestCompress_WithoutAutoVary(t *testing.T) {
+	h := Compress()(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			String(w, "data", WithoutAutoVary())
+		}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(HeaderAcceptEncoding, "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if got := w.Header().Get(HeaderContentEncoding); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+
+	if got := w.Header().Get(HeaderVary); got != "" {
+		t.Errorf("Vary = %q, want empty", got)
+	}
+}
+
+// TestCompress_ContentDigest tests that the digest of the compressed
+// response covers the compressed data.
+func TestCompress_ContentDigest(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w http.ResponseWriter) error
+		body  string
+	}{
+		{
+			name: "String",
+			write: func(w http.ResponseWriter) error {
+				return String(w, "hello", WithContentDigest(DigestSHA256))
+			},
+			body: "hello",
+		},
+		{
+			name: "JSON",
+			write: func(w http.ResponseWriter) error {
+				return JSON(w, R{"ok": true}, WithContentDigest(DigestSHA256))
+			},
+			body: "{\"ok\":true}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Compress()(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if err := tt.write(w); err != nil {
+						t.Errorf("write error = %v", err)
+					}
+				}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(HeaderAcceptEncoding, "gzip")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			res := w.Result()
+			if got := gunzip(t, w.Body.Bytes()); got != tt.body {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+
+			if got := res.Header.Get(HeaderContentDigest); got != "" {
+				t.Errorf("Content-Digest header = %q, want none", got)
+			}
+
+			got := res.Trailer.Get(HeaderContentDigest)
+			if want := sha256Digest(w.Body.String()); got != want {
+				t.Errorf("Content-Digest trailer = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package resp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CheckPreconditions evaluates the conditional headers of the request
+// (If-Match, If-Unmodified-Since, If-None-Match and If-Modified-Since,
+// in the order of RFC 9110, 13.2.2) against the current ETag and the
+// modification time of the resource, e.g. before updating a record or
+// sending an API response. It returns true if the response is already
+// sent and the handler must stop: the 304 (Not Modified) response for
+// the GET and HEAD requests whose copy is actual, or the 412
+// (Precondition Failed) error response (see Error) for the failed
+// preconditions, e.g. the lost update of a PUT request.
+//
+// The ETag header is set to the etag (unless it is empty), and the
+// Last-Modified header to the modification time (unless it is zero),
+// so the response sent by the handler has the validators. The etag is
+// quoted if needed like with AddETag, e.g. `"v1"` for "v1" and `W/"v1"`
+// for "W/v1"; the invalid etag is ignored. The resource is assumed to
+// exist, so "*" matches it.
+//
+// Example Usage:
+//
+//	func UpdateUser(w http.ResponseWriter, r *http.Request) {
+//	    user := store.User(id)
+//	    if resp.CheckPreconditions(w, r, user.ETag(), user.UpdatedAt) {
+//	        return // 412: the user was changed by someone else
+//	    }
+//	    // update the user...
+//	}
+func CheckPreconditions(
+	w http.ResponseWriter,
+	r *http.Request,
+	etag string,
+	modtime time.Time,
+	opts ...Option,
+) bool {
+	return NewResponse(w, opts...).CheckPreconditions(r, etag, modtime)
+}
+
+// CheckPreconditions evaluates the conditional headers of the request
+// and sends the 304 or 412 response if needed. See the
+// CheckPreconditions function for details.
+func (r *Response) CheckPreconditions(
+	req *http.Request,
+	etag string,
+	modtime time.Time,
+) bool {
+	header := r.httpWriter.Header()
+	etag = etagValue(etag)
+	if etag != "" {
+		header.Set(HeaderETag, etag)
+	}
+
+	if !isZeroTime(modtime) {
+		header.Set(HeaderLastModified, modtime.UTC().Format(http.TimeFormat))
+	}
+
+	// Step 1 and 2: the preconditions of the state changes.
+	if im := req.Header.Get(HeaderIfMatch); im != "" {
+		if !etagStrongMatch(im, etag) {
+			return r.preconditionFailed()
+		}
+	} else if ius := req.Header.Get(HeaderIfUnmodifiedSince); ius != "" {
+		if modified, ok := modifiedSince(modtime, ius); ok && modified {
+			return r.preconditionFailed()
+		}
+	}
+
+	safe := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	// Step 3 and 4: the validation of the cached copy.
+	if inm := req.Header.Get(HeaderIfNoneMatch); inm != "" {
+		if !etagMatch(inm, etag) {
+			return false
+		}
+
+		if !safe {
+			return r.preconditionFailed()
+		}
+
+		r.finishNotModified()
+		return true
+	}
+
+	ims := req.Header.Get(HeaderIfModifiedSince)
+	if modified, ok := modifiedSince(modtime, ims); safe && ok && !modified {
+		r.finishNotModified()
+		return true
+	}
+
+	return false
+}
+
+// preconditionFailed sends the 412 (Precondition Failed) error
+// response and returns true.
+func (r *Response) preconditionFailed() bool {
+	r.statusCode = StatusPreconditionFailed
+	r.Error(StatusPreconditionFailed, StatusText(StatusPreconditionFailed))
+	return true
+}
+
+// finishNotModified sends the 304 (Not Modified) response.
+func (r *Response) finishNotModified() {
+	err := r.writeNotModified()
+	r.finish(&err)
+}
+
+// modifiedSince reports whether the modification time is after the
+// HTTP date. The ok result is false if the modification time is unknown
+// or the date is invalid, so the condition must be ignored.
+func modifiedSince(modtime time.Time, date string) (modified, ok bool) {
+	t, err := http.ParseTime(date)
+	if err != nil || isZeroTime(modtime) {
+		return false, false
+	}
+
+	// The HTTP dates have a second precision.
+	return modtime.Truncate(time.Second).After(t), true
+}
+
+// etagStrongMatch reports whether the list of the If-Match header
+// matches the etag using the strong comparison: the weak tags
+// never match.
+func etagStrongMatch(list, etag string) bool {
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+
+		if etag != "" && !strings.HasPrefix(etag, "W/") && tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCheckPreconditions tests the CheckPreconditions function.
+func TestCheckPreconditions(t *testing.T) {
+	modtime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	before := modtime.Add(-time.Hour).Format(http.TimeFormat)
+	after := modtime.Add(time.Hour).Format(http.TimeFormat)
+	same := modtime.Format(http.TimeFormat)
+
+	tests := []struct {
+		name       string
+		method     string
+		header     map[string]string
+		etag       string
+		wantDone   bool
+		wantStatus int
+	}{
+		{"No conditions", "GET", nil, `"v1"`, false, StatusOK},
+		{
+			"If-None-Match matches", "GET",
+			map[string]string{HeaderIfNoneMatch: `W/"v1"`},
+			`"v1"`, true, StatusNotModified,
+		},
+		{
+			"If-None-Match differs", "GET",
+			map[string]string{HeaderIfNoneMatch: `"v0"`},
+			`"v1"`, false, StatusOK,
+		},
+		{
+			"If-None-Match on PUT", "PUT",
+			map[string]string{HeaderIfNoneMatch: "*"},
+			`"v1"`, true, StatusPreconditionFailed,
+		},
+		{
+			"If-Modified-Since actual", "GET",
+			map[string]string{HeaderIfModifiedSince: same},
+			"", true, StatusNotModified,
+		},
+		{
+			"If-Modified-Since stale", "GET",
+			map[string]string{HeaderIfModifiedSince: before},
+			"", false, StatusOK,
+		},
+		{
+			"If-None-Match wins over If-Modified-Since", "GET",
+			map[string]string{
+				HeaderIfNoneMatch:     `"v0"`,
+				HeaderIfModifiedSince: after,
+			},
+			`"v1"`, false, StatusOK,
+		},
+		{
+			"If-Match matches", "PUT",
+			map[string]string{HeaderIfMatch: `"v0", "v1"`},
+			`"v1"`, false, StatusOK,
+		},
+		{
+			"If-Match lost update", "PUT",
+			map[string]string{HeaderIfMatch: `"v0"`},
+			`"v1"`, true, StatusPreconditionFailed,
+		},
+		{
+			"If-Match unquoted etag", "PUT",
+			map[string]string{HeaderIfMatch: `"v1"`},
+			"v1", false, StatusOK,
+		},
+		{
+			"If-None-Match unquoted etag", "GET",
+			map[string]string{HeaderIfNoneMatch: `"v1"`},
+			"v1", true, StatusNotModified,
+		},
+		{
+			"If-Match weak tag", "PUT",
+			map[string]string{HeaderIfMatch: `W/"v1"`},
+			`W/"v1"`, true, StatusPreconditionFailed,
+		},
+		{
+			"If-Unmodified-Since passed", "DELETE",
+			map[string]string{HeaderIfUnmodifiedSince: after},
+			"", false, StatusOK,
+		},
+		{
+			"If-Unmodified-Since failed", "DELETE",
+			map[string]string{HeaderIfUnmodifiedSince: before},
+			"", true, StatusPreconditionFailed,
+		},
+		{
+			"If-Unmodified-Since invalid", "DELETE",
+			map[string]string{HeaderIfUnmodifiedSince: "yesterday"},
+			"", false, StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/users/7", nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			done := CheckPreconditions(w, req, tt.etag, modtime)
+			if done != tt.wantDone {
+				t.Fatalf("CheckPreconditions() = %v, want %v",
+					done, tt.wantDone)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Header().Get(HeaderLastModified); got !=
+				"Wed, 01 May 2024 10:00:00 GMT" {
+				t.Errorf("Last-Modified = %q", got)
+			}
+
+			if got := w.Header().Get(HeaderETag); got != etagValue(tt.etag) {
+				t.Errorf("ETag = %q, want %q", got, etagValue(tt.etag))
+			}
+		})
+	}
+}
@@ -16,6 +16,9 @@ const (
 	// MIMETextJavaScript is the MIME type for JavaScript code.
 	MIMETextJavaScript = "text/javascript"
 
+	// MIMETextCSV is the MIME type for comma-separated values.
+	MIMETextCSV = "text/csv"
+
 	// MIMEApplicationXML is the MIME type for XML documents,
 	// typically used for APIs or web services.
 	MIMEApplicationXML = "application/xml"
@@ -33,10 +36,31 @@ const (
 	// MIMEOctetStream is the MIME type for arbitrary binary data.
 	MIMEOctetStream = "application/octet-stream"
 
+	// MIMEApplicationRSSXML is the MIME type for RSS feeds.
+	MIMEApplicationRSSXML = "application/rss+xml"
+
+	// MIMEApplicationAtomXML is the MIME type for Atom feeds.
+	MIMEApplicationAtomXML = "application/atom+xml"
+
+	// MIMEApplicationLDJSON is the MIME type for JSON-LD documents.
+	MIMEApplicationLDJSON = "application/ld+json"
+
+	// MIMEApplicationGzip is the MIME type for gzip-compressed data,
+	// e.g. tar.gz archives.
+	MIMEApplicationGzip = "application/gzip"
+
 	// MIMEMultipartForm is the MIME type for multipart form data,
 	// used for form submissions that include file uploads.
 	MIMEMultipartForm = "multipart/form-data"
 
+	// MIMEMultipartMixedReplace is the MIME type of the multipart
+	// streams where each part replaces the previous one, e.g. MJPEG.
+	MIMEMultipartMixedReplace = "multipart/x-mixed-replace"
+
+	// MIMEMultipartByteranges is the MIME type of the responses
+	// to the Range requests for more than one range.
+	MIMEMultipartByteranges = "multipart/byteranges"
+
 	// MIMETextXMLCharsetUTF8 is the MIME type for XML documents
 	// using UTF-8 character encoding.
 	MIMETextXMLCharsetUTF8 = "text/xml; charset=utf-8"
@@ -53,6 +77,10 @@ const (
 	// code using UTF-8 character encoding.
 	MIMETextJavaScriptCharsetUTF8 = "text/javascript; charset=utf-8"
 
+	// MIMETextCSVCharsetUTF8 is the MIME type for comma-separated values
+	// using UTF-8 character encoding.
+	MIMETextCSVCharsetUTF8 = "text/csv; charset=utf-8"
+
 	// MIMEApplicationXMLCharsetUTF8 is the MIME type for XML documents
 	// using UTF-8 character encoding.
 	MIMEApplicationXMLCharsetUTF8 = "application/xml; charset=utf-8"
@@ -61,6 +89,14 @@ const (
 	// data using UTF-8 character encoding.
 	MIMEApplicationJSONCharsetUTF8 = "application/json; charset=utf-8"
 
+	// MIMEApplicationRSSXMLCharsetUTF8 is the MIME type for RSS feeds
+	// using UTF-8 character encoding.
+	MIMEApplicationRSSXMLCharsetUTF8 = "application/rss+xml; charset=utf-8"
+
+	// MIMEApplicationAtomXMLCharsetUTF8 is the MIME type for Atom feeds
+	// using UTF-8 character encoding.
+	MIMEApplicationAtomXMLCharsetUTF8 = "application/atom+xml; charset=utf-8"
+
 	// MIMEApplicationJavaScriptCharsetUTF8 is the MIME type for JavaScript
 	// code using UTF-8 character encoding.
 	MIMEApplicationJavaScriptCharsetUTF8 = "application/javascript; charset=utf-8"
@@ -118,6 +154,11 @@ const (
 	// of the client hints that the server supports.
 	HeaderAcceptCHLifetime = "Accept-CH-Lifetime"
 
+	// HeaderCriticalCH is the HTTP header that represents the client hints
+	// that the client must send for the response to be correct; the client
+	// retries the request with them (RFC 8942 Critical-CH).
+	HeaderCriticalCH = "Critical-CH"
+
 	// HeaderContentDPR is the HTTP header that represents the device pixel
 	// ratio (DPR) of the client's device.
 	HeaderContentDPR = "Content-DPR"
@@ -144,6 +185,38 @@ const (
 	// versions based on the content width.
 	HeaderWidth = "Width"
 
+	// HeaderSecCHUA is the HTTP header that represents the brands and the
+	// significant versions of the user agent (User-Agent Client Hints).
+	HeaderSecCHUA = "Sec-CH-UA"
+
+	// HeaderSecCHUAMobile is the HTTP header that represents whether the
+	// user agent is on a mobile device.
+	HeaderSecCHUAMobile = "Sec-CH-UA-Mobile"
+
+	// HeaderSecCHUAPlatform is the HTTP header that represents the
+	// platform (operating system) of the user agent.
+	HeaderSecCHUAPlatform = "Sec-CH-UA-Platform"
+
+	// HeaderSecCHUAPlatformVersion is the HTTP header that represents the
+	// version of the platform of the user agent.
+	HeaderSecCHUAPlatformVersion = "Sec-CH-UA-Platform-Version"
+
+	// HeaderSecCHUAModel is the HTTP header that represents the device
+	// model of the user agent.
+	HeaderSecCHUAModel = "Sec-CH-UA-Model"
+
+	// HeaderSecCHUAFullVersionList is the HTTP header that represents the
+	// brands and the full versions of the user agent.
+	HeaderSecCHUAFullVersionList = "Sec-CH-UA-Full-Version-List"
+
+	// HeaderSecCHPrefersColorScheme is the HTTP header that represents the
+	// color scheme preferred by the user, e.g. "dark".
+	HeaderSecCHPrefersColorScheme = "Sec-CH-Prefers-Color-Scheme"
+
+	// HeaderSecCHPrefersReducedMotion is the HTTP header that represents
+	// the user's preference for reduced motion.
+	HeaderSecCHPrefersReducedMotion = "Sec-CH-Prefers-Reduced-Motion"
+
 	// HeaderETag is the HTTP header that represents the entity tag of the
 	// resource, a mechanism for cache validation and conditional requests.
 	HeaderETag = "ETag"
@@ -331,6 +404,23 @@ const (
 	// (HTTP/HTTPS) part of the URL requested by the client.
 	HeaderXUrlScheme = "X-Url-Scheme"
 
+	// HeaderXTotalCount is the HTTP header that represents the total
+	// number of items in a paginated collection.
+	HeaderXTotalCount = "X-Total-Count"
+
+	// HeaderXBodyTruncated is the HTTP trailer that reports whether
+	// the response body is truncated at the size limit.
+	HeaderXBodyTruncated = "X-Body-Truncated"
+
+	// HeaderXAccelRedirect is the nginx HTTP header that represents the
+	// internal location of the file to be served by the proxy.
+	HeaderXAccelRedirect = "X-Accel-Redirect"
+
+	// HeaderXSendfile is the Apache (mod_xsendfile) and lighttpd HTTP
+	// header that represents the path of the file to be served by the
+	// proxy.
+	HeaderXSendfile = "X-Sendfile"
+
 	// HeaderLocation is the HTTP header that represents the URL to
 	// redirect a page to.
 	HeaderLocation = "Location"
@@ -397,6 +487,15 @@ const (
 	// load its resources.
 	HeaderCrossOriginResourcePolicy = "Cross-Origin-Resource-Policy"
 
+	// HeaderCrossOriginOpenerPolicy is the HTTP header that allows a site
+	// to isolate its browsing context group from cross-origin documents.
+	HeaderCrossOriginOpenerPolicy = "Cross-Origin-Opener-Policy"
+
+	// HeaderCrossOriginEmbedderPolicy is the HTTP header that prevents
+	// a document from loading cross-origin resources that don't explicitly
+	// grant the document permission.
+	HeaderCrossOriginEmbedderPolicy = "Cross-Origin-Embedder-Policy"
+
 	// HeaderExpectCT is the HTTP header that represents the policy that allows
 	// sites to opt in to reporting and/or enforcement of Certificate
 	// Transparency requirements.
@@ -481,6 +580,14 @@ const (
 	// present in the trailer of a message encoded with chunked transfer coding.
 	HeaderTrailer = "Trailer"
 
+	// HeaderContentDigest is the HTTP header that represents the digest
+	// of the message content (RFC 9530).
+	HeaderContentDigest = "Content-Digest"
+
+	// HeaderDigest is the HTTP header that represents the digest of the
+	// representation (RFC 3230), obsoleted by Content-Digest.
+	HeaderDigest = "Digest"
+
 	// HeaderTransferEncoding is the HTTP header that represents the form of
 	// encoding used to safely transfer the payload body to the user.
 	HeaderTransferEncoding = "Transfer-Encoding"
@@ -510,6 +617,10 @@ const (
 	// document formats accepted by the server.
 	HeaderAcceptPatch = "Accept-Patch"
 
+	// HeaderAcceptPost is the HTTP header that represents the media
+	// types accepted by the server in a POST request.
+	HeaderAcceptPost = "Accept-Post"
+
 	// HeaderAcceptPushPolicy is the HTTP header that represents the
 	// server's preferences for HTTP/2 server push.
 	HeaderAcceptPushPolicy = "Accept-Push-Policy"
@@ -546,14 +657,32 @@ const (
 	// time the client should wait before making a follow-up request.
 	HeaderRetryAfter = "Retry-After"
 
+	// HeaderSunset is the HTTP header that represents the date at which
+	// the resource is expected to become unresponsive (RFC 8594).
+	HeaderSunset = "Sunset"
+
+	// HeaderDeprecation is the HTTP header that represents the date at
+	// which the resource was (or will be) deprecated (RFC 9745).
+	HeaderDeprecation = "Deprecation"
+
 	// HeaderServerTiming is the HTTP header that represents the server
 	// timing for performance tracking.
 	HeaderServerTiming = "Server-Timing"
 
+	// HeaderPriority is the HTTP header that represents the priority
+	// of the response: its urgency and whether it can be processed
+	// incrementally (RFC 9218).
+	HeaderPriority = "Priority"
+
 	// HeaderSignature is the HTTP header that represents the digital
 	// signature for the message content for verification.
 	HeaderSignature = "Signature"
 
+	// HeaderSignatureInput is the HTTP header that represents the
+	// covered components and the parameters of the digital signature
+	// (RFC 9421).
+	HeaderSignatureInput = "Signature-Input"
+
 	// HeaderSignedHeaders is the HTTP header that represents the list
 	// of headers that are included in the digital signature.
 	HeaderSignedHeaders = "Signed-Headers"
@@ -1052,9 +1181,9 @@ var statusMessages = map[int]string{
 	StatusNetworkAuthenticationRequired: "Network Authentication Required",
 }
 
-// singleHeaders is a slice of strings that contains all the HTTP headers that
-// are not lists of values.
-var singleHeaders = []string{
+// singleHeaders is a set of the canonical names of all the HTTP headers
+// that are not lists of values.
+var singleHeaders = headerSet(
 	HeaderContentType,
 	HeaderETag,
 	HeaderLastModified,
@@ -1066,6 +1195,8 @@ var singleHeaders = []string{
 	HeaderDate,
 	HeaderLocation,
 	HeaderRetryAfter,
+	HeaderSunset,
+	HeaderDeprecation,
 	HeaderContentDisposition,
 	HeaderContentEncoding,
 	HeaderContentLanguage,
@@ -1085,9 +1216,33 @@ var singleHeaders = []string{
 	HeaderXContentTypeOptions,
 	HeaderXFrameOptions,
 	HeaderXXSSProtection,
+	HeaderCrossOriginOpenerPolicy,
+	HeaderCrossOriginEmbedderPolicy,
+	HeaderCrossOriginResourcePolicy,
 	HeaderContentDPR,
 	HeaderDPR,
 	HeaderViewportWidth,
 	HeaderWidth,
 	HeaderContentRange,
+	HeaderXTotalCount,
+	HeaderContentDigest,
+	HeaderDigest,
+	HeaderXAccelRedirect,
+	HeaderXSendfile,
+)
+
+// headerSet returns the set of the canonical names of the headers.
+func headerSet(keys ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[http.CanonicalHeaderKey(key)] = struct{}{}
+	}
+
+	return set
+}
+
+// isSingleHeader reports whether the header can contain only one value.
+func isSingleHeader(key string) bool {
+	_, ok := singleHeaders[http.CanonicalHeaderKey(key)]
+	return ok
 }
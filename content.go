@@ -0,0 +1,61 @@
+package resp
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// ServeContent sends the content to the client like http.ServeContent,
+// while still applying the response options: the Range and If-Range
+// headers are handled with the 206 (Partial Content) responses, the
+// conditional requests (If-Match, If-None-Match, If-Modified-Since,
+// If-Unmodified-Since) with the 304 and 412 responses. It is intended
+// for in-memory and object-store content, e.g. a bytes.Reader.
+//
+// If the Content-Type isn't set with the options, it is detected from
+// the extension of the name, or from the content. The modification time
+// is sent as the Last-Modified header unless it is zero; set the ETag
+// header with the AddETag option to use it for conditional requests.
+// The status code is set by http.ServeContent.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    obj, _ := bucket.Get(ctx, key)
+//	    resp.ServeContent(w, r, key, obj.Updated,
+//	        bytes.NewReader(obj.Data), resp.AddETag(obj.ETag))
+//	}
+func ServeContent(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	modtime time.Time,
+	content io.ReadSeeker,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).ServeContent(r, name, modtime, content)
+}
+
+// ServeContent sends the content with the support of the range and
+// conditional requests. See the ServeContent function for details.
+func (r *Response) ServeContent(
+	req *http.Request,
+	name string,
+	modtime time.Time,
+	content io.ReadSeeker,
+) (err error) {
+	defer r.finish(&err)
+
+	r.prepare(StatusOK)
+
+	// The http.ServeContent function sets the status code itself.
+	http.ServeContent(
+		responseWriter{bodyWriter{r}},
+		req,
+		name,
+		modtime,
+		content,
+	)
+	return nil
+}
@@ -0,0 +1,115 @@
+package resp
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestServeContent tests the ServeContent function.
+func TestServeContent(t *testing.T) {
+	modtime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	data := []byte("Hello, World!")
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		opts       []Option
+		wantStatus int
+		wantBody   string
+		wantRange  string
+	}{
+		{
+			name:       "Full content",
+			wantStatus: StatusOK,
+			wantBody:   "Hello, World!",
+		},
+		{
+			name:       "Range",
+			headers:    map[string]string{HeaderRange: "bytes=7-11"},
+			wantStatus: StatusPartialContent,
+			wantBody:   "World",
+			wantRange:  "bytes 7-11/13",
+		},
+		{
+			name: "If-Range mismatch",
+			headers: map[string]string{
+				HeaderRange:   "bytes=7-11",
+				HeaderIfRange: `"old"`,
+			},
+			opts:       []Option{AddETag(`"v2"`)},
+			wantStatus: StatusOK,
+			wantBody:   "Hello, World!",
+		},
+		{
+			name: "Not modified",
+			headers: map[string]string{
+				HeaderIfModifiedSince: modtime.Format(http.TimeFormat),
+			},
+			wantStatus: StatusNotModified,
+		},
+		{
+			name:       "ETag match",
+			headers:    map[string]string{HeaderIfNoneMatch: `"v2"`},
+			opts:       []Option{AddETag(`"v2"`)},
+			wantStatus: StatusNotModified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/hello.txt", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			err := ServeContent(w, r, "hello.txt", modtime,
+				bytes.NewReader(data), tt.opts...)
+			if err != nil {
+				t.Fatalf("ServeContent() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeContent() status = %d, want %d",
+					w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("ServeContent() body = %q, want %q",
+					got, tt.wantBody)
+			}
+
+			got := w.Header().Get(HeaderContentRange)
+			if got != tt.wantRange {
+				t.Errorf("ServeContent() Content-Range = %q, want %q",
+					got, tt.wantRange)
+			}
+		})
+	}
+}
+
+// TestServeContentType tests the detection of the Content-Type.
+func TestServeContentType(t *testing.T) {
+	tests := map[string]string{
+		"data.json": "application/json",
+		"noext":     "text/plain; charset=utf-8",
+	}
+
+	for name, want := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		err := ServeContent(w, r, name, time.Time{},
+			bytes.NewReader([]byte("plain text")))
+		if err != nil {
+			t.Fatalf("ServeContent() error = %v", err)
+		}
+
+		if got := w.Header().Get(HeaderContentType); got != want {
+			t.Errorf("ServeContent(%s) Content-Type = %q, want %q",
+				name, got, want)
+		}
+	}
+}
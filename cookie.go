@@ -0,0 +1,170 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCookieSize is the maximum size of a cookie (the name, the value
+// and the attributes) that all browsers store (RFC 6265, 6.1).
+// The larger cookies are silently dropped by the browsers.
+const MaxCookieSize = 4096
+
+// ErrCookieTooLarge is returned (wrapped) by the response methods when
+// a cookie set with SetCookie or BindCookie (and the options based on
+// them) exceeds MaxCookieSize. Such cookie isn't set; use
+// SetChunkedCookie for the large values.
+var ErrCookieTooLarge = errors.New("cookie too large")
+
+// checkCookie returns true if the cookie fits in MaxCookieSize.
+// Otherwise the error is recorded.
+func (r *Response) checkCookie(cookie *http.Cookie) bool {
+	if size := len(cookie.String()); size > MaxCookieSize {
+		r.headerErr = errors.Join(r.headerErr, fmt.Errorf(
+			"%w: %s is %d bytes, the limit is %d bytes",
+			ErrCookieTooLarge, cookie.Name, size, MaxCookieSize))
+		return false
+	}
+
+	return true
+}
+
+// WithChunkedCookie sets the cookie split into the chunks.
+// See the SetChunkedCookie method for details.
+func WithChunkedCookie(cookie *http.Cookie) Option {
+	return func(r *Response) *Response {
+		return r.SetChunkedCookie(cookie)
+	}
+}
+
+// SetChunkedCookie sets the cookie with the value that may exceed
+// MaxCookieSize, e.g. an encrypted session, as several cookies named
+// "<name>.0", "<name>.1", etc. with the attributes of the cookie. Use
+// ReadChunkedCookie to reassemble the value from the request. The
+// chunks of the cookie already set in the response are replaced, and
+// the chunk following the last one is expired, so the remaining chunks
+// of a larger previous value aren't read.
+//
+// The value must consist of the characters allowed in the cookie
+// values, e.g. be base64-encoded.
+//
+// Example Usage:
+//
+//	response.SetChunkedCookie(&http.Cookie{
+//	    Name:     "session",
+//	    Value:    base64.RawURLEncoding.EncodeToString(sealed),
+//	    Path:     "/",
+//	    Secure:   true,
+//	    HttpOnly: true,
+//	})
+func (r *Response) SetChunkedCookie(cookie *http.Cookie) *Response {
+	r.delChunkedCookie(cookie.Name)
+
+	value, i := cookie.Value, 0
+	for {
+		chunk := *cookie
+		chunk.Name = chunkName(cookie.Name, i)
+		chunk.Value = ""
+
+		size := MaxCookieSize - len(chunk.String())
+		if size <= 0 {
+			r.checkCookie(&chunk)
+			return r
+		}
+
+		n := min(size, len(value))
+		chunk.Value = value[:n]
+		http.SetCookie(r.httpWriter, &chunk)
+
+		value = value[n:]
+		i++
+		if value == "" {
+			break
+		}
+	}
+
+	// The chunk following the last one is expired,
+	// so the chunks of a larger previous value aren't read.
+	http.SetCookie(r.httpWriter, &http.Cookie{
+		Name:    chunkName(cookie.Name, i),
+		Value:   "deleted",
+		Path:    cookie.Path,
+		Domain:  cookie.Domain,
+		Expires: time.Unix(0, 0),
+		MaxAge:  -1,
+		Secure:  cookie.Secure,
+	})
+
+	return r
+}
+
+// delChunkedCookie removes the chunks of the cookie
+// from the Set-Cookie headers of the response.
+func (r *Response) delChunkedCookie(name string) {
+	header := r.httpWriter.Header()
+	values := header.Values(HeaderSetCookie)
+	if len(values) == 0 {
+		return
+	}
+
+	kept := make([]string, 0, len(values))
+	for _, v := range values {
+		index, ok := strings.CutPrefix(setCookieName(v), name+".")
+		if _, err := strconv.Atoi(index); ok && err == nil {
+			continue
+		}
+		kept = append(kept, v)
+	}
+
+	header.Del(HeaderSetCookie)
+	for _, v := range kept {
+		header.Add(HeaderSetCookie, v)
+	}
+}
+
+// ReadChunkedCookie returns the value of the cookie set with
+// SetChunkedCookie, reassembled from the chunks of the request.
+// It returns http.ErrNoCookie if there is no first chunk.
+//
+// Example Usage:
+//
+//	value, err := resp.ReadChunkedCookie(r, "session")
+//	if err != nil {
+//	    // no session
+//	}
+func ReadChunkedCookie(r *http.Request, name string) (string, error) {
+	var sb strings.Builder
+	for i := 0; ; i++ {
+		c, err := r.Cookie(chunkName(name, i))
+		if err != nil {
+			if i == 0 {
+				return "", err
+			}
+			break
+		}
+		sb.WriteString(c.Value)
+	}
+
+	return sb.String(), nil
+}
+
+// chunkName returns the name of the i-th chunk of the cookie.
+func chunkName(name string, i int) string {
+	return name + "." + strconv.Itoa(i)
+}
+
+// setCookieName returns the name of the cookie of the Set-Cookie header
+// value (RFC 6265, 5.2), or the empty string if there is no name.
+func setCookieName(value string) string {
+	pair, _, _ := strings.Cut(value, ";")
+	name, _, ok := strings.Cut(pair, "=")
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(name)
+}
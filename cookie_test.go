@@ -0,0 +1,91 @@
+package resp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSetCookie_TooLarge tests that the cookies larger
+// than MaxCookieSize are rejected.
+func TestSetCookie_TooLarge(t *testing.T) {
+	w := httptest.NewRecorder()
+	large := &http.Cookie{Name: "session", Value: strings.Repeat("a", 5000)}
+	err := String(w, "ok", WithCookie(large))
+	if !errors.Is(err, ErrCookieTooLarge) {
+		t.Errorf("String() error = %v, want %v", err, ErrCookieTooLarge)
+	}
+
+	if got := w.Header().Get(HeaderSetCookie); got != "" {
+		t.Errorf("Set-Cookie = %q, want empty", got)
+	}
+}
+
+// TestSetChunkedCookie tests that the chunked cookie
+// is reassembled by ReadChunkedCookie.
+func TestSetChunkedCookie(t *testing.T) {
+	value := strings.Repeat("0123456789", 1000)
+
+	w := httptest.NewRecorder()
+	err := String(w, "ok",
+		WithCookie(&http.Cookie{Name: "theme", Value: "dark"}),
+		WithChunkedCookie(&http.Cookie{Name: "session", Value: "old"}),
+		WithChunkedCookie(&http.Cookie{
+			Name:     "session",
+			Value:    value,
+			Path:     "/",
+			Secure:   true,
+			HttpOnly: true,
+		}))
+	if err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	names := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		names = append(names, c.Name)
+		if size := len(c.String()); size > MaxCookieSize {
+			t.Errorf("cookie %s is %d bytes", c.Name, size)
+		}
+	}
+
+	want := "theme session.0 session.1 session.2 session.3"
+	if got := strings.Join(names, " "); got != want {
+		t.Fatalf("cookies = %s, want %s", got, want)
+	}
+
+	// The last cookie expires the chunk of a larger previous value.
+	if last := cookies[len(cookies)-1]; last.MaxAge >= 0 {
+		t.Errorf("cookie %s MaxAge = %d, want < 0", last.Name, last.MaxAge)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, c := range cookies[1 : len(cookies)-1] {
+		req.AddCookie(c)
+	}
+	req.AddCookie(&http.Cookie{Name: "session.4", Value: "stale"})
+
+	got, err := ReadChunkedCookie(req, "session")
+	if err != nil {
+		t.Fatalf("ReadChunkedCookie() error = %v", err)
+	}
+
+	if got != value {
+		t.Errorf("ReadChunkedCookie() = %d bytes, want %d bytes",
+			len(got), len(value))
+	}
+}
+
+// TestReadChunkedCookie_Missing tests that ReadChunkedCookie
+// returns http.ErrNoCookie if there is no cookie.
+func TestReadChunkedCookie_Missing(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	_, err := ReadChunkedCookie(req, "session")
+	if !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("ReadChunkedCookie() error = %v, want %v",
+			err, http.ErrNoCookie)
+	}
+}
@@ -0,0 +1,296 @@
+package resp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSConfig represents the Cross-Origin Resource Sharing policy
+// applied by the CORS function and the CORSHandler middleware.
+//
+// Example Usage:
+//
+//	cfg := resp.CORSConfig{
+//	    AllowedOrigins: []string{
+//	        "https://example.com",
+//	        "https://*.example.com",
+//	    },
+//	    AllowedMethods:   []string{"GET", "POST", "DELETE"},
+//	    AllowedHeaders:   []string{"Content-Type", "Authorization"},
+//	    ExposedHeaders:   []string{"X-Request-ID"},
+//	    AllowCredentials: true,
+//	    MaxAge:           600,
+//	}
+//
+//	http.Handle("/api/", resp.CORSHandler(apiHandler, cfg))
+type CORSConfig struct {
+	// AllowedOrigins is the list of origins allowed to make cross-origin
+	// requests. The "*" value allows any origin. A value can contain one
+	// wildcard, e.g. "https://*.example.com" matches any subdomain.
+	// If empty, no origin is allowed.
+	AllowedOrigins []string
+
+	// AllowedMethods is the list of methods allowed in cross-origin
+	// requests. If empty, GET, HEAD and POST are allowed.
+	AllowedMethods []string
+
+	// AllowedHeaders is the list of request headers allowed in
+	// cross-origin requests. The "*" value allows any header.
+	AllowedHeaders []string
+
+	// ExposedHeaders is the list of response headers that the browser
+	// makes available to the client script.
+	ExposedHeaders []string
+
+	// AllowCredentials allows requests with credentials (cookies,
+	// authorization headers or TLS client certificates). The credentials
+	// are allowed only for the origins matched by the patterns other
+	// than "*", so any site can't read the responses with the
+	// credentials of the user.
+	AllowCredentials bool
+
+	// MaxAge is the number of seconds the result of a preflight
+	// request can be cached. Zero means the header isn't sent.
+	MaxAge int
+
+	// AllowPrivateNetwork allows requests from public websites to
+	// the private network (Private Network Access preflight).
+	AllowPrivateNetwork bool
+}
+
+// defaultCORSMethods is the list of methods allowed when
+// CORSConfig.AllowedMethods is empty.
+var defaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+}
+
+// CORS applies the CORS policy to the response.
+//
+// For a preflight request (OPTIONS with Access-Control-Request-Method
+// header) it writes the complete response with status 204 No Content
+// and returns true, so the handler must return immediately. For any
+// other request it only sets the CORS headers and returns false.
+//
+// If the origin, method or headers of the request aren't allowed,
+// the CORS headers are not set and the browser will block the request.
+//
+// The Origin (and for preflight requests, Access-Control-Request-*)
+// headers are merged into the Vary header unless the WithoutAutoVary
+// option is passed.
+//
+// Example usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    if resp.CORS(w, r, cfg) {
+//	        return // preflight request is answered
+//	    }
+//	    resp.JSON(w, data)
+//	}
+func CORS(
+	w http.ResponseWriter,
+	r *http.Request,
+	cfg CORSConfig,
+	opts ...Option,
+) bool {
+	response := NewResponse(w, opts...)
+	h := w.Header()
+	origin := r.Header.Get(HeaderOrigin)
+	preflight := r.Method == http.MethodOptions &&
+		r.Header.Get(HeaderAccessControlRequestMethod) != ""
+
+	if preflight {
+		response.varyOn(
+			HeaderOrigin,
+			HeaderAccessControlRequestMethod,
+			HeaderAccessControlRequestHeaders,
+		)
+
+		if origin != "" && cfg.allowPreflight(r) {
+			cfg.setOrigin(h, origin)
+			cfg.setPreflight(h, r)
+		}
+
+		response.NoContent()
+		return true
+	}
+
+	if origin == "" {
+		return false
+	}
+
+	if !cfg.allowAnyOrigin() || cfg.AllowCredentials {
+		response.varyOn(HeaderOrigin)
+	}
+
+	if cfg.allowOrigin(origin) {
+		cfg.setOrigin(h, origin)
+		if len(cfg.ExposedHeaders) > 0 {
+			h.Set(
+				HeaderAccessControlExposeHeaders,
+				strings.Join(cfg.ExposedHeaders, ", "),
+			)
+		}
+	}
+
+	return false
+}
+
+// CORSHandler returns a middleware that applies the CORS policy to
+// every request. Preflight requests are answered by the middleware
+// and never reach the next handler. The options are passed to CORS.
+func CORSHandler(
+	next http.Handler,
+	cfg CORSConfig,
+	opts ...Option,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CORS(w, r, cfg, opts...) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowAnyOrigin returns true if any origin is allowed.
+func (c CORSConfig) allowAnyOrigin() bool {
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOrigin returns true if the origin is allowed.
+func (c CORSConfig) allowOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range c.AllowedOrigins {
+		if matchOrigin(strings.ToLower(pattern), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowMethod returns true if the method is allowed.
+func (c CORSConfig) allowMethod(method string) bool {
+	methods := c.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowHeaders returns true if all headers of the
+// comma-separated list are allowed.
+func (c CORSConfig) allowHeaders(list string) bool {
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		allowed := false
+		for _, h := range c.AllowedHeaders {
+			if h == "*" || strings.EqualFold(h, name) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// allowPreflight returns true if the preflight request is allowed.
+func (c CORSConfig) allowPreflight(r *http.Request) bool {
+	return c.allowOrigin(r.Header.Get(HeaderOrigin)) &&
+		c.allowMethod(r.Header.Get(HeaderAccessControlRequestMethod)) &&
+		c.allowHeaders(r.Header.Get(HeaderAccessControlRequestHeaders))
+}
+
+// setOrigin sets the Access-Control-Allow-Origin and
+// Access-Control-Allow-Credentials headers.
+func (c CORSConfig) setOrigin(h http.Header, origin string) {
+	// The "*" value is forbidden for requests with credentials, so the
+	// origin of the request is echoed, but only if it is listed.
+	if c.AllowCredentials && c.listOrigin(origin) {
+		h.Set(HeaderAccessControlAllowOrigin, origin)
+		h.Set(HeaderAccessControlAllowCredentials, "true")
+		return
+	}
+
+	if c.allowAnyOrigin() {
+		h.Set(HeaderAccessControlAllowOrigin, "*")
+	} else {
+		h.Set(HeaderAccessControlAllowOrigin, origin)
+	}
+}
+
+// listOrigin returns true if the origin is matched
+// by a pattern other than "*".
+func (c CORSConfig) listOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range c.AllowedOrigins {
+		if pattern != "*" && matchOrigin(strings.ToLower(pattern), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// setPreflight sets the headers specific to the preflight response.
+func (c CORSConfig) setPreflight(h http.Header, r *http.Request) {
+	methods := c.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	h.Set(HeaderAccessControlAllowMethods, strings.Join(methods, ", "))
+
+	// The requested headers are already validated, so they
+	// can be echoed, which also handles the "*" configuration.
+	requested := r.Header.Get(HeaderAccessControlRequestHeaders)
+	if requested != "" {
+		h.Set(HeaderAccessControlAllowHeaders, requested)
+	}
+
+	if c.MaxAge > 0 {
+		h.Set(HeaderAccessControlMaxAge, strconv.Itoa(c.MaxAge))
+	}
+
+	if c.AllowPrivateNetwork &&
+		r.Header.Get(HeaderAccessControlRequestPrivateNetwork) == "true" {
+		h.Set(HeaderAccessControlAllowPrivateNetwork, "true")
+	}
+}
+
+// matchOrigin returns true if the origin matches the pattern.
+// The pattern can be "*", an exact origin or an origin with one
+// wildcard, e.g. "https://*.example.com".
+func matchOrigin(pattern, origin string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+
+	i := strings.IndexByte(pattern, '*')
+	if i < 0 {
+		return false
+	}
+
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
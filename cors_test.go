@@ -0,0 +1,259 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newCORSRequest creates a new request with the Origin header.
+func newCORSRequest(method, origin string) *http.Request {
+	r := httptest.NewRequest(method, "/", nil)
+	if origin != "" {
+		r.Header.Set(HeaderOrigin, origin)
+	}
+	return r
+}
+
+// TestCORS_Simple tests the CORS function for a simple request.
+func TestCORS_Simple(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins: []string{"https://example.com"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	}
+
+	w := httptest.NewRecorder()
+	r := newCORSRequest(http.MethodGet, "https://example.com")
+	if CORS(w, r, cfg) {
+		t.Fatal("CORS() returned true for a simple request")
+	}
+
+	h := w.Header()
+	if got := h.Get(HeaderAccessControlAllowOrigin); got != "https://example.com" {
+		t.Errorf("CORS() Allow-Origin = %q", got)
+	}
+
+	if got := h.Get(HeaderAccessControlExposeHeaders); got != "X-Request-ID" {
+		t.Errorf("CORS() Expose-Headers = %q", got)
+	}
+
+	if got := h.Get(HeaderVary); got != HeaderOrigin {
+		t.Errorf("CORS() Vary = %q", got)
+	}
+}
+
+// TestCORS_Disallowed tests the CORS function for a disallowed origin.
+func TestCORS_Disallowed(t *testing.T) {
+	cfg := CORSConfig{AllowedOrigins: []string{"https://example.com"}}
+
+	w := httptest.NewRecorder()
+	r := newCORSRequest(http.MethodGet, "https://evil.com")
+	CORS(w, r, cfg)
+
+	if got := w.Header().Get(HeaderAccessControlAllowOrigin); got != "" {
+		t.Errorf("CORS() unexpected Allow-Origin = %q", got)
+	}
+
+	// The response still depends on the origin.
+	if got := w.Header().Get(HeaderVary); got != HeaderOrigin {
+		t.Errorf("CORS() Vary = %q", got)
+	}
+}
+
+// TestCORS_AnyOrigin tests the "*" origin with and without credentials.
+func TestCORS_AnyOrigin(t *testing.T) {
+	cfg := CORSConfig{AllowedOrigins: []string{"*"}}
+
+	w := httptest.NewRecorder()
+	CORS(w, newCORSRequest(http.MethodGet, "https://a.com"), cfg)
+	if got := w.Header().Get(HeaderAccessControlAllowOrigin); got != "*" {
+		t.Errorf("CORS() Allow-Origin = %q, want *", got)
+	}
+
+	if got := w.Header().Get(HeaderVary); got != "" {
+		t.Errorf("CORS() unexpected Vary = %q", got)
+	}
+
+	// The credentials are allowed only for the listed origins.
+	cfg.AllowedOrigins = []string{"*", "https://a.com"}
+	cfg.AllowCredentials = true
+	tests := []struct {
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+	}{
+		{"https://a.com", "https://a.com", "true"},
+		{"https://evil.com", "*", ""},
+	}
+
+	for _, tt := range tests {
+		w = httptest.NewRecorder()
+		CORS(w, newCORSRequest(http.MethodGet, tt.origin), cfg)
+		h := w.Header()
+		if got := h.Get(HeaderAccessControlAllowOrigin); got != tt.wantOrigin {
+			t.Errorf("CORS(%s) Allow-Origin = %q, want %q",
+				tt.origin, got, tt.wantOrigin)
+		}
+
+		got := h.Get(HeaderAccessControlAllowCredentials)
+		if got != tt.wantCredentials {
+			t.Errorf("CORS(%s) Allow-Credentials = %q, want %q",
+				tt.origin, got, tt.wantCredentials)
+		}
+	}
+}
+
+// TestCORS_Preflight tests the CORS function for a preflight request.
+func TestCORS_Preflight(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins:      []string{"https://*.example.com"},
+		AllowedMethods:      []string{"GET", "PUT"},
+		AllowedHeaders:      []string{"Content-Type", "Authorization"},
+		MaxAge:              600,
+		AllowPrivateNetwork: true,
+	}
+
+	w := httptest.NewRecorder()
+	r := newCORSRequest(http.MethodOptions, "https://app.example.com")
+	r.Header.Set(HeaderAccessControlRequestMethod, "PUT")
+	r.Header.Set(HeaderAccessControlRequestHeaders, "content-type")
+	r.Header.Set(HeaderAccessControlRequestPrivateNetwork, "true")
+
+	if !CORS(w, r, cfg) {
+		t.Fatal("CORS() returned false for a preflight request")
+	}
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("CORS() status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	tests := map[string]string{
+		HeaderAccessControlAllowOrigin:         "https://app.example.com",
+		HeaderAccessControlAllowMethods:        "GET, PUT",
+		HeaderAccessControlAllowHeaders:        "content-type",
+		HeaderAccessControlMaxAge:              "600",
+		HeaderAccessControlAllowPrivateNetwork: "true",
+	}
+
+	for key, want := range tests {
+		if got := w.Header().Get(key); got != want {
+			t.Errorf("CORS() %s = %q, want %q", key, got, want)
+		}
+	}
+
+	if n := len(w.Header().Values(HeaderVary)); n != 3 {
+		t.Errorf("CORS() Vary has %d values, want 3", n)
+	}
+}
+
+// TestCORS_PreflightRejected tests the preflight request
+// with a disallowed method or header.
+func TestCORS_PreflightRejected(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		headers string
+	}{
+		{"method", http.MethodDelete, ""},
+		{"headers", http.MethodGet, "X-Secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newCORSRequest(http.MethodOptions, "https://example.com")
+			r.Header.Set(HeaderAccessControlRequestMethod, tt.method)
+			if tt.headers != "" {
+				r.Header.Set(HeaderAccessControlRequestHeaders, tt.headers)
+			}
+
+			if !CORS(w, r, cfg) {
+				t.Fatal("CORS() returned false for a preflight request")
+			}
+
+			got := w.Header().Get(HeaderAccessControlAllowOrigin)
+			if got != "" {
+				t.Errorf("CORS() unexpected Allow-Origin = %q", got)
+			}
+		})
+	}
+}
+
+// TestCORSHandler tests the CORSHandler middleware.
+func TestCORSHandler(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		String(w, "ok")
+	})
+
+	h := CORSHandler(next, CORSConfig{AllowedOrigins: []string{"*"}})
+
+	// Preflight request doesn't reach the handler.
+	w := httptest.NewRecorder()
+	r := newCORSRequest(http.MethodOptions, "https://a.com")
+	r.Header.Set(HeaderAccessControlRequestMethod, "GET")
+	h.ServeHTTP(w, r)
+	if called {
+		t.Error("CORSHandler() called next handler for preflight request")
+	}
+
+	// Actual request reaches the handler.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newCORSRequest(http.MethodGet, "https://a.com"))
+	if !called || w.Body.String() != "ok" {
+		t.Error("CORSHandler() did not call next handler")
+	}
+
+	if got := w.Header().Get(HeaderAccessControlAllowOrigin); got != "*" {
+		t.Errorf("CORSHandler() Allow-Origin = %q", got)
+	}
+}
+
+// TestMatchOrigin tests the matchOrigin function.
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"*", "https://a.com", true},
+		{"https://a.com", "https://a.com", true},
+		{"https://a.com", "https://b.com", false},
+		{"https://*.a.com", "https://x.a.com", true},
+		{"https://*.a.com", "https://x.y.a.com", true},
+		{"https://*.a.com", "https://.a.com", false},
+		{"https://*.a.com", "https://a.com", false},
+		{"https://*.a.com", "http://x.a.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchOrigin(tt.pattern, tt.origin); got != tt.want {
+			t.Errorf("matchOrigin(%q, %q) = %v, want %v",
+				tt.pattern, tt.origin, got, tt.want)
+		}
+	}
+}
+
+// TestCORS_WithoutAutoVary tests that the Vary header isn't set
+// when the WithoutAutoVary option is passed.
+func TestCORS_WithoutAutoVary(t *testing.T) {
+	cfg := CORSConfig{AllowedOrigins: []string{"https://example.com"}}
+
+	w := httptest.NewRecorder()
+	r := newCORSRequest(http.MethodGet, "https://example.com")
+	CORS(w, r, cfg, WithoutAutoVary())
+
+	if got := w.Header().Get(HeaderVary); got != "" {
+		t.Errorf("CORS() unexpected Vary = %q", got)
+	}
+
+	if got := w.Header().Get(HeaderAccessControlAllowOrigin); got == "" {
+		t.Error("CORS() did not set Allow-Origin")
+	}
+}
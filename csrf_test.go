@@ -0,0 +1,91 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSignedCookieCodec tests the signed cookie codec.
+func TestSignedCookieCodec(t *testing.T) {
+	codec := NewSignedCookieCodec([]byte("secret"))
+
+	encoded, err := codec.Encode("name", "value")
+	if err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+
+	decoded, err := codec.Decode("name", encoded)
+	if err != nil || decoded != "value" {
+		t.Errorf("Decode() = %q, %v, want value", decoded, err)
+	}
+
+	// The value can't be moved to another cookie.
+	if _, err := codec.Decode("other", encoded); err == nil {
+		t.Error("Decode() expected error for another cookie name")
+	}
+
+	// The value can't be decoded with another key.
+	other := NewSignedCookieCodec([]byte("other"))
+	if _, err := other.Decode("name", encoded); err == nil {
+		t.Error("Decode() expected error for another key")
+	}
+
+	for _, v := range []string{"", "no-dot", "!!!.abc", "dmFsdWU.!!!"} {
+		if _, err := codec.Decode("name", v); err == nil {
+			t.Errorf("Decode(%q) expected error", v)
+		}
+	}
+}
+
+// TestIssueCSRFToken tests the IssueCSRFToken function.
+func TestIssueCSRFToken(t *testing.T) {
+	codec := NewSignedCookieCodec([]byte("secret"))
+
+	w := httptest.NewRecorder()
+	token, err := IssueCSRFToken(w, codec)
+	if err != nil {
+		t.Fatalf("IssueCSRFToken() returned error: %v", err)
+	}
+
+	if len(token) < 40 {
+		t.Errorf("IssueCSRFToken() token is too short: %q", token)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("IssueCSRFToken() set %d cookies", len(cookies))
+	}
+
+	c := cookies[0]
+	if c.Name != CSRFCookieName || !c.HttpOnly || !c.Secure ||
+		c.SameSite != http.SameSiteStrictMode {
+		t.Errorf("IssueCSRFToken() cookie = %+v", c)
+	}
+
+	if got, err := codec.Decode(CSRFCookieName, c.Value); got != token {
+		t.Errorf("IssueCSRFToken() cookie decodes to %q, %v", got, err)
+	}
+
+	// A second token replaces the cookie.
+	resp := NewResponse(w)
+	second, _ := resp.IssueCSRFToken(nil)
+	values := w.Header().Values(HeaderSetCookie)
+	if len(values) != 1 || !strings.Contains(values[0], second) {
+		t.Errorf("IssueCSRFToken() Set-Cookie = %v", values)
+	}
+
+	if second == token {
+		t.Error("IssueCSRFToken() generated the same token twice")
+	}
+}
+
+// TestCSRFMeta tests the CSRFMeta function.
+func TestCSRFMeta(t *testing.T) {
+	got := string(CSRFMeta(`a"b<c`))
+	want := `<meta name="csrf-token" content="a&#34;b&lt;c">`
+	if got != want {
+		t.Errorf("CSRFMeta() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package resp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+)
+
+// CSV sends the records as a CSV document.
+//
+// Each value is converted to text by the LocaleFormatter set with the
+// WithLocaleFormatter option (the zero LocaleFormatter by default),
+// which also defines the field delimiter. The document is written as
+// text, so the WithBOM and WithTextEncoding options are applied too.
+//
+// If the Content-Type header isn't set, "text/csv; charset=utf-8"
+// is used.
+//
+// Example usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    rows := [][]any{
+//	        {"Product", "Price", "Date"},
+//	        {"Coffee", 1234.5, time.Now()},
+//	    }
+//
+//	    err := resp.CSV(w, rows,
+//	        resp.WithLocaleFormatter(resp.LocaleDeDE),
+//	        resp.WithBOM(),
+//	        resp.AddContentDisposition("attachment", "prices.csv"))
+//	    if err != nil {
+//	        log.Printf("Failed to send CSV: %v", err)
+//	    }
+//	}
+func CSV(w http.ResponseWriter, records [][]any, opts ...Option) error {
+	return NewResponse(w, opts...).CSV(records)
+}
+
+// CSV sends the records as a CSV document.
+// If the status code is not set - StatusOK will be set.
+// If ContentType isn't defined - MIMETextCSVCharsetUTF8 will be used.
+func (r *Response) CSV(records [][]any) (err error) {
+	defer r.finish(&err)
+
+	var f LocaleFormatter
+	if r.localeFormatter != nil {
+		f = *r.localeFormatter
+	}
+
+	// The document is built in memory first, so a formatting
+	// error doesn't leave a partially written response.
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.Comma = f.delimiter()
+
+	row := []string{}
+	for _, record := range records {
+		row = row[:0]
+		for _, v := range record {
+			row = append(row, f.Format(v))
+		}
+
+		if err := cw.Write(row); err != nil {
+			return r.encodeError("failed to encode CSV response", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return r.encodeError("failed to encode CSV response", err)
+	}
+
+	r.prepare(StatusOK, MIMETextCSVCharsetUTF8)
+	r.writeHeader(r.statusCode)
+	return r.writeText(&buf)
+}
@@ -0,0 +1,63 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCSV tests the CSV function with the default formatter.
+func TestCSV(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := CSV(w, [][]any{
+		{"name", "price"},
+		{"Coffee, black", 1234.5},
+	})
+	if err != nil {
+		t.Fatalf("CSV() returned error: %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("CSV() status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if got := w.Header().Get(HeaderContentType); got != MIMETextCSVCharsetUTF8 {
+		t.Errorf("CSV() Content-Type = %q", got)
+	}
+
+	want := "name,price\n\"Coffee, black\",1234.5\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("CSV() body = %q, want %q", got, want)
+	}
+}
+
+// TestCSV_Locale tests the CSV function with a locale formatter and BOM.
+func TestCSV_Locale(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := CSV(w, [][]any{{"Coffee", 1234.5}},
+		WithLocaleFormatter(LocaleDeDE), WithBOM())
+	if err != nil {
+		t.Fatalf("CSV() returned error: %v", err)
+	}
+
+	want := "\uFEFFCoffee;1.234,5\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("CSV() body = %q, want %q", got, want)
+	}
+}
+
+// TestCSV_InvalidDelimiter tests that nothing is written
+// when the CSV writer fails.
+func TestCSV_InvalidDelimiter(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := CSV(w, [][]any{{"a"}}, WithLocaleFormatter(LocaleFormatter{
+		Delimiter: '\n',
+	}))
+	if err == nil {
+		t.Error("CSV() expected error for invalid delimiter")
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("CSV() unexpected body %q", w.Body.String())
+	}
+}
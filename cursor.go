@@ -0,0 +1,141 @@
+package resp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DefaultCursorParam is the query parameter of the cursor
+// in the next page link sent by CursorPage.
+const DefaultCursorParam = "cursor"
+
+// ErrInvalidCursor is returned by DecodeCursor
+// if the cursor is malformed.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorMeta is the pagination metadata of the CursorPage envelope.
+type CursorMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// CursorEnvelope is the JSON body sent by CursorPage.
+type CursorEnvelope struct {
+	Data any        `json:"data"`
+	Meta CursorMeta `json:"meta"`
+}
+
+// EncodeCursor encodes the keyset values of the last item of the page
+// (e.g. a struct or a slice with the sort key and the ID) as an opaque
+// cursor: URL-safe base64 of the JSON representation.
+//
+// Example Usage:
+//
+//	last := items[len(items)-1]
+//	next, err := resp.EncodeCursor([]any{last.CreatedAt, last.ID})
+func EncodeCursor(values any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes the cursor created by EncodeCursor into the
+// value pointed to by v. It returns an error wrapping ErrInvalidCursor
+// if the cursor is malformed, which usually should be reported to the
+// client as a bad request.
+//
+// Example Usage:
+//
+//	var key struct {
+//		CreatedAt time.Time
+//		ID        int
+//	}
+//	cursor := r.URL.Query().Get("cursor")
+//	if err := resp.DecodeCursor(cursor, &key); err != nil {
+//	    resp.Error(w, http.StatusBadRequest, "invalid cursor")
+//	    return
+//	}
+func DecodeCursor(cursor string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return nil
+}
+
+// WithCursorBase sets the URL of the collection used to build the next
+// page link of CursorPage; the query parameters of the URL are kept
+// and the cursor parameter is set. By default, the link is the relative
+// reference "?cursor=...".
+func WithCursorBase(base string) Option {
+	return func(r *Response) *Response {
+		r.cursorBase = base
+		return r
+	}
+}
+
+// CursorPage sends the page of the collection as a JSON response
+// in the envelope with the cursor of the next page:
+//
+//	{"data": [...], "meta": {"next_cursor": "WzQyXQ", "has_more": true}}
+//
+// If the next cursor isn't empty, it is also sent in the Link header
+// with the "next" relation (see WithCursorBase). An empty next cursor
+// means that it is the last page.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    items, next := store.ListAfter(cursor, limit)
+//	    resp.CursorPage(w, items, next,
+//	        resp.WithCursorBase(r.URL.String()))
+//	}
+func CursorPage(
+	w http.ResponseWriter,
+	items any,
+	nextCursor string,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).CursorPage(items, nextCursor)
+}
+
+// CursorPage sends the page of the collection with the cursor of the
+// next page. See the CursorPage function for details.
+func (r *Response) CursorPage(items any, nextCursor string) error {
+	if nextCursor != "" {
+		if link, ok := r.cursorLink(nextCursor); ok {
+			AddLink(LinkHeader{URI: link, Rel: "next"})(r)
+		}
+	}
+
+	return r.JSON(CursorEnvelope{
+		Data: items,
+		Meta: CursorMeta{NextCursor: nextCursor, HasMore: nextCursor != ""},
+	})
+}
+
+// cursorLink returns the link of the page with the cursor,
+// and false if the base URL can't be parsed.
+func (r *Response) cursorLink(cursor string) (string, bool) {
+	u, err := url.Parse(r.cursorBase)
+	if err != nil {
+		return "", false
+	}
+
+	query := u.Query()
+	query.Set(DefaultCursorParam, cursor)
+	u.RawQuery = query.Encode()
+	return u.String(), true
+}
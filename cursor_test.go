@@ -0,0 +1,95 @@
+package resp
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestEncodeDecodeCursor tests the round trip of the cursor.
+func TestEncodeDecodeCursor(t *testing.T) {
+	type key struct {
+		Score float64
+		ID    int
+	}
+
+	cursor, err := EncodeCursor(key{Score: 9.5, ID: 42})
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	var got key
+	if err := DecodeCursor(cursor, &got); err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	if got != (key{Score: 9.5, ID: 42}) {
+		t.Errorf("DecodeCursor() = %v", got)
+	}
+
+	if _, err := EncodeCursor(make(chan int)); err == nil {
+		t.Error("EncodeCursor() expected error for channel")
+	}
+}
+
+// TestDecodeCursorInvalid tests the malformed cursors.
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, cursor := range []string{"%%%", "bm90LWpzb24"} {
+		var v []any
+		err := DecodeCursor(cursor, &v)
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor",
+				cursor, err)
+		}
+	}
+}
+
+// TestCursorPage tests the CursorPage function.
+func TestCursorPage(t *testing.T) {
+	tests := []struct {
+		name string
+		next string
+		opts []Option
+		link string
+		body string
+	}{
+		{
+			name: "Relative link",
+			next: "WzQyXQ",
+			link: `<?cursor=WzQyXQ>; rel="next"`,
+			body: `{"data":[1,2],"meta":{"next_cursor":"WzQyXQ",` +
+				`"has_more":true}}` + "\n",
+		},
+		{
+			name: "Base URL",
+			next: "WzQyXQ",
+			opts: []Option{WithCursorBase("/items?limit=2&cursor=old")},
+			link: `</items?cursor=WzQyXQ&limit=2>; rel="next"`,
+			body: `{"data":[1,2],"meta":{"next_cursor":"WzQyXQ",` +
+				`"has_more":true}}` + "\n",
+		},
+		{
+			name: "Last page",
+			link: "",
+			body: `{"data":[1,2],"meta":{"has_more":false}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := CursorPage(w, []int{1, 2}, tt.next, tt.opts...)
+			if err != nil {
+				t.Fatalf("CursorPage() error = %v", err)
+			}
+
+			if got := w.Header().Get(HeaderLink); got != tt.link {
+				t.Errorf("CursorPage() Link = %s, want %s", got, tt.link)
+			}
+
+			if got := w.Body.String(); got != tt.body {
+				t.Errorf("CursorPage() body = %s, want %s", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package resp
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ExcludeFieldsDeep removes the specified fields from the provided data
+// at every depth and returns the result. Unlike ExcludeFields, which
+// copies nested values verbatim, it recurses into nested structs,
+// pointers, maps with string keys, slices, and arrays, so a secret
+// inside a nested struct is removed as well. Structs are converted to
+// `R` maps keyed by the JSON names of the fields, as encoding/json
+// encodes them: the fields tagged with `json:"-"` are skipped, the empty
+// omitempty fields are omitted and the fields of the embedded structs
+// are promoted. The fields are matched by the JSON name or by the name
+// in Go. Slices and arrays are converted to `[]any`.
+// Values with their own JSON representation (e.g. time.Time) and other
+// values are copied unchanged.
+//
+// Example Usage:
+//
+//	type Account struct {
+//		Login    string
+//		Password string
+//	}
+//
+//	type User struct {
+//		ID       int
+//		Password string
+//		Accounts []Account
+//	}
+//
+//	data := resp.ExcludeFieldsDeep(user, "Password")
+//	// {"ID": 1, "Accounts": [{"Login": "go"}]}
+func ExcludeFieldsDeep(data any, fields ...string) any {
+	excluded := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		excluded[field] = true
+	}
+
+	f := deepFilter{excluded: excluded}
+	return filterDeep(reflect.ValueOf(data), f)
+}
+
+// OnlyFieldsDeep extracts only the specified fields from the provided
+// data at every depth and returns the result. The nested fields are
+// specified by dot-separated paths, e.g. "Address.City"; a field
+// specified without the nested path (e.g. "Address") is kept with all
+// its nested fields. The paths are applied to each element of slices
+// and arrays. The result has the same form as for ExcludeFieldsDeep.
+//
+// Example Usage:
+//
+//	data := resp.OnlyFieldsDeep(users, "ID", "Address.City")
+//	// [{"ID": 1, "Address": {"City": "Kyiv"}}, ...]
+func OnlyFieldsDeep(data any, paths ...string) any {
+	f := deepFilter{only: splitPaths(paths)}
+	return filterDeep(reflect.ValueOf(data), f)
+}
+
+// fieldPaths is the tree of the field paths selected by OnlyFieldsDeep.
+// A nil subtree means that the whole field is kept.
+type fieldPaths map[string]fieldPaths
+
+// splitPaths builds the tree of the dot-separated field paths.
+func splitPaths(paths []string) fieldPaths {
+	tree := make(fieldPaths)
+	for _, path := range paths {
+		node := tree
+		parts := strings.Split(path, ".")
+		for i, part := range parts {
+			sub, ok := node[part]
+			if ok && sub == nil {
+				break // the whole field is already kept
+			}
+
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+
+			if !ok {
+				sub = make(fieldPaths)
+				node[part] = sub
+			}
+			node = sub
+		}
+	}
+
+	return tree
+}
+
+// deepFilter selects the fields kept by the deep filtering: either
+// the excluded names (the same at every depth) or the tree of the kept
+// paths is used.
+type deepFilter struct {
+	excluded map[string]bool
+	only     fieldPaths
+}
+
+// field reports whether the field with the name is kept, and whether
+// its value is filtered recursively with the returned filter.
+func (f deepFilter) field(
+	name string,
+) (keep bool, sub deepFilter, deep bool) {
+	if f.only == nil {
+		return !f.excluded[name], f, true
+	}
+
+	paths, ok := f.only[name]
+	return ok, deepFilter{only: paths}, paths != nil
+}
+
+// structField is like field for the struct field, which is matched
+// by its JSON name or by its name in Go.
+func (f deepFilter) structField(
+	field fieldInfo,
+) (keep bool, sub deepFilter, deep bool) {
+	if field.jsonName == field.name {
+		return f.field(field.name)
+	}
+
+	if f.only == nil {
+		keep = !f.excluded[field.jsonName] && !f.excluded[field.name]
+		return keep, f, true
+	}
+
+	if keep, sub, deep = f.field(field.jsonName); keep {
+		return keep, sub, deep
+	}
+
+	return f.field(field.name)
+}
+
+// filterDeep recursively filters the fields of the value.
+func filterDeep(rv reflect.Value, f deepFilter) any {
+	if !rv.IsValid() {
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if rv.IsNil() || isMarshaler(rv) {
+			return rv.Interface()
+		}
+		return filterDeep(rv.Elem(), f)
+	case reflect.Struct:
+		if isMarshaler(rv) {
+			break
+		}
+
+		info := cachedStructInfo(rv.Type())
+		result := make(R, len(info.flat))
+		for _, field := range info.flat {
+			if field.jsonSkip {
+				continue
+			}
+
+			ok, sub, deep := f.structField(field)
+			if !ok {
+				continue
+			}
+
+			value, ok := fieldByPath(rv, field.path)
+			if !ok || (field.omitEmpty && isEmptyValue(value)) {
+				continue
+			}
+
+			if deep {
+				result[field.jsonName] = filterDeep(value, sub)
+			} else {
+				result[field.jsonName] = value.Interface()
+			}
+		}
+		return result
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String || isMarshaler(rv) {
+			break
+		}
+
+		result := make(R, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			ok, sub, deep := f.field(key)
+			if !ok {
+				continue
+			}
+
+			if deep {
+				result[key] = filterDeep(iter.Value(), sub)
+			} else {
+				result[key] = iter.Value().Interface()
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			break
+		}
+
+		// Byte slices are encoded as base64 strings.
+		if rv.Type().Elem().Kind() == reflect.Uint8 || isMarshaler(rv) {
+			break
+		}
+
+		result := make([]any, rv.Len())
+		for i := range result {
+			result[i] = filterDeep(rv.Index(i), f)
+		}
+		return result
+	}
+
+	return rv.Interface()
+}
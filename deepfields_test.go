@@ -0,0 +1,141 @@
+package resp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// deepAccount is a nested model with a secret.
+type deepAccount struct {
+	Login    string
+	Password string
+}
+
+// deepUser is a model with nested secrets.
+type deepUser struct {
+	ID       int
+	Password string
+	Created  time.Time
+	Primary  *deepAccount
+	Accounts []deepAccount
+	Extra    map[string]any
+	hidden   string
+}
+
+// newDeepUser returns the user for the deep filtering tests.
+func newDeepUser() deepUser {
+	return deepUser{
+		ID:       1,
+		Password: "secret",
+		Created:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Primary:  &deepAccount{Login: "go", Password: "p1"},
+		Accounts: []deepAccount{{Login: "loop", Password: "p2"}},
+		Extra:    map[string]any{"Password": "p3", "Theme": "dark"},
+		hidden:   "hidden",
+	}
+}
+
+// TestExcludeFieldsDeep tests the exclusion of the fields at every depth.
+func TestExcludeFieldsDeep(t *testing.T) {
+	user := newDeepUser()
+	expected := R{
+		"ID":       1,
+		"Created":  user.Created,
+		"Primary":  R{"Login": "go"},
+		"Accounts": []any{R{"Login": "loop"}},
+		"Extra":    R{"Theme": "dark"},
+	}
+
+	result := ExcludeFieldsDeep(&user, "Password")
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ExcludeFieldsDeep() = %v, want %v", result, expected)
+	}
+
+	slice := ExcludeFieldsDeep([]deepUser{user}, "Password")
+	if !reflect.DeepEqual(slice, []any{expected}) {
+		t.Errorf("ExcludeFieldsDeep() slice = %v", slice)
+	}
+
+	if got := ExcludeFieldsDeep("text", "Password"); got != "text" {
+		t.Errorf("ExcludeFieldsDeep() non-struct = %v", got)
+	}
+}
+
+// TestOnlyFieldsDeep tests the selection of the fields by paths.
+func TestOnlyFieldsDeep(t *testing.T) {
+	user := newDeepUser()
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  any
+	}{
+		{
+			name:  "Nested paths",
+			paths: []string{"ID", "Primary.Login", "Accounts.Login"},
+			want: R{
+				"ID":       1,
+				"Primary":  R{"Login": "go"},
+				"Accounts": []any{R{"Login": "loop"}},
+			},
+		},
+		{
+			name:  "Whole field wins over nested path",
+			paths: []string{"Extra.Theme", "Extra"},
+			want:  R{"Extra": user.Extra},
+		},
+		{
+			name:  "Map keys",
+			paths: []string{"Extra.Theme"},
+			want:  R{"Extra": R{"Theme": "dark"}},
+		},
+		{
+			name:  "No paths",
+			paths: nil,
+			want:  R{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := OnlyFieldsDeep(user, tt.paths...)
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("OnlyFieldsDeep() = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+// TestExcludeFieldsDeep_JSONTags tests that the nested fields are keyed
+// by the JSON names and the fields tagged with `json:"-"` are hidden.
+func TestExcludeFieldsDeep_JSONTags(t *testing.T) {
+	type account struct {
+		Login string `json:"login"`
+		Token string `json:"-"`
+		Note  string `json:"note,omitempty"`
+	}
+
+	type user struct {
+		Account  account `json:"account"`
+		Password string  `json:"password"`
+	}
+
+	data := user{
+		Account:  account{Login: "go", Token: "SECRET"},
+		Password: "secret",
+	}
+
+	want := R{"account": R{"login": "go"}}
+	for _, name := range []string{"Password", "password"} {
+		got := ExcludeFieldsDeep(data, name)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ExcludeFieldsDeep(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	got := OnlyFieldsDeep(data, "account.login", "account.Token")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OnlyFieldsDeep() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package resp
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// defaults holds the options applied to every new response.
+var defaults struct {
+	sync.RWMutex
+	opts []Option
+}
+
+// SetDefaults sets the options that are applied to every response
+// created by the package (before the options of the call itself).
+// Each call replaces the previously set defaults; call it without
+// arguments to remove them.
+//
+// It is intended to be called once, at application startup.
+//
+// Example Usage:
+//
+//	func main() {
+//	    resp.SetDefaults(
+//	        resp.WithSecureHeaders(),
+//	        resp.WithHeaderDenylist("X-Internal-*", "X-Debug"),
+//	    )
+//	    // ...
+//	}
+func SetDefaults(opts ...Option) {
+	defaults.Lock()
+	defer defaults.Unlock()
+	defaults.opts = append([]Option(nil), opts...)
+}
+
+// defaultOptions returns the options set by SetDefaults.
+func defaultOptions() []Option {
+	defaults.RLock()
+	defer defaults.RUnlock()
+	return defaults.opts
+}
+
+// Wrap returns a middleware that applies the options to every response
+// of the next handler right before its headers are sent, including the
+// responses written directly to the http.ResponseWriter, without the
+// resp package, the responses flushed before the first write, and the
+// responses of the handlers that return without writing anything. This
+// guarantees, for example, that the header denylist is enforced for
+// every handler.
+//
+// Only the options that modify the headers have effect, since the
+// options are applied at the moment the headers are sent.
+//
+// Example Usage:
+//
+//	handler := resp.Wrap(mux, resp.WithHeaderDenylist("X-Internal-*"))
+//	http.ListenAndServe(":8080", handler)
+func Wrap(next http.Handler, opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := &wrapWriter{ResponseWriter: w, opts: opts}
+		next.ServeHTTP(ww, r)
+
+		// The implicit StatusOK is sent by net/http after the return.
+		ww.apply()
+	})
+}
+
+// wrapWriter is the http.ResponseWriter used by Wrap.
+type wrapWriter struct {
+	http.ResponseWriter
+	opts        []Option
+	wroteHeader bool
+}
+
+// apply applies the options to the headers once, before they are sent.
+func (w *wrapWriter) apply() {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		r := newResponse(w.ResponseWriter, w.opts)
+		r.stripHeaders()
+	}
+}
+
+// WriteHeader applies the options and sends the status code.
+func (w *wrapWriter) WriteHeader(code int) {
+	w.apply()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the data, sending the status code first if needed.
+func (w *wrapWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// FlushError applies the options, since the flush sends
+// the headers, and flushes the underlying writer.
+func (w *wrapWriter) FlushError() error {
+	w.apply()
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush is like FlushError, for the handlers that use http.Flusher.
+func (w *wrapWriter) Flush() {
+	w.FlushError()
+}
+
+// Unwrap returns the original http.ResponseWriter,
+// for use with http.ResponseController.
+func (w *wrapWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// With returns a middleware that makes every response of the wrapped
+// handler created by the package inherit the options (after the
+// defaults set by SetDefaults and before the options of the call
+// itself), e.g. the Server header and the cache policy of a route
+// group. The options travel with the http.ResponseWriter passed to the
+// handler, so they're found also through the writers of other
+// middleware that implement the Unwrap method. The options of the
+// nested With middleware are applied after the outer ones.
+//
+// Unlike Wrap, the options don't apply to the responses written
+// directly to the http.ResponseWriter, without the resp package.
+//
+// Example Usage:
+//
+//	api := resp.With(resp.AddCacheControl("no-store"))
+//	mux.Handle("/api/", api(apiHandler))
+func With(opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				rw := &routeWriter{ResponseWriter: w}
+				rw.opts = append(routeOptions(w), opts...)
+				next.ServeHTTP(rw, r)
+			})
+	}
+}
+
+// routeWriter is the http.ResponseWriter used by With.
+type routeWriter struct {
+	http.ResponseWriter
+	opts []Option
+}
+
+// ReadFrom copies the data to the body, keeping the fast path
+// (e.g. sendfile) of the underlying writer if it is available.
+func (w *routeWriter) ReadFrom(src io.Reader) (int64, error) {
+	return io.Copy(w.ResponseWriter, src)
+}
+
+// FlushError flushes the underlying writer.
+func (w *routeWriter) FlushError() error {
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush is like FlushError, for the handlers that use http.Flusher.
+func (w *routeWriter) Flush() {
+	w.FlushError()
+}
+
+// Unwrap returns the original http.ResponseWriter,
+// for use with http.ResponseController.
+func (w *routeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// routeOptions returns the options set with With for the writer.
+func routeOptions(w http.ResponseWriter) []Option {
+	for {
+		switch v := w.(type) {
+		case *routeWriter:
+			return append([]Option(nil), v.opts...)
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package resp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSetDefaults tests that default options are applied
+// before the options of the call.
+func TestSetDefaults(t *testing.T) {
+	SetDefaults(WithHeader("X-Default", "1"), WithStatusAccepted())
+	defer SetDefaults()
+
+	w := httptest.NewRecorder()
+	if err := String(w, "ok", WithStatusCreated()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := w.Header().Get("X-Default"); got != "1" {
+		t.Errorf("SetDefaults() header = %q, want 1", got)
+	}
+
+	// The option of the call overrides the default one.
+	if w.Code != http.StatusCreated {
+		t.Errorf("SetDefaults() status = %d, want %d",
+			w.Code, http.StatusCreated)
+	}
+
+	SetDefaults()
+	w = httptest.NewRecorder()
+	String(w, "ok")
+	if got := w.Header().Get("X-Default"); got != "" {
+		t.Errorf("SetDefaults() without options kept header %q", got)
+	}
+}
+
+// TestWrap tests that Wrap enforces the options for handlers
+// that write directly to the http.ResponseWriter.
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"Write", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Internal-Node", "1")
+			fmt.Fprint(w, "raw")
+		}},
+		{"WriteHeader", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Internal-Node", "1")
+			w.WriteHeader(http.StatusAccepted)
+		}},
+		{"resp", func(w http.ResponseWriter, r *http.Request) {
+			String(w, "ok", WithHeader("X-Internal-Node", "1"))
+		}},
+		{"No write", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Internal-Node", "1")
+		}},
+		{"Flush", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Internal-Node", "1")
+			http.NewResponseController(w).Flush()
+			fmt.Fprint(w, "raw")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Wrap(tt.handler,
+				WithHeaderDenylist("X-Internal-*"),
+				WithHeader("X-Frame-Options", "DENY"))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// The headers sent to the client.
+			header := w.Result().Header
+			if got := header.Get("X-Internal-Node"); got != "" {
+				t.Errorf("Wrap() kept X-Internal-Node = %q", got)
+			}
+
+			if got := header.Get("X-Frame-Options"); got != "DENY" {
+				t.Errorf("Wrap() X-Frame-Options = %q", got)
+			}
+		})
+	}
+}
+
+// TestWrapWriter_Unwrap tests the Unwrap method of wrapWriter.
+func TestWrapWriter_Unwrap(t *testing.T) {
+	w := httptest.NewRecorder()
+	ww := &wrapWriter{ResponseWriter: w}
+	if ww.Unwrap() != w {
+		t.Error("Unwrap() did not return the original writer")
+	}
+}
+
+// TestWith tests that the responses of the wrapped handler
+// inherit the options of the With middleware.
+func TestWith(t *testing.T) {
+	SetDefaults(WithHeader("X-Default", "1"))
+	defer SetDefaults()
+
+	var h http.Handler = HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) error {
+			return String(w, "ok", WithHeader("X-Call", "1"))
+		})
+	h = With(WithHeader("X-Group", "inner"), WithStatusAccepted())(h)
+	h = With(WithHeader("X-Group", "outer"), WithHeader("X-API", "v1"))(h)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := map[string]string{
+		"X-Default": "1",
+		"X-API":     "v1",
+		"X-Group":   "outer, inner", // the outer options go first
+		"X-Call":    "1",
+	}
+	for key, value := range want {
+		got := strings.Join(w.Header().Values(key), ", ")
+		if got != value {
+			t.Errorf("With() %s = %q, want %q", key, got, value)
+		}
+	}
+
+	if w.Code != http.StatusAccepted || w.Body.String() != "ok" {
+		t.Errorf("With() response = %d %q, want %d %q",
+			w.Code, w.Body.String(), http.StatusAccepted, "ok")
+	}
+
+	// The writer without the middleware has no route options.
+	if opts := routeOptions(httptest.NewRecorder()); opts != nil {
+		t.Errorf("routeOptions() = %d options, want none", len(opts))
+	}
+}
+
+// TestWith_Flush tests that the writer of With implements http.Flusher.
+func TestWith_Flush(t *testing.T) {
+	h := With(WithHeader("X-API", "v1"))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			f, ok := w.(http.Flusher)
+			if !ok {
+				t.Fatal("writer doesn't implement http.Flusher")
+			}
+
+			String(w, "event")
+			f.Flush()
+		}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !w.Flushed {
+		t.Error("response isn't flushed")
+	}
+}
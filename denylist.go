@@ -0,0 +1,54 @@
+package resp
+
+import "strings"
+
+// WithHeaderDenylist removes the headers with the provided names from
+// the response right before the headers are sent, no matter where they
+// were set. A name ending with "*" removes all headers with this prefix.
+// Names are compared case-insensitively.
+//
+// It is meant to stop internal headers (e.g. debugging or routing
+// information) from leaking to clients. To enforce it for every handler,
+// use it with SetDefaults or Wrap.
+//
+// Example Usage:
+//
+//	resp.SetDefaults(resp.WithHeaderDenylist("X-Internal-*", "X-Debug"))
+func WithHeaderDenylist(names ...string) Option {
+	return func(r *Response) *Response {
+		r.headerDenylist = append(r.headerDenylist, names...)
+		return r
+	}
+}
+
+// stripHeaders removes the headers of the denylist from the response.
+func (r *Response) stripHeaders() {
+	if len(r.headerDenylist) == 0 {
+		return
+	}
+
+	h := r.httpWriter.Header()
+	for key := range h {
+		if denied(key, r.headerDenylist) {
+			delete(h, key)
+		}
+	}
+}
+
+// denied returns true if the header name matches any of the patterns.
+func denied(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if len(name) >= len(prefix) &&
+				strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+			continue
+		}
+
+		if strings.EqualFold(name, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithHeaderDenylist tests that denied headers are removed
+// right before the headers are sent.
+func TestWithHeaderDenylist(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewResponse(w, WithHeaderDenylist("X-Internal-*", "x-debug"))
+
+	// Headers set after the option are removed too.
+	r.SetHeader("X-Internal-Route", "users")
+	r.SetHeader("X-Internal-Node", "node-1")
+	r.SetHeader("X-Debug", "true")
+	r.SetHeader("X-Request-ID", "42")
+
+	if err := r.String("ok"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"X-Internal-Route", "X-Internal-Node",
+		"X-Debug"} {
+		if got := w.Header().Get(key); got != "" {
+			t.Errorf("WithHeaderDenylist() kept %s = %q", key, got)
+		}
+	}
+
+	if got := w.Header().Get("X-Request-ID"); got != "42" {
+		t.Errorf("WithHeaderDenylist() removed X-Request-ID")
+	}
+}
+
+// TestDenied tests the denied function.
+func TestDenied(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     bool
+	}{
+		{"X-Debug", []string{"x-debug"}, true},
+		{"X-Debug-Info", []string{"X-Debug"}, false},
+		{"X-Internal-A", []string{"x-internal-*"}, true},
+		{"X-Inter", []string{"X-Internal-*"}, false},
+		{"Server", []string{"*"}, true},
+		{"Server", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := denied(tt.name, tt.patterns); got != tt.want {
+			t.Errorf("denied(%q, %v) = %v, want %v",
+				tt.name, tt.patterns, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package resp
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// DigestAlgorithm is the hash algorithm of the Content-Digest header,
+// named as in the Hash Algorithms for HTTP Digest Fields registry.
+type DigestAlgorithm string
+
+// The digest algorithms supported by WithContentDigest.
+const (
+	DigestSHA256 DigestAlgorithm = "sha-256"
+	DigestSHA512 DigestAlgorithm = "sha-512"
+)
+
+// newHash returns the hash of the algorithm,
+// or nil if the algorithm isn't supported.
+func (a DigestAlgorithm) newHash() hash.Hash {
+	switch a {
+	case DigestSHA256:
+		return sha256.New()
+	case DigestSHA512:
+		return sha512.New()
+	}
+
+	return nil
+}
+
+// contentDigest is the state of the digest of the response body.
+type contentDigest struct {
+	algo    DigestAlgorithm
+	hash    hash.Hash // hashes the streamed body
+	sent    bool      // the digest is sent in the headers
+	trailer bool      // the digest is sent in the trailers
+}
+
+// WithContentDigest sends the digest of the response body, so clients
+// can verify the integrity of the files. The Content-Digest (RFC 9530)
+// and the legacy Digest (RFC 3230) headers are set.
+//
+// If the body is known before it is sent (e.g. HTML, String, and
+// ServeFileAsDownload without text transformations), the digest is
+// sent in the headers. Otherwise (e.g. JSON, Stream), the body is
+// hashed as it is written and the digest is sent in the trailers,
+// declared in the Trailer header, unless the digest is covered by the
+// signature of the response (see WithSignature). If the response is
+// compressed by the Compress middleware, the digest of the compressed
+// data is sent in the trailers by the middleware. Unsupported
+// algorithms are ignored.
+//
+// Example Usage:
+//
+//	resp.ServeFileAsDownload(w, "report.pdf", data,
+//	    resp.WithContentDigest(resp.DigestSHA256))
+//	// Content-Digest: sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:
+func WithContentDigest(algo DigestAlgorithm) Option {
+	return func(r *Response) *Response {
+		if algo.newHash() == nil {
+			return r
+		}
+
+		r.digest = &contentDigest{algo: algo}
+		return r
+	}
+}
+
+// setBodyDigest sends the digest of the body in the headers. It must be
+// called before the status code is sent, with the exact body bytes.
+func (r *Response) setBodyDigest(body []byte) {
+	if r.digest == nil || r.wroteHeader || r.compressedDigest(r.statusCode) {
+		return
+	}
+
+	h := r.digest.algo.newHash()
+	h.Write(body)
+	setDigestHeaders(r.httpWriter.Header(), r.digest.algo, h.Sum(nil))
+	r.digest.sent = true
+}
+
+// compressedDigest hands the digest over to the Compress middleware if
+// the response with the status code is compressed by it, since the
+// digest covers the encoded content (RFC 9530, 2).
+func (r *Response) compressedDigest(code int) bool {
+	cw := findCompressWriter(r.httpWriter)
+	if cw == nil || !cw.compresses(code, r.httpWriter.Header()) {
+		return false
+	}
+
+	cw.digest = r.digest.algo
+	return true
+}
+
+// startDigest declares the digest trailers and starts hashing the body,
+// if the digest isn't sent in the headers. It is called when the status
+// code is sent.
+func (r *Response) startDigest(code int) {
+	if r.digest == nil || r.digest.sent || !bodyAllowed(code) ||
+		r.compressedDigest(code) {
+		return
+	}
+
+	r.digest.hash = r.digest.algo.newHash()
+	r.digest.trailer = true
+	r.httpWriter.Header().Add(HeaderTrailer, HeaderContentDigest)
+	r.httpWriter.Header().Add(HeaderTrailer, HeaderDigest)
+}
+
+// finishDigest sends the digest of the streamed body in the trailers.
+func (r *Response) finishDigest() {
+	if r.digest == nil || !r.digest.trailer {
+		return
+	}
+
+	setDigestHeaders(r.httpWriter.Header(), r.digest.algo,
+		r.digest.hash.Sum(nil))
+	r.digest.trailer = false
+}
+
+// setDigestHeaders sets the digest headers with the sum.
+func setDigestHeaders(header http.Header, algo DigestAlgorithm, sum []byte) {
+	encoded := base64.StdEncoding.EncodeToString(sum)
+	header.Set(HeaderContentDigest, string(algo)+"=:"+encoded+":")
+	header.Set(HeaderDigest, strings.ToUpper(string(algo))+"="+encoded)
+}
+
+// bodyAllowed reports whether the response
+// with the status code can have a body.
+func bodyAllowed(code int) bool {
+	return code >= 200 && code != StatusNoContent && code != StatusNotModified
+}
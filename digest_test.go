@@ -0,0 +1,125 @@
+package resp
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// sha256Digest returns the Content-Digest value of the data.
+func sha256Digest(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// TestWithContentDigestHeader tests the digest of the buffered bodies.
+func TestWithContentDigestHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := HTML(w, "<h1>Hello</h1>", WithContentDigest(DigestSHA256))
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+
+	if got, want := w.Header().Get(HeaderContentDigest),
+		sha256Digest("<h1>Hello</h1>"); got != want {
+		t.Errorf("Content-Digest = %q, want %q", got, want)
+	}
+
+	sum := sha256.Sum256([]byte("<h1>Hello</h1>"))
+	want := "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+	if got := w.Header().Get(HeaderDigest); got != want {
+		t.Errorf("Digest = %q, want %q", got, want)
+	}
+
+	if got := w.Header().Get(HeaderTrailer); got != "" {
+		t.Errorf("Trailer = %q, want none", got)
+	}
+}
+
+// TestWithContentDigestDownload tests the digest of the downloads.
+func TestWithContentDigestDownload(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := ServeFileAsDownload(w, "a.bin", []byte("data"),
+		WithContentDigest(DigestSHA512))
+	if err != nil {
+		t.Fatalf("ServeFileAsDownload() error = %v", err)
+	}
+
+	sum := sha512.Sum512([]byte("data"))
+	want := "sha-512=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+	if got := w.Header().Get(HeaderContentDigest); got != want {
+		t.Errorf("Content-Digest = %q, want %q", got, want)
+	}
+}
+
+// TestWithContentDigestTrailer tests the digest of the streamed bodies.
+func TestWithContentDigestTrailer(t *testing.T) {
+	tests := []struct {
+		name  string
+		serve func(w *httptest.ResponseRecorder) error
+		body  string
+	}{
+		{
+			name: "JSON",
+			serve: func(w *httptest.ResponseRecorder) error {
+				return JSON(w, R{"ok": true},
+					WithContentDigest(DigestSHA256))
+			},
+			body: "{\"ok\":true}\n",
+		},
+		{
+			name: "Stream",
+			serve: func(w *httptest.ResponseRecorder) error {
+				return Stream(w, io.MultiReader(strings.NewReader("abc")),
+					WithContentDigest(DigestSHA256))
+			},
+			body: "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := tt.serve(w); err != nil {
+				t.Fatalf("serve error = %v", err)
+			}
+
+			res := w.Result()
+			if got := res.Header.Values(HeaderTrailer); len(got) != 2 {
+				t.Errorf("Trailer = %v", got)
+			}
+
+			got := res.Trailer.Get(HeaderContentDigest)
+			if want := sha256Digest(tt.body); got != want {
+				t.Errorf("Content-Digest trailer = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestWithContentDigestNoBody tests that no trailers are declared
+// for the responses without a body, and that unsupported algorithms
+// are ignored.
+func TestWithContentDigestNoBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := NoContent(w, WithContentDigest(DigestSHA256)); err != nil {
+		t.Fatalf("NoContent() error = %v", err)
+	}
+
+	if got := w.Header().Get(HeaderTrailer); got != "" {
+		t.Errorf("Trailer = %q, want none", got)
+	}
+
+	w = httptest.NewRecorder()
+	if err := String(w, "a", WithContentDigest("md5")); err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+
+	if got := w.Header().Get(HeaderContentDigest); got != "" {
+		t.Errorf("Content-Digest = %q, want none", got)
+	}
+}
@@ -0,0 +1,208 @@
+package resp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// Algorithms of the HTTP Digest authentication (RFC 7616).
+const (
+	DigestAuthMD5           = "MD5"
+	DigestAuthMD5Sess       = "MD5-sess"
+	DigestAuthSHA256        = "SHA-256"
+	DigestAuthSHA256Sess    = "SHA-256-sess"
+	DigestAuthSHA512256     = "SHA-512-256"
+	DigestAuthSHA512256Sess = "SHA-512-256-sess"
+)
+
+const (
+	// digestNonceRandomSize is the number
+	// of random bytes in a nonce.
+	digestNonceRandomSize = 16
+
+	// digestNonceTimestampSize is the size of
+	// the timestamp of a timed nonce.
+	digestNonceTimestampSize = 8
+)
+
+// DigestChallenge is a challenge of the HTTP Digest authentication
+// (RFC 7616), sent in the WWW-Authenticate header.
+type DigestChallenge struct {
+	// Realm is the protection space, e.g. "api@example.com".
+	Realm string
+
+	// Domain is the list of the URIs of the protection space.
+	Domain []string
+
+	// Nonce is the server nonce. AddDigestChallenge generates a random
+	// one if it is empty; see also NewDigestNonce.
+	Nonce string
+
+	// Opaque is the data returned by the client unchanged.
+	Opaque string
+
+	// Stale reports that the nonce of the previous request is stale,
+	// so the client can retry without asking the user again.
+	Stale bool
+
+	// Algorithm is the hash algorithm, e.g. DigestAuthSHA256;
+	// the client assumes MD5 if it is empty.
+	Algorithm string
+
+	// QOP is the list of the supported qualities
+	// of protection: "auth" and/or "auth-int".
+	QOP []string
+
+	// Charset is the charset of the credentials,
+	// the only allowed value is "UTF-8".
+	Charset string
+
+	// UserHash reports that the server supports the hashed usernames.
+	UserHash bool
+}
+
+// String returns the value of the WWW-Authenticate header.
+func (c DigestChallenge) String() string {
+	params := []string{"realm=" + quoteString(c.Realm)}
+	if len(c.Domain) > 0 {
+		params = append(params,
+			"domain="+quoteString(strings.Join(c.Domain, " ")))
+	}
+
+	params = append(params, "nonce="+quoteString(c.Nonce))
+	if c.Opaque != "" {
+		params = append(params, "opaque="+quoteString(c.Opaque))
+	}
+
+	if c.Stale {
+		params = append(params, "stale=true")
+	}
+
+	if c.Algorithm != "" {
+		params = append(params, "algorithm="+c.Algorithm)
+	}
+
+	if len(c.QOP) > 0 {
+		params = append(params,
+			"qop="+quoteString(strings.Join(c.QOP, ", ")))
+	}
+
+	if c.Charset != "" {
+		params = append(params, "charset="+c.Charset)
+	}
+
+	if c.UserHash {
+		params = append(params, "userhash=true")
+	}
+
+	return "Digest " + strings.Join(params, ", ")
+}
+
+// AddDigestChallenge adds the WWW-Authenticate header with the Digest
+// challenges, e.g. one per algorithm in the order of preference. A
+// random nonce is generated for the challenges without one. Send it
+// with the 401 (Unauthorized) status.
+//
+// Example Usage:
+//
+//	resp.Error(w, resp.StatusUnauthorized, "",
+//	    resp.WithStatusUnauthorized(),
+//	    resp.AddDigestChallenge(resp.DigestChallenge{
+//	        Realm:     "api@example.com",
+//	        Nonce:     resp.NewTimedDigestNonce(key, time.Now()),
+//	        Algorithm: resp.DigestAuthSHA256,
+//	        QOP:       []string{"auth"},
+//	    }))
+func AddDigestChallenge(challenges ...DigestChallenge) Option {
+	return func(r *Response) *Response {
+		for _, c := range challenges {
+			if c.Nonce == "" {
+				nonce, err := NewDigestNonce()
+				if err != nil {
+					continue
+				}
+				c.Nonce = nonce
+			}
+
+			r.httpWriter.Header().Add(HeaderWWWAuthenticate, c.String())
+		}
+
+		return r
+	}
+}
+
+// NewDigestNonce returns a random nonce for the Digest challenge.
+func NewDigestNonce() (string, error) {
+	b := make([]byte, digestNonceRandomSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewTimedDigestNonce returns a nonce that holds the time it is issued
+// at, signed with the key (HMAC-SHA256), so the server can check the
+// nonce and its age with VerifyDigestNonce without storing it.
+func NewTimedDigestNonce(key []byte, t time.Time) string {
+	b := make([]byte, digestNonceTimestampSize, 64)
+	binary.BigEndian.PutUint64(b, uint64(t.UnixNano()))
+	b = append(b, digestNonceMAC(key, b)...)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// VerifyDigestNonce checks the nonce issued by NewTimedDigestNonce:
+// valid reports whether the nonce is signed with the key, and stale
+// whether it is issued more than maxAge ago. For a stale nonce, the
+// challenge should be sent again with Stale set, so the client
+// retries with the new nonce without asking the user again.
+func VerifyDigestNonce(
+	key []byte,
+	nonce string,
+	maxAge time.Duration,
+) (valid, stale bool) {
+	b, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil || len(b) != digestNonceTimestampSize+sha256.Size {
+		return false, false
+	}
+
+	ts, sig := b[:digestNonceTimestampSize], b[digestNonceTimestampSize:]
+	if !hmac.Equal(sig, digestNonceMAC(key, ts)) {
+		return false, false
+	}
+
+	issued := time.Unix(0, int64(binary.BigEndian.Uint64(ts)))
+	if time.Since(issued) > maxAge {
+		return true, true
+	}
+
+	return true, false
+}
+
+// digestNonceMAC returns the signature of the timestamp of the nonce.
+func digestNonceMAC(key, timestamp []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(timestamp)
+	return mac.Sum(nil)
+}
+
+// quoteString returns the value as the quoted-string
+// of the HTTP header parameters (RFC 9110, 5.6.4).
+func quoteString(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(s[i])
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
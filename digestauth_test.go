@@ -0,0 +1,116 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDigestChallenge tests the String method of DigestChallenge.
+func TestDigestChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge DigestChallenge
+		want      string
+	}{
+		{
+			name: "Minimal",
+			challenge: DigestChallenge{
+				Realm: "api",
+				Nonce: "abc",
+			},
+			want: `Digest realm="api", nonce="abc"`,
+		},
+		{
+			name: "Full",
+			challenge: DigestChallenge{
+				Realm:     `http-auth@example.org`,
+				Domain:    []string{"/api", "/admin"},
+				Nonce:     "7ypf",
+				Opaque:    "FQhe",
+				Stale:     true,
+				Algorithm: DigestAuthSHA256,
+				QOP:       []string{"auth", "auth-int"},
+				Charset:   "UTF-8",
+				UserHash:  true,
+			},
+			want: `Digest realm="http-auth@example.org", ` +
+				`domain="/api /admin", nonce="7ypf", opaque="FQhe", ` +
+				`stale=true, algorithm=SHA-256, qop="auth, auth-int", ` +
+				`charset=UTF-8, userhash=true`,
+		},
+		{
+			name: "Escaped realm",
+			challenge: DigestChallenge{
+				Realm: `say "hi" \ bye`,
+				Nonce: "n",
+			},
+			want: `Digest realm="say \"hi\" \\ bye", nonce="n"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.challenge.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAddDigestChallenge tests the AddDigestChallenge option.
+func TestAddDigestChallenge(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := NoContent(w, AddDigestChallenge(
+		DigestChallenge{Realm: "api", Algorithm: DigestAuthSHA256},
+		DigestChallenge{Realm: "api", Nonce: "fixed"},
+	))
+	if err != nil {
+		t.Fatalf("NoContent() error = %v", err)
+	}
+
+	got := w.Header().Values(HeaderWWWAuthenticate)
+	if len(got) != 2 {
+		t.Fatalf("WWW-Authenticate = %q, want two challenges", got)
+	}
+
+	if strings.Contains(got[0], `nonce=""`) {
+		t.Errorf("challenge = %s, want generated nonce", got[0])
+	}
+
+	if want := `Digest realm="api", nonce="fixed"`; got[1] != want {
+		t.Errorf("challenge = %s, want %s", got[1], want)
+	}
+}
+
+// TestTimedDigestNonce tests the NewTimedDigestNonce
+// and VerifyDigestNonce functions.
+func TestTimedDigestNonce(t *testing.T) {
+	key := []byte("secret")
+	fresh := NewTimedDigestNonce(key, time.Now())
+	old := NewTimedDigestNonce(key, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name      string
+		key       []byte
+		nonce     string
+		wantValid bool
+		wantStale bool
+	}{
+		{"Fresh", key, fresh, true, false},
+		{"Stale", key, old, true, true},
+		{"Wrong key", []byte("other"), fresh, false, false},
+		{"Malformed", key, "not a nonce", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, stale := VerifyDigestNonce(tt.key, tt.nonce, time.Minute)
+			if valid != tt.wantValid || stale != tt.wantStale {
+				t.Errorf("VerifyDigestNonce() = %v, %v, want %v, %v",
+					valid, stale, tt.wantValid, tt.wantStale)
+			}
+		})
+	}
+}
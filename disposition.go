@@ -0,0 +1,99 @@
+package resp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultFilename is used in the Content-Disposition header
+// when the filename is empty after sanitizing.
+const defaultFilename = "download"
+
+// contentDisposition returns the value of the Content-Disposition
+// header (RFC 6266) with the sanitized filename. The filename parameter
+// is the ASCII fallback; if the name contains non-ASCII characters or
+// quotes (or the UTF-8 encoding is forced), the filename* parameter
+// (RFC 8187) with the UTF-8 name is added too.
+func contentDisposition(
+	dispositionType string,
+	filename string,
+	forceUTF8 bool,
+) string {
+	filename = sanitizeFilename(filename)
+
+	extended := forceUTF8
+	var fallback strings.Builder
+	for _, c := range filename {
+		switch {
+		case c >= utf8.RuneSelf:
+			fallback.WriteByte('_')
+			extended = true
+		case c == '"':
+			fallback.WriteByte('_')
+			extended = true
+		default:
+			fallback.WriteRune(c)
+		}
+	}
+
+	value := dispositionType + `; filename="` + fallback.String() + `"`
+	if extended {
+		value += "; filename*=UTF-8''" + encodeExtValue(filename)
+	}
+
+	return value
+}
+
+// sanitizeFilename replaces the path separators of the filename
+// with underscores and removes the control characters, so the name
+// can't point outside the download directory or break the header.
+func sanitizeFilename(filename string) string {
+	filename = strings.Map(func(c rune) rune {
+		switch {
+		case c == '/' || c == '\\':
+			return '_'
+		case unicode.IsControl(c) || c == utf8.RuneError:
+			return -1
+		}
+		return c
+	}, filename)
+
+	filename = strings.TrimSpace(filename)
+	if filename == "" || filename == "." || filename == ".." {
+		return defaultFilename
+	}
+
+	return filename
+}
+
+// encodeExtValue percent-encodes the UTF-8 bytes of the value
+// that aren't attr-char of RFC 8187.
+func encodeExtValue(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+
+	return b.String()
+}
+
+// isAttrChar reports whether the byte is attr-char of RFC 8187.
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
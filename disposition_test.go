@@ -0,0 +1,75 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestContentDisposition tests the Content-Disposition values.
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		force    bool
+		want     string
+	}{
+		{
+			name:     "ASCII",
+			filename: "report.pdf",
+			want:     `attachment; filename="report.pdf"`,
+		},
+		{
+			name:     "Non-ASCII",
+			filename: "звіт 2024.pdf",
+			want: `attachment; filename="____ 2024.pdf"; ` +
+				`filename*=UTF-8''%D0%B7%D0%B2%D1%96%D1%82%202024.pdf`,
+		},
+		{
+			name:     "Quotes",
+			filename: `say "hi".txt`,
+			want: `attachment; filename="say _hi_.txt"; ` +
+				`filename*=UTF-8''say%20%22hi%22.txt`,
+		},
+		{
+			name:     "Forced UTF-8",
+			filename: "a=b.txt",
+			force:    true,
+			want: `attachment; filename="a=b.txt"; ` +
+				`filename*=UTF-8''a%3Db.txt`,
+		},
+		{
+			name:     "Path separators and control characters",
+			filename: "../etc/pass\r\nwd\x00.txt",
+			want:     `attachment; filename=".._etc_passwd.txt"`,
+		},
+		{
+			name:     "Empty after sanitizing",
+			filename: "\t..",
+			want:     `attachment; filename="download"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := contentDisposition("attachment", tt.filename, tt.force)
+			if got != tt.want {
+				t.Errorf("contentDisposition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestServeFileAsDownloadFilename tests that the download filename
+// is encoded according to RFC 6266.
+func TestServeFileAsDownloadFilename(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := ServeFileAsDownload(w, "файл.txt", []byte("data")); err != nil {
+		t.Fatalf("ServeFileAsDownload() error = %v", err)
+	}
+
+	want := `attachment; filename="____.txt"; ` +
+		`filename*=UTF-8''%D1%84%D0%B0%D0%B9%D0%BB.txt`
+	if got := w.Header().Get(HeaderContentDisposition); got != want {
+		t.Errorf("Content-Disposition = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,353 @@
+package resp
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServeReaderAsDownload streams the content of the reader to the client
+// as a download, without loading it into memory (unlike
+// ServeFileAsDownload). The Content-Type is detected from the extension
+// of the filename (application/octet-stream by default), and the
+// Content-Length is set if the size is known (not negative).
+//
+// If the size is known, at most size bytes are read, and an error
+// wrapping io.ErrUnexpectedEOF is returned if the reader ends earlier.
+// The Content-Length isn't set if the text is transformed (see WithBOM
+// and WithTextEncoding).
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    obj, size, _ := bucket.Open(ctx, "exports/2024.csv")
+//	    defer obj.Close()
+//
+//	    resp.ServeReaderAsDownload(w, "2024.csv", obj, size)
+//	}
+func ServeReaderAsDownload(
+	w http.ResponseWriter,
+	filename string,
+	r io.Reader,
+	size int64,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).ServeReaderAsDownload(filename, r, size)
+}
+
+// ServeReaderAsDownload streams the content of the reader as a download.
+// See the ServeReaderAsDownload function for details.
+func (r *Response) ServeReaderAsDownload(
+	filename string,
+	data io.Reader,
+	size int64,
+) (err error) {
+	defer r.finish(&err)
+
+	r.setAttachment(filename)
+
+	header := r.httpWriter.Header()
+	if _, ok := header[HeaderContentType]; !ok {
+		ctype := mime.TypeByExtension(filepath.Ext(filename))
+		if ctype != "" {
+			header.Set(HeaderContentType, ctype)
+		}
+	}
+
+	if rs, ok := data.(io.ReadSeeker); ok && r.rangeSupported() {
+		if _, ok := header[HeaderContentType]; !ok {
+			header.Set(HeaderContentType, MIMEOctetStream)
+		}
+		return r.ServeContent(r.rangeRequest, filename, time.Time{}, rs)
+	}
+
+	if ra, ok := data.(io.ReaderAt); ok && size >= 0 && r.rangeSupported() {
+		return r.ServeRanges(r.rangeRequest, ra, size)
+	}
+
+	var limited *io.LimitedReader
+	if size >= 0 {
+		limited = &io.LimitedReader{R: data, N: size}
+		data = limited
+		if !r.bom && r.textEncoding == nil {
+			header.Set(HeaderContentLength, strconv.FormatInt(size, 10))
+		}
+	}
+
+	r.prepare(StatusOK, MIMEOctetStream)
+	r.writeHeader(r.statusCode)
+
+	if err := r.writeText(data); err != nil {
+		return err
+	}
+
+	if limited != nil && limited.N > 0 {
+		return fmt.Errorf("download is shorter than %d bytes: %w",
+			size, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// WithRangeSupport enables the Range requests for the downloads served
+// from memory or readers (ServeFileAsDownload, ServeReaderAsDownload):
+// the request is used to send the requested ranges with the 206 (Partial
+// Content) status and the Content-Range header, and to evaluate the
+// If-Range and other conditional headers (see ServeContent), so media
+// players and download managers can resume the downloads.
+//
+// The ranges are supported only for readers that implement io.Seeker,
+// or io.ReaderAt if the size is known (see ServeRanges), and aren't
+// supported if the text is transformed (see WithBOM and
+// WithTextEncoding); other downloads are sent in full.
+//
+// Example Usage:
+//
+//	resp.ServeFileAsDownload(w, "video.mp4", data, resp.WithRangeSupport(r))
+func WithRangeSupport(req *http.Request) Option {
+	return func(r *Response) *Response {
+		r.rangeRequest = req
+		return r
+	}
+}
+
+// rangeSupported reports whether the download
+// can be served with the Range requests.
+func (r *Response) rangeSupported() bool {
+	return r.rangeRequest != nil && !r.bom && r.textEncoding == nil
+}
+
+// WithAcceptRanges sets the Accept-Ranges header with the range units
+// the resource supports, e.g. "bytes", so the clients know they can
+// resume the download. Without units (or with "none") the header is
+// set to "none", which tells the clients not to send the Range
+// requests. The invalid units are skipped.
+//
+// Example Usage:
+//
+//	resp.FileInfoHead(w, info.Size(), info.ModTime(), etag,
+//	    resp.WithAcceptRanges("bytes"))
+func WithAcceptRanges(units ...string) Option {
+	return func(r *Response) *Response {
+		valid := make([]string, 0, len(units))
+		for _, unit := range units {
+			unit = strings.ToLower(strings.TrimSpace(unit))
+			if isSFToken(unit) && unit != "none" {
+				valid = append(valid, unit)
+			}
+		}
+
+		value := "none"
+		if len(valid) > 0 {
+			value = strings.Join(valid, ", ")
+		}
+
+		r.httpWriter.Header().Set(HeaderAcceptRanges, value)
+		return r
+	}
+}
+
+// FileInfoHead sends the metadata of a file without its content, e.g.
+// for the HEAD requests and the probes of the download managers on the
+// download endpoints: the Content-Length is set to the size, the
+// Last-Modified header to the modification time (unless it is zero),
+// the ETag header to the etag (unless it is empty), and the
+// Accept-Ranges header to "bytes" (unless it is set, see
+// WithAcceptRanges). The Content-Type is MIMEOctetStream unless it is
+// set with the options. If the status code isn't set - StatusOK will
+// be set.
+//
+// The etag is quoted if needed like with AddETag, e.g. `"v1"` for "v1";
+// the invalid etag is ignored.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    info, _ := os.Stat(path)
+//	    if r.Method == http.MethodHead {
+//	        resp.FileInfoHead(w, info.Size(), info.ModTime(), "",
+//	            resp.AddContentType("video/mp4"))
+//	        return
+//	    }
+//	    // stream the content...
+//	}
+func FileInfoHead(
+	w http.ResponseWriter,
+	size int64,
+	modtime time.Time,
+	etag string,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).FileInfoHead(size, modtime, etag)
+}
+
+// FileInfoHead sends the metadata of a file without its content.
+// See the FileInfoHead function for details.
+func (r *Response) FileInfoHead(
+	size int64,
+	modtime time.Time,
+	etag string,
+) (err error) {
+	defer r.finish(&err)
+
+	header := r.httpWriter.Header()
+	header.Set(HeaderContentLength, strconv.FormatInt(size, 10))
+	if !isZeroTime(modtime) {
+		header.Set(HeaderLastModified,
+			modtime.UTC().Format(http.TimeFormat))
+	}
+
+	if etag = etagValue(etag); etag != "" {
+		header.Set(HeaderETag, etag)
+	}
+
+	if header.Get(HeaderAcceptRanges) == "" {
+		header.Set(HeaderAcceptRanges, "bytes")
+	}
+
+	r.prepare(StatusOK, MIMEOctetStream)
+	r.writeHeader(r.statusCode)
+	return nil
+}
+
+// setAttachment sets the Content-Disposition header
+// of the download with the filename.
+func (r *Response) setAttachment(filename string) {
+	AddContentDisposition("attachment", filename)(r)
+}
+
+// downloadValidators holds the validators of the download
+// set with the WithDownloadValidators option.
+type downloadValidators struct {
+	req     *http.Request
+	modtime time.Time
+	etag    string
+}
+
+// WithDownloadValidators enables the caching of the downloads generated
+// in memory (ServeFileAsDownload): the modification time is sent as the
+// Last-Modified header (unless it is zero), and the etag as the ETag
+// header. If the etag is omitted, a weak ETag is computed from the hash
+// of the data. When the If-None-Match or If-Modified-Since header of the
+// GET or HEAD request matches the download, the 304 (Not Modified)
+// response is sent without the body, so repeat downloads of unchanged
+// reports are cheap.
+//
+// The etag is quoted if needed like with AddETag, e.g. `"v1"` for "v1";
+// the invalid etag is ignored, so the weak ETag is computed.
+//
+// Example Usage:
+//
+//	resp.ServeFileAsDownload(w, "report.csv", data,
+//	    resp.WithDownloadValidators(r, report.UpdatedAt))
+func WithDownloadValidators(
+	req *http.Request,
+	modtime time.Time,
+	etag ...string,
+) Option {
+	return func(r *Response) *Response {
+		v := &downloadValidators{req: req, modtime: modtime}
+		if len(etag) > 0 {
+			v.etag = etagValue(etag[0])
+		}
+
+		r.validators = v
+		return r
+	}
+}
+
+// setValidators sets the ETag and Last-Modified headers of the download
+// and returns the modification time, or zero time if there are no
+// validators.
+func (r *Response) setValidators(data []byte) time.Time {
+	v := r.validators
+	if v == nil {
+		return time.Time{}
+	}
+
+	if v.etag == "" {
+		v.etag = weakETag(data)
+	}
+
+	header := r.httpWriter.Header()
+	header.Set(HeaderETag, v.etag)
+	if !isZeroTime(v.modtime) {
+		header.Set(HeaderLastModified,
+			v.modtime.UTC().Format(http.TimeFormat))
+	}
+
+	return v.modtime
+}
+
+// notModified reports whether the request of the validators
+// matches the download, i.e. the client has its actual copy.
+func (r *Response) notModified() bool {
+	v := r.validators
+	if v == nil || v.req == nil {
+		return false
+	}
+
+	if v.req.Method != http.MethodGet && v.req.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := v.req.Header.Get(HeaderIfNoneMatch); inm != "" {
+		return etagMatch(inm, v.etag)
+	}
+
+	ims := v.req.Header.Get(HeaderIfModifiedSince)
+	if ims == "" || isZeroTime(v.modtime) {
+		return false
+	}
+
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	// The Last-Modified header has a second precision.
+	return !v.modtime.Truncate(time.Second).After(t)
+}
+
+// writeNotModified sends the 304 (Not Modified) response
+// without the body and the representation headers.
+func (r *Response) writeNotModified() error {
+	header := r.httpWriter.Header()
+	header.Del(HeaderContentType)
+	header.Del(HeaderContentLength)
+	header.Del(HeaderContentEncoding)
+
+	r.statusCode = StatusNotModified
+	r.writeHeader(StatusNotModified)
+	return nil
+}
+
+// weakETag returns the weak ETag computed from the hash of the data.
+func weakETag(data []byte) string {
+	return "W/" + ETagStrong(data)
+}
+
+// etagMatch reports whether the list of the If-None-Match header
+// matches the etag using the weak comparison.
+func etagMatch(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isZeroTime reports whether the time is
+// zero or the Unix epoch (an unknown time).
+func isZeroTime(t time.Time) bool {
+	return t.IsZero() || t.Equal(time.Unix(0, 0))
+}
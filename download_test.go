@@ -0,0 +1,425 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestServeReaderAsDownload tests the ServeReaderAsDownload function.
+func TestServeReaderAsDownload(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		data       string
+		size       int64
+		wantType   string
+		wantLength string
+		wantBody   string
+		wantErr    error
+	}{
+		{
+			name:       "Known size",
+			filename:   "report.csv",
+			data:       "id,name\n1,Go\n",
+			size:       13,
+			wantType:   "text/csv; charset=utf-8",
+			wantLength: "13",
+			wantBody:   "id,name\n1,Go\n",
+		},
+		{
+			name:     "Unknown size",
+			filename: "archive.unknownext",
+			data:     "binary",
+			size:     -1,
+			wantType: MIMEOctetStream,
+			wantBody: "binary",
+		},
+		{
+			name:       "Reader is longer",
+			filename:   "data.bin",
+			data:       "0123456789",
+			size:       4,
+			wantType:   MIMEOctetStream,
+			wantLength: "4",
+			wantBody:   "0123",
+		},
+		{
+			name:       "Reader is shorter",
+			filename:   "data.bin",
+			data:       "01",
+			size:       4,
+			wantType:   MIMEOctetStream,
+			wantLength: "4",
+			wantBody:   "01",
+			wantErr:    io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := ServeReaderAsDownload(w, tt.filename,
+				strings.NewReader(tt.data), tt.size)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ServeReaderAsDownload() error = %v, want %v",
+					err, tt.wantErr)
+			}
+
+			header := w.Header()
+			want := `attachment; filename="` + tt.filename + `"`
+			if got := header.Get(HeaderContentDisposition); got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+
+			if got := header.Get(HeaderContentType); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+
+			if got := header.Get(HeaderContentLength); got != tt.wantLength {
+				t.Errorf("Content-Length = %q, want %q",
+					got, tt.wantLength)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestServeReaderAsDownloadWithBOM tests that the Content-Length
+// isn't set when the text is transformed.
+func TestServeReaderAsDownloadWithBOM(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := ServeReaderAsDownload(w, "a.txt", strings.NewReader("abc"), 3,
+		WithBOM())
+	if err != nil {
+		t.Fatalf("ServeReaderAsDownload() error = %v", err)
+	}
+
+	if got := w.Header().Get(HeaderContentLength); got != "" {
+		t.Errorf("Content-Length = %q, want none", got)
+	}
+
+	if got := w.Body.String(); got != "\uFEFFabc" {
+		t.Errorf("body = %q", got)
+	}
+}
+
+// TestWithRangeSupport tests the Range requests of the downloads.
+func TestWithRangeSupport(t *testing.T) {
+	data := "0123456789"
+
+	tests := []struct {
+		name       string
+		serve      func(w http.ResponseWriter, opts ...Option) error
+		rangeValue string
+		opts       []Option
+		wantStatus int
+		wantBody   string
+		wantRange  string
+	}{
+		{
+			name: "Bytes",
+			serve: func(w http.ResponseWriter, opts ...Option) error {
+				return ServeFileAsDownload(w, "d.bin", []byte(data), opts...)
+			},
+			rangeValue: "bytes=2-4",
+			wantStatus: StatusPartialContent,
+			wantBody:   "234",
+			wantRange:  "bytes 2-4/10",
+		},
+		{
+			name: "Seeker",
+			serve: func(w http.ResponseWriter, opts ...Option) error {
+				return ServeReaderAsDownload(w, "d.bin",
+					strings.NewReader(data), 10, opts...)
+			},
+			rangeValue: "bytes=-3",
+			wantStatus: StatusPartialContent,
+			wantBody:   "789",
+			wantRange:  "bytes 7-9/10",
+		},
+		{
+			name: "Not a seeker",
+			serve: func(w http.ResponseWriter, opts ...Option) error {
+				return ServeReaderAsDownload(w, "d.bin",
+					io.MultiReader(strings.NewReader(data)), 10, opts...)
+			},
+			rangeValue: "bytes=2-4",
+			wantStatus: StatusOK,
+			wantBody:   data,
+		},
+		{
+			name: "Transformed text",
+			serve: func(w http.ResponseWriter, opts ...Option) error {
+				return ServeFileAsDownload(w, "d.txt", []byte(data), opts...)
+			},
+			rangeValue: "bytes=2-4",
+			opts:       []Option{WithBOM()},
+			wantStatus: StatusOK,
+			wantBody:   "\uFEFF" + data,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/download", nil)
+			r.Header.Set(HeaderRange, tt.rangeValue)
+
+			opts := append(tt.opts, WithRangeSupport(r))
+			if err := tt.serve(w, opts...); err != nil {
+				t.Fatalf("serve error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+
+			if got := w.Header().Get(HeaderContentRange); got != tt.wantRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantRange)
+			}
+
+			got := w.Header().Get(HeaderContentType)
+			if got != MIMEOctetStream {
+				t.Errorf("Content-Type = %q, want %q", got, MIMEOctetStream)
+			}
+		})
+	}
+}
+
+// TestWithDownloadValidators tests the conditional
+// requests of the ServeFileAsDownload function.
+func TestWithDownloadValidators(t *testing.T) {
+	data := []byte("id,name\n1,Go\n")
+	modtime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	etag := weakETag(data)
+
+	tests := []struct {
+		name       string
+		method     string
+		header     http.Header
+		etag       []string
+		wantStatus int
+	}{
+		{
+			name:       "First download",
+			method:     http.MethodGet,
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "Computed etag matches",
+			method:     http.MethodGet,
+			header:     http.Header{HeaderIfNoneMatch: {etag}},
+			wantStatus: StatusNotModified,
+		},
+		{
+			name:       "Explicit etag matches weakly",
+			method:     http.MethodGet,
+			header:     http.Header{HeaderIfNoneMatch: {`"x", W/"v1"`}},
+			etag:       []string{`"v1"`},
+			wantStatus: StatusNotModified,
+		},
+		{
+			name:       "Unquoted etag matches",
+			method:     http.MethodGet,
+			header:     http.Header{HeaderIfNoneMatch: {`"v1"`}},
+			etag:       []string{"v1"},
+			wantStatus: StatusNotModified,
+		},
+		{
+			name:       "Etag changed",
+			method:     http.MethodGet,
+			header:     http.Header{HeaderIfNoneMatch: {`"old"`}},
+			wantStatus: StatusOK,
+		},
+		{
+			name:   "Not modified since",
+			method: http.MethodGet,
+			header: http.Header{
+				HeaderIfModifiedSince: {modtime.Format(http.TimeFormat)},
+			},
+			wantStatus: StatusNotModified,
+		},
+		{
+			name:   "Modified since",
+			method: http.MethodGet,
+			header: http.Header{
+				HeaderIfModifiedSince: {
+					modtime.Add(-time.Hour).Format(http.TimeFormat),
+				},
+			},
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "Unsafe method",
+			method:     http.MethodPost,
+			header:     http.Header{HeaderIfNoneMatch: {"*"}},
+			wantStatus: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/report", nil)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+
+			w := httptest.NewRecorder()
+			err := ServeFileAsDownload(w, "report.csv", data,
+				WithDownloadValidators(req, modtime, tt.etag...))
+			if err != nil {
+				t.Fatalf("ServeFileAsDownload() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			wantETag := etag
+			if len(tt.etag) > 0 {
+				wantETag = etagValue(tt.etag[0])
+			}
+			if got := w.Header().Get(HeaderETag); got != wantETag {
+				t.Errorf("ETag = %q, want %q", got, wantETag)
+			}
+
+			wantLM := modtime.Format(http.TimeFormat)
+			if got := w.Header().Get(HeaderLastModified); got != wantLM {
+				t.Errorf("Last-Modified = %q, want %q", got, wantLM)
+			}
+
+			wantBody := string(data)
+			if tt.wantStatus == StatusNotModified {
+				wantBody = ""
+				if got := w.Header().Get(HeaderContentType); got != "" {
+					t.Errorf("Content-Type = %q, want empty", got)
+				}
+			}
+			if got := w.Body.String(); got != wantBody {
+				t.Errorf("body = %q, want %q", got, wantBody)
+			}
+		})
+	}
+}
+
+// TestWithDownloadValidatorsRange tests that the validators
+// are used with the Range requests.
+func TestWithDownloadValidatorsRange(t *testing.T) {
+	data := []byte("0123456789")
+	req := httptest.NewRequest(http.MethodGet, "/d.bin", nil)
+	req.Header.Set(HeaderIfNoneMatch, `"v2"`)
+
+	w := httptest.NewRecorder()
+	err := ServeFileAsDownload(w, "d.bin", data, WithRangeSupport(req),
+		WithDownloadValidators(req, time.Time{}, `"v2"`))
+	if err != nil {
+		t.Fatalf("ServeFileAsDownload() error = %v", err)
+	}
+
+	if w.Code != StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+}
+
+// TestFileInfoHead tests the FileInfoHead function.
+func TestFileInfoHead(t *testing.T) {
+	modtime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		opts  []Option
+		mod   time.Time
+		etag  string
+		want  map[string]string
+		unset []string
+	}{
+		{
+			name: "Full metadata",
+			mod:  modtime,
+			etag: `"v1"`,
+			opts: []Option{AddContentType("video/mp4")},
+			want: map[string]string{
+				HeaderContentLength: "1024",
+				HeaderLastModified:  "Wed, 01 May 2024 10:00:00 GMT",
+				HeaderETag:          `"v1"`,
+				HeaderAcceptRanges:  "bytes",
+				HeaderContentType:   "video/mp4",
+			},
+		},
+		{
+			name: "Unquoted etag",
+			etag: "W/v1",
+			want: map[string]string{HeaderETag: `W/"v1"`},
+		},
+		{
+			name: "No validators",
+			opts: []Option{WithAcceptRanges()},
+			want: map[string]string{
+				HeaderContentLength: "1024",
+				HeaderAcceptRanges:  "none",
+				HeaderContentType:   MIMEOctetStream,
+			},
+			unset: []string{HeaderLastModified, HeaderETag},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := FileInfoHead(w, 1024, tt.mod, tt.etag, tt.opts...)
+			if err != nil {
+				t.Fatalf("FileInfoHead() error = %v", err)
+			}
+
+			if w.Code != StatusOK || w.Body.Len() != 0 {
+				t.Errorf("response = %d %q, want %d without the body",
+					w.Code, w.Body.String(), StatusOK)
+			}
+
+			for key, value := range tt.want {
+				if got := w.Header().Get(key); got != value {
+					t.Errorf("%s = %q, want %q", key, got, value)
+				}
+			}
+
+			for _, key := range tt.unset {
+				if got := w.Header().Get(key); got != "" {
+					t.Errorf("%s = %q, want empty", key, got)
+				}
+			}
+		})
+	}
+}
+
+// TestWithAcceptRanges tests the WithAcceptRanges option.
+func TestWithAcceptRanges(t *testing.T) {
+	tests := []struct {
+		units []string
+		want  string
+	}{
+		{[]string{"bytes"}, "bytes"},
+		{[]string{"Bytes", "items"}, "bytes, items"},
+		{[]string{"bad unit", "none"}, "none"},
+		{nil, "none"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		NewResponse(w, WithAcceptRanges(tt.units...))
+		if got := w.Header().Get(HeaderAcceptRanges); got != tt.want {
+			t.Errorf("WithAcceptRanges(%q) = %q, want %q",
+				tt.units, got, tt.want)
+		}
+	}
+}
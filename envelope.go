@@ -0,0 +1,44 @@
+package resp
+
+import "net/http"
+
+// Envelope is the typed wrapper of the JSON response body:
+// {"data": ..., "meta": {...}, "errors": [...]}. The metadata set
+// with WithMeta and SetGlobalMeta is merged into Meta.
+type Envelope[T any] struct {
+	Data   T               `json:"data"`
+	Meta   R               `json:"meta,omitempty"`
+	Errors []ErrorResponse `json:"errors,omitempty"`
+}
+
+// OK sends the data wrapped in the Envelope as the JSON response.
+// If the status code isn't set - StatusOK will be set.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    users, total := store.Users()
+//	    resp.OK(w, users, resp.WithMeta("total", total))
+//	    // {"data": [...], "meta": {"total": 42}}
+//	}
+func OK[T any](w http.ResponseWriter, data T, opts ...Option) error {
+	return SendEnvelope(w, Envelope[T]{Data: data}, opts...)
+}
+
+// SendEnvelope sends the envelope as the JSON response, e.g. the data
+// with the errors of the items that failed. If the status code isn't
+// set - StatusOK will be set.
+//
+// Example Usage:
+//
+//	resp.SendEnvelope(w, resp.Envelope[[]User]{
+//	    Data:   imported,
+//	    Errors: []resp.ErrorResponse{{Code: 409, Message: "duplicate"}},
+//	})
+func SendEnvelope[T any](
+	w http.ResponseWriter,
+	env Envelope[T],
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).JSON(env)
+}
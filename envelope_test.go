@@ -0,0 +1,79 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestOK tests the OK function.
+func TestOK(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name       string
+		send       func(w *httptest.ResponseRecorder) error
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "Data",
+			send: func(w *httptest.ResponseRecorder) error {
+				return OK(w, user{ID: 1, Name: "Go Loop"})
+			},
+			wantStatus: StatusOK,
+			want:       `{"data":{"id":1,"name":"Go Loop"}}` + "\n",
+		},
+		{
+			name: "Slice with meta",
+			send: func(w *httptest.ResponseRecorder) error {
+				return OK(w, []int{1, 2}, WithMeta("total", 2))
+			},
+			wantStatus: StatusOK,
+			want:       `{"data":[1,2],"meta":{"total":2}}` + "\n",
+		},
+		{
+			name: "Nil slice",
+			send: func(w *httptest.ResponseRecorder) error {
+				var data []user
+				return OK(w, data, WithStatusAccepted())
+			},
+			wantStatus: StatusAccepted,
+			want:       `{"data":null}` + "\n",
+		},
+		{
+			name: "Envelope",
+			send: func(w *httptest.ResponseRecorder) error {
+				return SendEnvelope(w, Envelope[[]int]{
+					Data: []int{1},
+					Meta: R{"page": 1},
+					Errors: []ErrorResponse{
+						{Code: StatusConflict, Message: "duplicate"},
+					},
+				}, WithMeta("v", "1"))
+			},
+			wantStatus: StatusOK,
+			want: `{"data":[1],"meta":{"page":1,"v":"1"},` +
+				`"errors":[{"code":409,"message":"duplicate"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := tt.send(w); err != nil {
+				t.Fatalf("send error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
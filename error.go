@@ -1,5 +1,23 @@
 package resp
 
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrEncodingFailed is returned (wrapped) by the response methods when
+// the data can't be encoded, e.g. a value that json.Marshal doesn't
+// support or the error of the custom JSON encoder. The returned error
+// also wraps the cause, e.g. *json.UnsupportedTypeError.
+var ErrEncodingFailed = errors.New("encoding failed")
+
+// StatusCoder is implemented by the errors that know the status code
+// of the response, e.g. a not found error. See the Err function.
+type StatusCoder interface {
+	StatusCode() int
+}
+
 // ErrorResponse represents an error response.
 type ErrorResponse struct {
 	Code    int    `json:"code"`    // error code
@@ -26,3 +44,101 @@ func newErrorResponse(status int, message ...string) *ErrorResponse {
 		Message: msg,
 	}
 }
+
+// errorRegistry holds the status codes of the errors
+// registered with RegisterErrorStatus.
+var errorRegistry struct {
+	sync.RWMutex
+	entries []registeredError
+}
+
+// registeredError is the error registered with its status code.
+type registeredError struct {
+	target error
+	code   int
+}
+
+// RegisterErrorStatus registers the status code of the error responses
+// (see Err and HandlerFunc) of the errors that match the target (see
+// errors.Is), e.g. the sentinel errors of other packages that can't
+// implement StatusCoder. The StatusCoder of the error takes precedence,
+// and the first registered matching target wins. Registering the target
+// again replaces its status code; the invalid status codes are ignored.
+//
+// It is intended to be called at application startup.
+//
+// Example Usage:
+//
+//	resp.RegisterErrorStatus(sql.ErrNoRows, resp.StatusNotFound)
+//	resp.RegisterErrorStatus(context.DeadlineExceeded,
+//	    resp.StatusGatewayTimeout)
+func RegisterErrorStatus(target error, code int) {
+	if target == nil || !validStatus(code) {
+		return
+	}
+
+	errorRegistry.Lock()
+	defer errorRegistry.Unlock()
+
+	for i, e := range errorRegistry.entries {
+		if e.target == target {
+			errorRegistry.entries[i].code = code
+			return
+		}
+	}
+
+	errorRegistry.entries = append(errorRegistry.entries,
+		registeredError{target: target, code: code})
+}
+
+// errorStatus returns the status code of the first error in the chain
+// that implements StatusCoder, or of the first registered error that
+// matches it (see RegisterErrorStatus), or StatusInternalServerError.
+func errorStatus(err error) int {
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		if code := coder.StatusCode(); validStatus(code) {
+			return code
+		}
+	}
+
+	errorRegistry.RLock()
+	defer errorRegistry.RUnlock()
+
+	for _, e := range errorRegistry.entries {
+		if errors.Is(err, e.target) {
+			return e.code
+		}
+	}
+
+	return StatusInternalServerError
+}
+
+// encodingError is the error of an encoder. It keeps the message of the
+// cause with the context and wraps both ErrEncodingFailed and the cause.
+type encodingError struct {
+	context string
+	err     error
+}
+
+// Error returns the context with the message of the cause.
+func (e *encodingError) Error() string {
+	return e.context + ": " + e.err.Error()
+}
+
+// Unwrap returns ErrEncodingFailed and the cause.
+func (e *encodingError) Unwrap() []error {
+	return []error{ErrEncodingFailed, e.err}
+}
+
+// encodeError returns the error of the encoder with the context. The
+// errors of writing the body (e.g. ErrWriteTimeout) returned through
+// the encoder aren't encoding errors, so they're wrapped as is.
+func (r *Response) encodeError(context string, err error) error {
+	if errors.Is(err, ErrBodyTooLarge) || errors.Is(err, ErrAlreadyWritten) ||
+		(r.writeErr != nil && errors.Is(err, r.writeErr)) {
+		return fmt.Errorf("%s: %w", context, err)
+	}
+
+	return &encodingError{context: context, err: err}
+}
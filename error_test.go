@@ -1,6 +1,14 @@
 package resp
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
 
 // TestNewErrorMessage tests the newErrorMessage function.
 func TestNewErrorMessage(t *testing.T) {
@@ -61,3 +69,159 @@ func TestErrorResponse_Unpack(t *testing.T) {
 		t.Errorf("Unpack() message = %s, want %s", message, "OK")
 	}
 }
+
+// notFoundError is an error with the status code used in tests.
+type notFoundError struct{ id int }
+
+// Error returns the message of the error.
+func (e notFoundError) Error() string {
+	return fmt.Sprintf("user %d not found", e.id)
+}
+
+// StatusCode returns StatusNotFound.
+func (e notFoundError) StatusCode() int { return StatusNotFound }
+
+// badStatusError is an error with the invalid status code.
+type badStatusError struct{}
+
+// Error returns the message of the error.
+func (badStatusError) Error() string { return "bad status" }
+
+// StatusCode returns the invalid status code.
+func (badStatusError) StatusCode() int { return 42 }
+
+// TestErr tests the Err function.
+func TestErr(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		opts       []Option
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "Plain error",
+			err:        errors.New("failed"),
+			wantStatus: StatusInternalServerError,
+			want:       `{"code":500,"message":"failed"}` + "\n",
+		},
+		{
+			name:       "Wrapped status coder",
+			err:        fmt.Errorf("get user: %w", notFoundError{7}),
+			wantStatus: StatusNotFound,
+			want: `{"code":404,` +
+				`"message":"get user: user 7 not found"}` + "\n",
+		},
+		{
+			name:       "Status option",
+			err:        notFoundError{7},
+			opts:       []Option{WithStatusGone()},
+			wantStatus: StatusGone,
+			want:       `{"code":410,"message":"user 7 not found"}` + "\n",
+		},
+		{
+			name:       "Invalid status",
+			err:        badStatusError{},
+			wantStatus: StatusInternalServerError,
+			want:       `{"code":500,"message":"bad status"}` + "\n",
+		},
+		{
+			name:       "Nil error",
+			wantStatus: StatusInternalServerError,
+			want: `{"code":500,"message":"` +
+				StatusText(StatusInternalServerError) + `"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := Err(w, tt.err, tt.opts...); err != nil {
+				t.Fatalf("Err() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestError_ErrorMessage tests the Error function with the error message.
+func TestError_ErrorMessage(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := Error(w, 7, errors.New("invalid input"),
+		WithStatus(StatusBadRequest))
+	if err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+
+	want := `{"code":7,"message":"invalid input"}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
+
+// TestErrEncodingFailed tests that the encoding errors
+// wrap ErrEncodingFailed and the cause.
+func TestErrEncodingFailed(t *testing.T) {
+	cause := errors.New("custom encoder error")
+	tests := []struct {
+		name string
+		send func(w *httptest.ResponseRecorder) error
+	}{
+		{
+			name: "Unsupported type",
+			send: func(w *httptest.ResponseRecorder) error {
+				return JSON(w, R{"fn": func() {}})
+			},
+		},
+		{
+			name: "Custom encoder",
+			send: func(w *httptest.ResponseRecorder) error {
+				enc := func(io.Writer, any) error { return cause }
+				return JSON(w, R{}, ApplyJSONEncoder(enc))
+			},
+		},
+		{
+			name: "CSV",
+			send: func(w *httptest.ResponseRecorder) error {
+				return CSV(w, [][]any{{"a"}},
+					WithLocaleFormatter(LocaleFormatter{Delimiter: '"'}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.send(httptest.NewRecorder())
+			if !errors.Is(err, ErrEncodingFailed) {
+				t.Errorf("error = %v, want %v", err, ErrEncodingFailed)
+			}
+		})
+	}
+
+	var typeErr *json.UnsupportedTypeError
+	err := JSON(httptest.NewRecorder(), R{"fn": func() {}})
+	if !errors.As(err, &typeErr) {
+		t.Errorf("error = %v, want *json.UnsupportedTypeError", err)
+	}
+}
+
+// TestErrEncodingFailed_WriteError tests that the errors
+// of writing the body aren't encoding errors.
+func TestErrEncodingFailed_WriteError(t *testing.T) {
+	err := JSON(httptest.NewRecorder(), R{"data": strings.Repeat("x", 64)},
+		WithMaxBodySize(8))
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("error = %v, want %v", err, ErrBodyTooLarge)
+	}
+
+	if errors.Is(err, ErrEncodingFailed) {
+		t.Errorf("error = %v, want not %v", err, ErrEncodingFailed)
+	}
+}
@@ -0,0 +1,189 @@
+package resp
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorFormat is the format of the error responses sent by
+// the Error and Err methods.
+type ErrorFormat string
+
+// Formats of the error responses.
+const (
+	// ErrorFormatJSON sends the ErrorResponse as JSON (default).
+	ErrorFormatJSON ErrorFormat = "json"
+
+	// ErrorFormatText sends the error message as plain text.
+	ErrorFormatText ErrorFormat = "text"
+
+	// ErrorFormatHTML sends the error page rendered with the error
+	// template (see WithErrorTemplate).
+	ErrorFormatHTML ErrorFormat = "html"
+)
+
+// ErrorPage is the data of the error template.
+type ErrorPage struct {
+	Status     int    // status code of the response
+	StatusText string // status text, e.g. "Not Found"
+	Code       int    // error code
+	Message    string // error message
+}
+
+// defaultErrorTemplate is the error template used
+// if no template is set with WithErrorTemplate.
+var defaultErrorTemplate = template.Must(template.New("error").Parse(
+	`<!DOCTYPE html>
+<html>
+<head><title>{{ .Status }} {{ .StatusText }}</title></head>
+<body>
+<h1>{{ .Status }} {{ .StatusText }}</h1>
+<p>{{ .Message }}</p>
+</body>
+</html>
+`))
+
+// WithErrorFormat sets the format of the error responses sent by
+// the Error and Err methods. By default the errors are sent as JSON.
+// With WithErrorNegotiation, the format is used when several formats
+// are equally acceptable or the Accept header is missing.
+//
+// Example Usage:
+//
+//	resp.SetDefaults(resp.WithErrorFormat(resp.ErrorFormatText))
+//	resp.Error(w, 404, "user not found")
+//	// Content-Type: text/plain
+//	// user not found
+func WithErrorFormat(format ErrorFormat) Option {
+	return func(r *Response) *Response {
+		r.errorFormat = format
+		return r
+	}
+}
+
+// WithErrorNegotiation selects the format of the error responses
+// by the Accept header of the request: ErrorFormatJSON for
+// application/json, ErrorFormatHTML for text/html and ErrorFormatText
+// for text/plain. If the header is missing or no format is acceptable,
+// the format set with WithErrorFormat (JSON by default) is used.
+//
+// The Accept header is merged into the Vary header of the error
+// responses (see WithoutAutoVary).
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.Err(w, err, resp.WithErrorNegotiation(r))
+//	    // the browsers get the HTML page, the API clients get JSON
+//	}
+func WithErrorNegotiation(req *http.Request) Option {
+	return func(r *Response) *Response {
+		r.errorRequest = req
+		return r
+	}
+}
+
+// WithErrorTemplate sets the template of the HTML error pages and
+// selects ErrorFormatHTML if no format is set. The template is executed
+// with ErrorPage. If the template defines a template named after the
+// status code (e.g. "404"), it is used for the responses with this
+// status code, so custom pages can be defined for the particular
+// errors.
+//
+// Example Usage:
+//
+//	var pages = template.Must(template.New("error").Parse(
+//	    `<h1>{{ .StatusText }}</h1>` +
+//	    `{{ define "404" }}<h1>Nothing here</h1>{{ end }}`))
+//
+//	resp.SetDefaults(resp.WithErrorTemplate(pages))
+func WithErrorTemplate(tmpl *template.Template) Option {
+	return func(r *Response) *Response {
+		r.errorTemplate = tmpl
+		if r.errorFormat == "" {
+			r.errorFormat = ErrorFormatHTML
+		}
+		return r
+	}
+}
+
+// writeError sends the error response in the error format.
+func (r *Response) writeError(code int, message string) error {
+	switch r.negotiateErrorFormat() {
+	case ErrorFormatText:
+		return r.String(message)
+	case ErrorFormatHTML:
+		return r.writeErrorPage(code, message)
+	}
+
+	return r.JSON(newErrorResponse(code, message))
+}
+
+// writeErrorPage renders the error template and sends
+// the result as an HTML response.
+func (r *Response) writeErrorPage(code int, message string) error {
+	tmpl := r.errorTemplate
+	if tmpl == nil {
+		tmpl = defaultErrorTemplate
+	}
+
+	if t := tmpl.Lookup(strconv.Itoa(r.statusCode)); t != nil {
+		tmpl = t
+	}
+
+	page := ErrorPage{
+		Status:     r.statusCode,
+		StatusText: StatusText(r.statusCode),
+		Code:       code,
+		Message:    message,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, page); err != nil {
+		return err
+	}
+
+	return r.HTML(buf.String())
+}
+
+// negotiateErrorFormat returns the format of the error response.
+func (r *Response) negotiateErrorFormat() ErrorFormat {
+	format := r.errorFormat
+	if format == "" {
+		format = ErrorFormatJSON
+	}
+
+	if r.errorRequest == nil {
+		return format
+	}
+
+	r.varyOn(HeaderAccept)
+	accept := r.errorRequest.Header.Values(HeaderAccept)
+	if len(accept) == 0 {
+		return format
+	}
+
+	// The configured format wins when the qualities are equal.
+	formats := []ErrorFormat{
+		format, ErrorFormatJSON, ErrorFormatHTML, ErrorFormatText,
+	}
+	mediaTypes := map[ErrorFormat]string{
+		ErrorFormatJSON: MIMEApplicationJSON,
+		ErrorFormatHTML: MIMETextHTML,
+		ErrorFormatText: MIMETextPlain,
+	}
+
+	ranges := parseAccept(strings.Join(accept, ","))
+	best, bestQ := format, 0.0
+	for _, f := range formats {
+		v := Variant{MediaType: mediaTypes[f]}
+		if q := quality(ranges, v); q > bestQ {
+			best, bestQ = f, q
+		}
+	}
+
+	return best
+}
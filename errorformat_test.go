@@ -0,0 +1,167 @@
+package resp
+
+import (
+	"errors"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestError_Formats tests the error formats of the Error function.
+func TestError_Formats(t *testing.T) {
+	pages := template.Must(template.New("error").Parse(
+		`<p>{{ .Status }}: {{ .Message }}</p>` +
+			`{{ define "404" }}<p>Nothing here</p>{{ end }}`))
+
+	tests := []struct {
+		name        string
+		accept      string
+		opts        []Option
+		status      int
+		wantType    string
+		want        string
+		wantVary    string
+		wantContain string
+	}{
+		{
+			name:     "Default JSON",
+			status:   StatusBadRequest,
+			wantType: MIMEApplicationJSON,
+			want:     `{"code":400,"message":"bad input"}` + "\n",
+		},
+		{
+			name:     "Text format",
+			opts:     []Option{WithErrorFormat(ErrorFormatText)},
+			status:   StatusBadRequest,
+			wantType: MIMETextPlain,
+			want:     "bad input",
+		},
+		{
+			name:        "Default HTML page",
+			opts:        []Option{WithErrorFormat(ErrorFormatHTML)},
+			status:      StatusBadRequest,
+			wantType:    MIMETextHTML,
+			wantContain: "<h1>400 Bad Request</h1>",
+		},
+		{
+			name:     "Custom template",
+			opts:     []Option{WithErrorTemplate(pages)},
+			status:   StatusBadRequest,
+			wantType: MIMETextHTML,
+			want:     "<p>400: bad input</p>",
+		},
+		{
+			name:     "Template for status",
+			opts:     []Option{WithErrorTemplate(pages)},
+			status:   StatusNotFound,
+			wantType: MIMETextHTML,
+			want:     "<p>Nothing here</p>",
+		},
+		{
+			name:        "Negotiated HTML",
+			accept:      "text/html,application/xhtml+xml,*/*;q=0.8",
+			status:      StatusBadRequest,
+			wantType:    MIMETextHTML,
+			wantContain: "bad input",
+			wantVary:    HeaderAccept,
+		},
+		{
+			name:     "Negotiated text",
+			accept:   "text/plain",
+			status:   StatusBadRequest,
+			wantType: MIMETextPlain,
+			want:     "bad input",
+			wantVary: HeaderAccept,
+		},
+		{
+			name:     "Any uses configured format",
+			accept:   "*/*",
+			opts:     []Option{WithErrorFormat(ErrorFormatText)},
+			status:   StatusBadRequest,
+			wantType: MIMETextPlain,
+			want:     "bad input",
+			wantVary: HeaderAccept,
+		},
+		{
+			name:     "Not acceptable uses configured format",
+			accept:   "image/png",
+			status:   StatusBadRequest,
+			wantType: MIMEApplicationJSON,
+			want:     `{"code":400,"message":"bad input"}` + "\n",
+			wantVary: HeaderAccept,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			opts := append([]Option{WithStatus(tt.status)}, tt.opts...)
+			if tt.accept != "" {
+				req.Header.Set(HeaderAccept, tt.accept)
+				opts = append(opts, WithErrorNegotiation(req))
+			}
+
+			w := httptest.NewRecorder()
+			if err := Error(w, tt.status, "bad input", opts...); err != nil {
+				t.Fatalf("Error() error = %v", err)
+			}
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+
+			ct := w.Header().Get(HeaderContentType)
+			if !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+
+			body := w.Body.String()
+			if tt.want != "" && body != tt.want {
+				t.Errorf("body = %q, want %q", body, tt.want)
+			}
+
+			if !strings.Contains(body, tt.wantContain) {
+				t.Errorf("body = %q, want to contain %q",
+					body, tt.wantContain)
+			}
+
+			if got := w.Header().Get(HeaderVary); got != tt.wantVary {
+				t.Errorf("Vary = %q, want %q", got, tt.wantVary)
+			}
+		})
+	}
+}
+
+// TestErr_TextFormat tests the Err function with the text format.
+func TestErr_TextFormat(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := Err(w, errors.New("failed"), WithErrorFormat(ErrorFormatText))
+	if err != nil {
+		t.Fatalf("Err() error = %v", err)
+	}
+
+	if w.Code != StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, StatusInternalServerError)
+	}
+
+	if got := w.Body.String(); got != "failed" {
+		t.Errorf("body = %q, want %q", got, "failed")
+	}
+}
+
+// TestError_TemplateFailure tests that nothing is sent
+// if the error template fails.
+func TestError_TemplateFailure(t *testing.T) {
+	broken := template.Must(template.New("error").Parse(`{{ .Missing }}`))
+
+	w := httptest.NewRecorder()
+	err := Error(w, 500, "failed", WithErrorTemplate(broken))
+	if err == nil {
+		t.Fatal("Error() error = nil, want error")
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", w.Body.String())
+	}
+}
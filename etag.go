@@ -0,0 +1,80 @@
+package resp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ETagStrong returns the strong ETag computed from the hash of the data,
+// e.g. `"5d41402abc4b2a76b9719d911017c592"`. Equal data always has the
+// same ETag, so it is suitable for the byte-exact representations
+// (the Range requests and If-Match).
+//
+// Example Usage:
+//
+//	resp.Blob(w, "image/png", png, resp.AddETag(resp.ETagStrong(png)))
+func ETagStrong(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ETagWeak returns the weak ETag computed from the hash of the parts
+// that identify the version of the resource, e.g. its ID and the
+// revision, e.g. `W/"1a79a4d60de6718e8e5b326e338ae533"`. The weak ETag
+// tells that the representations are semantically equivalent, so it
+// is suitable for the responses that are encoded differently (e.g.
+// compressed or formatted) but have the same data.
+//
+// Example Usage:
+//
+//	etag := resp.ETagWeak("user", strconv.Itoa(user.ID),
+//	    strconv.Itoa(user.Revision))
+func ETagWeak(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// The parts are separated, so ("ab", "c") and ("a", "bc")
+		// have different ETags.
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	sum := h.Sum(nil)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// normalizeETag returns the ETag as the quoted string with the optional
+// W/ prefix, quoting the value if needed, e.g. `W/"v1"` for "W/v1". It
+// returns false if the value has the characters not allowed in the
+// ETag (RFC 9110, 8.8.3): the double quotes, the spaces and the
+// control characters.
+func normalizeETag(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	prefix := ""
+	if strings.HasPrefix(value, "W/") {
+		prefix, value = "W/", value[2:]
+	}
+
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c == '"' || c == 0x7f {
+			return "", false
+		}
+	}
+
+	return prefix + `"` + value + `"`, true
+}
+
+// etagValue returns the normalized ETag (see normalizeETag),
+// or "" if the value is empty or isn't a valid ETag.
+func etagValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+
+	etag, _ := normalizeETag(value)
+	return etag
+}
@@ -0,0 +1,43 @@
+package resp
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestETagStrong tests the ETagStrong function.
+func TestETagStrong(t *testing.T) {
+	etag := ETagStrong([]byte("hello"))
+	if len(etag) != 34 || etag[0] != '"' || etag[33] != '"' {
+		t.Errorf("ETagStrong() = %s, want a quoted hash", etag)
+	}
+
+	if ETagStrong([]byte("hello")) != etag {
+		t.Error("ETagStrong() isn't stable")
+	}
+
+	if ETagStrong([]byte("hello!")) == etag {
+		t.Error("ETagStrong() is the same for different data")
+	}
+}
+
+// TestETagWeak tests the ETagWeak function.
+func TestETagWeak(t *testing.T) {
+	etag := ETagWeak("user", "7", "3")
+	if !strings.HasPrefix(etag, `W/"`) || !strings.HasSuffix(etag, `"`) {
+		t.Errorf("ETagWeak() = %s, want a weak quoted hash", etag)
+	}
+
+	if ETagWeak("user", "7", "3") != etag {
+		t.Error("ETagWeak() isn't stable")
+	}
+
+	if ETagWeak("ab", "c") == ETagWeak("a", "bc") {
+		t.Error("ETagWeak() doesn't separate the parts")
+	}
+
+	// The weak ETag matches itself in the If-None-Match header.
+	if !etagMatch(etag, etag) {
+		t.Errorf("etagMatch(%s) = false, want true", etag)
+	}
+}
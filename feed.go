@@ -0,0 +1,372 @@
+package resp
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Feed is a web feed sent by RSS or Atom.
+type Feed struct {
+	Title       string    // title of the feed
+	Link        string    // URL of the site of the feed
+	FeedURL     string    // URL of the feed itself (Atom "self" link)
+	ID          string    // Atom ID of the feed; Link is used if empty
+	Description string    // description (RSS) or subtitle (Atom)
+	Language    string    // language of the feed, e.g. "en-us"
+	Author      string    // name of the author
+	Copyright   string    // copyright notice
+	Updated     time.Time // last update; the latest item's if zero
+	Items       []FeedItem
+}
+
+// FeedItem is an item (RSS) or an entry (Atom) of the feed.
+type FeedItem struct {
+	Title       string    // title of the item
+	Link        string    // URL of the item
+	GUID        string    // unique ID of the item; Link is used if empty
+	Description string    // summary of the item
+	Content     string    // full HTML content of the item (Atom)
+	Author      string    // name of the author
+	Categories  []string  // categories of the item
+	Published   time.Time // publication time
+	Updated     time.Time // last update; Published is used if zero
+	Enclosure   *FeedEnclosure
+}
+
+// FeedEnclosure is a media file attached to the feed item,
+// e.g. a podcast episode.
+type FeedEnclosure struct {
+	URL    string // URL of the file
+	Length int64  // size of the file in bytes
+	Type   string // MIME type of the file, e.g. "audio/mpeg"
+}
+
+// updated returns the last update time of the feed.
+func (f *Feed) updated() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+
+	var latest time.Time
+	for _, item := range f.Items {
+		if t := item.updated(); t.After(latest) {
+			latest = t
+		}
+	}
+
+	return latest
+}
+
+// updated returns the last update time of the item.
+func (i *FeedItem) updated() time.Time {
+	if !i.Updated.IsZero() {
+		return i.Updated
+	}
+
+	return i.Published
+}
+
+// rssFeed is the XML document of the RSS 2.0 feed.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr,omitempty"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel is the channel of the RSS 2.0 feed.
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Self          *atomLink `xml:"atom:link,omitempty"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	Copyright     string    `xml:"copyright,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem is the item of the RSS 2.0 feed.
+type rssItem struct {
+	Title       string        `xml:"title,omitempty"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Author      string        `xml:"author,omitempty"`
+	Categories  []string      `xml:"category"`
+	GUID        *rssGUID      `xml:"guid,omitempty"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+// rssGUID is the unique ID of the RSS item.
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// rssEnclosure is the enclosure of the RSS item.
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// rss returns the RSS 2.0 document of the feed.
+func (f *Feed) rss() *rssFeed {
+	doc := &rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Language:    f.Language,
+			Copyright:   f.Copyright,
+		},
+	}
+
+	if f.FeedURL != "" {
+		doc.Atom = "http://www.w3.org/2005/Atom"
+		doc.Channel.Self = &atomLink{
+			Href: f.FeedURL,
+			Rel:  "self",
+			Type: MIMEApplicationRSSXML,
+		}
+	}
+
+	if t := f.updated(); !t.IsZero() {
+		doc.Channel.LastBuildDate = t.Format(time.RFC1123Z)
+	}
+
+	for _, item := range f.Items {
+		ri := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Author:      item.Author,
+			Categories:  item.Categories,
+		}
+
+		switch {
+		case item.GUID != "":
+			ri.GUID = &rssGUID{Value: item.GUID}
+		case item.Link != "":
+			ri.GUID = &rssGUID{IsPermaLink: true, Value: item.Link}
+		}
+
+		if !item.Published.IsZero() {
+			ri.PubDate = item.Published.Format(time.RFC1123Z)
+		}
+
+		if e := item.Enclosure; e != nil {
+			ri.Enclosure = &rssEnclosure{
+				URL:    e.URL,
+				Length: e.Length,
+				Type:   e.Type,
+			}
+		}
+
+		doc.Channel.Items = append(doc.Channel.Items, ri)
+	}
+
+	return doc
+}
+
+// atomFeed is the XML document of the Atom feed.
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Rights   string      `xml:"rights,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+// atomEntry is the entry of the Atom feed.
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+// atomLink is the link of the Atom feed or entry.
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+// atomPerson is the author of the Atom feed or entry.
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// atomCategory is the category of the Atom entry.
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// atomText is the text construct of the Atom entry.
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// atom returns the Atom document of the feed.
+func (f *Feed) atom() *atomFeed {
+	doc := &atomFeed{
+		Lang:     f.Language,
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.updated().UTC().Format(time.RFC3339),
+		Rights:   f.Copyright,
+	}
+
+	if doc.ID == "" {
+		doc.ID = f.Link
+	}
+
+	if f.Author != "" {
+		doc.Author = &atomPerson{Name: f.Author}
+	}
+
+	if f.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.Link})
+	}
+
+	if f.FeedURL != "" {
+		doc.Links = append(doc.Links, atomLink{
+			Href: f.FeedURL,
+			Rel:  "self",
+			Type: MIMEApplicationAtomXML,
+		})
+	}
+
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:      item.GUID,
+			Title:   item.Title,
+			Updated: item.updated().UTC().Format(time.RFC3339),
+		}
+
+		if entry.ID == "" {
+			entry.ID = item.Link
+		}
+
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.UTC().Format(time.RFC3339)
+		}
+
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+
+		if item.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.Link})
+		}
+
+		if e := item.Enclosure; e != nil {
+			entry.Links = append(entry.Links, atomLink{
+				Href:   e.URL,
+				Rel:    "enclosure",
+				Type:   e.Type,
+				Length: strconv.FormatInt(e.Length, 10),
+			})
+		}
+
+		for _, c := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{c})
+		}
+
+		if item.Description != "" {
+			entry.Summary = &atomText{Value: item.Description}
+		}
+
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return doc
+}
+
+// RSS sends the feed as an RSS 2.0 document (application/rss+xml).
+// The dates are formatted according to RFC 822, and the Link of the
+// items without a GUID is used as the permanent GUID.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    feed := resp.Feed{
+//	        Title:       "Go Loop Blog",
+//	        Link:        "https://example.com/blog",
+//	        Description: "News of the Go Loop project",
+//	    }
+//	    for _, post := range posts {
+//	        feed.Items = append(feed.Items, resp.FeedItem{
+//	            Title:     post.Title,
+//	            Link:      post.URL,
+//	            Published: post.Date,
+//	        })
+//	    }
+//
+//	    resp.RSS(w, feed)
+//	}
+func RSS(w http.ResponseWriter, feed Feed, opts ...Option) error {
+	return NewResponse(w, opts...).RSS(feed)
+}
+
+// RSS sends the feed as an RSS 2.0 document.
+// See the RSS function for details.
+func (r *Response) RSS(feed Feed) error {
+	return r.writeFeed(feed.rss(), MIMEApplicationRSSXMLCharsetUTF8)
+}
+
+// Atom sends the feed as an Atom document (application/atom+xml).
+// The dates are formatted according to RFC 3339; the Link of the feed
+// and of the items without the ID (GUID) is used as the ID.
+//
+// Example Usage:
+//
+//	resp.Atom(w, feed)
+func Atom(w http.ResponseWriter, feed Feed, opts ...Option) error {
+	return NewResponse(w, opts...).Atom(feed)
+}
+
+// Atom sends the feed as an Atom document.
+// See the Atom function for details.
+func (r *Response) Atom(feed Feed) error {
+	return r.writeFeed(feed.atom(), MIMEApplicationAtomXMLCharsetUTF8)
+}
+
+// writeFeed encodes the XML document of the feed and sends it.
+func (r *Response) writeFeed(doc any, contentType string) (err error) {
+	defer r.finish(&err)
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return r.encodeError("failed to encode feed", err)
+	}
+	data = append([]byte(xml.Header), data...)
+
+	r.prepare(StatusOK, contentType)
+	r.setBodyDigest(data)
+	r.writeHeader(r.statusCode)
+	_, err = r.write(data)
+	return err
+}
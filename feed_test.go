@@ -0,0 +1,124 @@
+package resp
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testFeed returns the feed used in tests.
+func testFeed() Feed {
+	published := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	return Feed{
+		Title:       "Go Loop Blog",
+		Link:        "https://example.com/blog",
+		FeedURL:     "https://example.com/blog/feed",
+		Description: "News & updates",
+		Language:    "en-us",
+		Author:      "Go Loop",
+		Items: []FeedItem{
+			{
+				Title:       "Release 1.0",
+				Link:        "https://example.com/blog/1",
+				Description: "The <b>first</b> release",
+				Content:     "<p>Hello</p>",
+				Categories:  []string{"release"},
+				Published:   published,
+			},
+			{
+				Title:     "Episode 1",
+				GUID:      "urn:episode:1",
+				Published: published.Add(time.Hour),
+				Enclosure: &FeedEnclosure{
+					URL:    "https://example.com/ep1.mp3",
+					Length: 1024,
+					Type:   "audio/mpeg",
+				},
+			},
+		},
+	}
+}
+
+// TestRSS tests the RSS function.
+func TestRSS(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := RSS(w, testFeed()); err != nil {
+		t.Fatalf("RSS() error = %v", err)
+	}
+
+	got := w.Header().Get(HeaderContentType)
+	if got != MIMEApplicationRSSXMLCharsetUTF8 {
+		t.Errorf("Content-Type = %q, want %q",
+			got, MIMEApplicationRSSXMLCharsetUTF8)
+	}
+
+	body := w.Body.String()
+	wants := []string{
+		xml.Header,
+		`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`,
+		`<atom:link href="https://example.com/blog/feed" rel="self" ` +
+			`type="application/rss+xml"></atom:link>`,
+		`<description>News &amp; updates</description>`,
+		`<lastBuildDate>Fri, 01 Mar 2024 11:00:00 +0000</lastBuildDate>`,
+		`<guid isPermaLink="true">https://example.com/blog/1</guid>`,
+		`<guid isPermaLink="false">urn:episode:1</guid>`,
+		`<pubDate>Fri, 01 Mar 2024 10:00:00 +0000</pubDate>`,
+		`<category>release</category>`,
+		`<enclosure url="https://example.com/ep1.mp3" length="1024" ` +
+			`type="audio/mpeg"></enclosure>`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(body, want) {
+			t.Errorf("RSS() body doesn't contain %s\n%s", want, body)
+		}
+	}
+
+	var doc struct {
+		Items []struct {
+			Title string `xml:"title"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("RSS() body isn't valid XML: %v", err)
+	}
+
+	if len(doc.Items) != 2 || doc.Items[1].Title != "Episode 1" {
+		t.Errorf("RSS() items = %+v", doc.Items)
+	}
+}
+
+// TestAtom tests the Atom function.
+func TestAtom(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := Atom(w, testFeed()); err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+
+	got := w.Header().Get(HeaderContentType)
+	if got != MIMEApplicationAtomXMLCharsetUTF8 {
+		t.Errorf("Content-Type = %q, want %q",
+			got, MIMEApplicationAtomXMLCharsetUTF8)
+	}
+
+	body := w.Body.String()
+	wants := []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en-us">`,
+		`<id>https://example.com/blog</id>`,
+		`<updated>2024-03-01T11:00:00Z</updated>`,
+		`<link href="https://example.com/blog/feed" rel="self" ` +
+			`type="application/atom+xml"></link>`,
+		`<id>urn:episode:1</id>`,
+		`<link href="https://example.com/ep1.mp3" rel="enclosure" ` +
+			`type="audio/mpeg" length="1024"></link>`,
+		`<category term="release"></category>`,
+		`<summary>The &lt;b&gt;first&lt;/b&gt; release</summary>`,
+		`<content type="html">&lt;p&gt;Hello&lt;/p&gt;</content>`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(body, want) {
+			t.Errorf("Atom() body doesn't contain %s\n%s", want, body)
+		}
+	}
+}
@@ -11,7 +11,12 @@ import (
 // objects, an array of objects, or a map. When a slice or an array
 // is provided, the function returns a slice of `R` maps. If the data
 // is not a struct, slice/array of structs, or map with string keys,
-// it returns the original data unchanged.
+// it returns the original data unchanged. Slices of maps with string
+// keys (e.g. the result of FlattenEmbedded) are processed as well.
+//
+// The fields promoted from the embedded structs can be selected by
+// their names, as with encoding/json; the embedded struct itself is
+// selected by its type name.
 //
 // Parameters:
 //   - data: The input data from which fields will be extracted. It
@@ -75,42 +80,9 @@ import (
 //		}
 //	}
 func OnlyFields(data any, fields ...string) any {
-	rv := reflect.ValueOf(data)
-
-	switch rv.Kind() {
-	case reflect.Ptr:
-		rv = rv.Elem()
-		if rv.Kind() == reflect.Struct {
-			return onlyFields(rv.Interface(), fields...)
-		}
-	case reflect.Slice, reflect.Array:
-		length := rv.Len()
-		if length > 0 {
-			elemKind := rv.Index(0).Kind()
-			if elemKind == reflect.Ptr {
-				elemKind = rv.Index(0).Elem().Kind()
-			}
-			if elemKind == reflect.Struct {
-				result := make([]R, length)
-				for i := 0; i < length; i++ {
-					elem := rv.Index(i)
-					if elem.Kind() == reflect.Ptr {
-						elem = elem.Elem()
-					}
-					result[i] = onlyFields(elem.Interface(), fields...)
-				}
-				return result
-			}
-		}
-	case reflect.Struct:
-		return onlyFields(data, fields...)
-	case reflect.Map:
-		if rv.Type().Key().Kind() == reflect.String {
-			return onlyFieldsMap(data.(map[string]any), fields...)
-		}
-	}
-
-	return data
+	return transformFields(data,
+		func(rv reflect.Value) R { return onlyFields(rv, fields) },
+		func(m map[string]any) R { return onlyFieldsMap(m, fields...) })
 }
 
 // ExcludeFields removes the specified fields from the provided data
@@ -185,38 +157,81 @@ func OnlyFields(data any, fields ...string) any {
 //		}
 //	}
 func ExcludeFields(data any, fields ...string) any {
+	excluded := fieldSet(fields)
+	return transformFields(data,
+		func(rv reflect.Value) R { return excludeFields(rv, excluded) },
+		func(m map[string]any) R { return excludeFieldsMap(m, fields...) })
+}
+
+// transformFields is the traversal shared by the field helpers: it
+// applies the struct function to the struct (or the pointer to it) and
+// to each element of the slice or array of structs, returning an `R`
+// map or a slice of them. The map function (if it isn't nil) is applied
+// the same way to the maps with string keys. Other data is returned
+// unchanged.
+func transformFields(
+	data any,
+	structFn func(rv reflect.Value) R,
+	mapFn func(m map[string]any) R,
+) any {
 	rv := reflect.ValueOf(data)
 
 	switch rv.Kind() {
 	case reflect.Ptr:
 		rv = rv.Elem()
 		if rv.Kind() == reflect.Struct {
-			return excludeFields(rv.Interface(), fields...)
+			return structFn(rv)
 		}
 	case reflect.Slice, reflect.Array:
 		length := rv.Len()
-		if length > 0 {
-			elemKind := rv.Index(0).Kind()
-			if elemKind == reflect.Ptr {
-				elemKind = rv.Index(0).Elem().Kind()
-			}
-			if elemKind == reflect.Struct {
-				result := make([]R, length)
-				for i := 0; i < length; i++ {
-					elem := rv.Index(i)
-					if elem.Kind() == reflect.Ptr {
-						elem = elem.Elem()
-					}
-					result[i] = excludeFields(elem.Interface(), fields...)
+		if length == 0 {
+			break
+		}
+
+		elemKind := rv.Index(0).Kind()
+		if elemKind == reflect.Ptr {
+			elemKind = rv.Index(0).Elem().Kind()
+		}
+
+		if elemKind == reflect.Struct {
+			result := make([]R, length)
+			for i := 0; i < length; i++ {
+				elem := rv.Index(i)
+				if elem.Kind() == reflect.Ptr {
+					elem = elem.Elem()
 				}
-				return result
+
+				// The nil pointers stay nil (null in JSON).
+				if elem.Kind() == reflect.Struct {
+					result[i] = structFn(elem)
+				}
+			}
+			return result
+		}
+
+		if mapFn == nil {
+			break
+		}
+
+		if maps, ok := stringMaps(rv); ok {
+			result := make([]R, length)
+			for i, m := range maps {
+				result[i] = mapFn(m)
 			}
+			return result
 		}
 	case reflect.Struct:
-		return excludeFields(data, fields...)
+		return structFn(rv)
 	case reflect.Map:
-		if rv.Type().Key().Kind() == reflect.String {
-			return excludeFieldsMap(data.(map[string]any), fields...)
+		if mapFn == nil {
+			break
+		}
+
+		switch m := data.(type) {
+		case R:
+			return mapFn(m)
+		case map[string]any:
+			return mapFn(m)
 		}
 	}
 
@@ -224,26 +239,54 @@ func ExcludeFields(data any, fields ...string) any {
 }
 
 // onlyFields extracts only the specified fields from the provided
-// data and returns them as an `R` map.
-func onlyFields(data any, fields ...string) R {
+// struct value and returns them as an `R` map.
+func onlyFields(rv reflect.Value, fields []string) R {
+	info := cachedStructInfo(rv.Type())
 	result := make(R)
 
-	rv := reflect.ValueOf(data)
-	rt := rv.Type()
+	for _, name := range fields {
+		if i, ok := info.byName[name]; ok {
+			result[name] = rv.Field(info.fields[i].index).Interface()
+			continue
+		}
 
-	allowed := make(map[string]bool, len(fields))
-	for _, field := range fields {
-		allowed[field] = true
+		// The promoted field of an embedded struct.
+		if i, ok := info.flatByName[name]; ok {
+			if field, ok := fieldByPath(rv, info.flat[i].path); ok {
+				result[name] = field.Interface()
+			}
+		}
 	}
 
-	for i := 0; i < rv.NumField(); i++ {
-		name := rt.Field(i).Name
-		if allowed[name] {
-			result[name] = rv.Field(i).Interface()
+	return result
+}
+
+// stringMaps returns the elements of the slice or array value as maps
+// with string keys, and false if any element isn't such a map.
+func stringMaps(rv reflect.Value) ([]map[string]any, bool) {
+	maps := make([]map[string]any, rv.Len())
+	for i := range maps {
+		switch m := rv.Index(i).Interface().(type) {
+		case map[string]any:
+			maps[i] = m
+		case R:
+			maps[i] = m
+		default:
+			return nil, false
 		}
 	}
 
-	return result
+	return maps, true
+}
+
+// fieldSet returns the set of the field names.
+func fieldSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+
+	return set
 }
 
 // onlyFieldsMap extracts only the specified fields from the provided
@@ -264,23 +307,15 @@ func onlyFieldsMap(data map[string]any, fields ...string) R {
 	return result
 }
 
-// excludeFields removes the specified fields from the provided data
-// and returns the remaining fields as an `R` map.
-func excludeFields(data any, fields ...string) R {
-	result := make(R)
-
-	rv := reflect.ValueOf(data)
-	rt := rv.Type()
-
-	excluded := make(map[string]bool, len(fields))
-	for _, field := range fields {
-		excluded[field] = true
-	}
+// excludeFields removes the specified fields from the provided struct
+// value and returns the remaining fields as an `R` map.
+func excludeFields(rv reflect.Value, excluded map[string]bool) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(info.fields))
 
-	for i := 0; i < rv.NumField(); i++ {
-		name := rt.Field(i).Name
-		if !excluded[name] {
-			result[name] = rv.Field(i).Interface()
+	for _, field := range info.fields {
+		if !excluded[field.name] {
+			result[field.name] = rv.Field(field.index).Interface()
 		}
 	}
 
@@ -304,3 +339,95 @@ func excludeFieldsMap(data map[string]any, fields ...string) R {
 
 	return result
 }
+
+// RenameFields renames the fields of the provided data according to the
+// names map (old name to new name) and returns the result as an `R` map.
+// The fields that aren't in the map keep their names. This function is
+// useful for shaping responses to legacy client contracts. The input
+// handling is the same as in OnlyFields: the operation can be performed
+// on a single object, a slice of objects, an array of objects, or a map,
+// and other data is returned unchanged.
+//
+// Example Usage:
+//
+//	data := resp.RenameFields(user, map[string]string{
+//		"ID":    "id",
+//		"Email": "email_address",
+//	})
+//	// {"id": 1, "email_address": "user_a@example.com", "IsActive": true}
+func RenameFields(data any, names map[string]string) any {
+	return transformFields(data,
+		func(rv reflect.Value) R { return renameFields(rv, names) },
+		func(m map[string]any) R { return renameFieldsMap(m, names) })
+}
+
+// renameFields renames the fields of the provided struct
+// value and returns them as an `R` map.
+func renameFields(rv reflect.Value, names map[string]string) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(info.fields))
+
+	for _, field := range info.fields {
+		name := field.name
+		if newName, ok := names[name]; ok {
+			name = newName
+		}
+		result[name] = rv.Field(field.index).Interface()
+	}
+
+	return result
+}
+
+// renameFieldsMap renames the keys of the provided map
+// and returns them as an `R` map.
+func renameFieldsMap(data map[string]any, names map[string]string) R {
+	result := make(R, len(data))
+	for key, value := range data {
+		if newKey, ok := names[key]; ok {
+			key = newKey
+		}
+		result[key] = value
+	}
+
+	return result
+}
+
+// FlattenEmbedded returns the fields of the provided data as an `R` map
+// with the fields of the embedded structs promoted to the top level, as
+// encoding/json treats anonymous fields (the shallower field hides the
+// deeper ones, and the ambiguous fields are dropped). The input handling
+// is the same as in OnlyFields. The result can be passed to OnlyFields
+// and ExcludeFields, e.g. to exclude promoted fields.
+//
+// Example Usage:
+//
+//	type Contact struct {
+//		Email string
+//		Phone string
+//	}
+//
+//	type User struct {
+//		ID int
+//		Contact
+//	}
+//
+//	data := resp.ExcludeFields(resp.FlattenEmbedded(users), "Phone")
+//	// [{"ID": 1, "Email": "user_a@example.com"}, ...]
+func FlattenEmbedded(data any) any {
+	return transformFields(data, flattenEmbedded, nil)
+}
+
+// flattenEmbedded returns the fields of the struct value
+// with the fields of the embedded structs promoted.
+func flattenEmbedded(rv reflect.Value) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(info.flat))
+
+	for _, field := range info.flat {
+		if value, ok := fieldByPath(rv, field.path); ok {
+			result[field.name] = value.Interface()
+		}
+	}
+
+	return result
+}
@@ -339,3 +339,158 @@ func TestExcludeFieldsMap(t *testing.T) {
 		t.Errorf("ExcludeFields() = %v, want %v", result, expected)
 	}
 }
+
+// TestRenameFields tests the RenameFields function.
+func TestRenameFields(t *testing.T) {
+	names := map[string]string{"ID": "id", "Email": "email_address"}
+	user := User{
+		ID:       1,
+		Email:    "user@example.com",
+		Password: "secret",
+		IsActive: true,
+	}
+
+	expected := R{
+		"id":            1,
+		"email_address": "user@example.com",
+		"Password":      "secret",
+		"IsActive":      true,
+	}
+
+	tests := []struct {
+		name string
+		data any
+		want any
+	}{
+		{name: "Struct", data: user, want: expected},
+		{name: "Pointer", data: &user, want: expected},
+		{name: "Slice", data: []*User{&user}, want: []R{expected}},
+		{
+			name: "Map",
+			data: map[string]any{"ID": 2, "Name": "Go"},
+			want: R{"id": 2, "Name": "Go"},
+		},
+		{
+			name: "Slice of maps",
+			data: []R{{"ID": 3}, {"Email": "go@example.com"}},
+			want: []R{{"id": 3}, {"email_address": "go@example.com"}},
+		},
+		{name: "Non-struct", data: "text", want: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenameFields(tt.data, names)
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("RenameFields() = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+// EmbeddedProfile is an exported struct embedded into EmbeddedAccount.
+type EmbeddedProfile struct {
+	Email string
+	Phone string
+}
+
+// EmbeddedAccount is a struct with an exported embedded struct.
+type EmbeddedAccount struct {
+	ID int
+	EmbeddedProfile
+}
+
+// TestOnlyFieldsPromoted tests the selection of the promoted fields.
+func TestOnlyFieldsPromoted(t *testing.T) {
+	account := EmbeddedAccount{
+		ID:              1,
+		EmbeddedProfile: EmbeddedProfile{Email: "a@example.com"},
+	}
+
+	result := OnlyFields(account, "ID", "Email", "Missing")
+	expected := R{"ID": 1, "Email": "a@example.com"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("OnlyFields() = %v, want %v", result, expected)
+	}
+
+	result = OnlyFields(account, "EmbeddedProfile")
+	expected = R{"EmbeddedProfile": account.EmbeddedProfile}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("OnlyFields() embedded = %v, want %v", result, expected)
+	}
+}
+
+// TestFlattenEmbedded tests the FlattenEmbedded function.
+func TestFlattenEmbedded(t *testing.T) {
+	type Audit struct {
+		CreatedBy string
+		Phone     string
+	}
+
+	type Account struct {
+		ID    int
+		Email string
+		EmbeddedProfile
+		*Audit
+	}
+
+	account := Account{
+		ID:              1,
+		Email:           "own@example.com",
+		EmbeddedProfile: EmbeddedProfile{Email: "a@example.com"},
+	}
+
+	// Email of the profile is hidden, Phone is ambiguous,
+	// and the fields of the nil *Audit are skipped.
+	expected := R{"ID": 1, "Email": "own@example.com"}
+	if result := FlattenEmbedded(&account); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("FlattenEmbedded() = %v, want %v", result, expected)
+	}
+
+	account.Audit = &Audit{CreatedBy: "admin"}
+	expected = R{"ID": 1, "Email": "own@example.com", "CreatedBy": "admin"}
+	if result := FlattenEmbedded(account); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("FlattenEmbedded() = %v, want %v", result, expected)
+	}
+
+	flat := FlattenEmbedded([]Account{account})
+	result := ExcludeFields(flat, "CreatedBy")
+	want := []R{{"ID": 1, "Email": "own@example.com"}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("ExcludeFields(FlattenEmbedded()) = %v, want %v",
+			result, want)
+	}
+
+	if got := FlattenEmbedded("text"); got != "text" {
+		t.Errorf("FlattenEmbedded() non-struct = %v", got)
+	}
+}
+
+// TestFlattenEmbeddedTagged tests that the embedded structs with the
+// JSON names and the named struct fields aren't flattened.
+func TestFlattenEmbeddedTagged(t *testing.T) {
+	type Tagged struct {
+		EmbeddedProfile `json:"profile"`
+		ID              int
+	}
+
+	data := Tagged{ID: 1, EmbeddedProfile: EmbeddedProfile{Email: "e"}}
+	expected := R{"EmbeddedProfile": data.EmbeddedProfile, "ID": 1}
+	if result := FlattenEmbedded(data); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("FlattenEmbedded() = %v, want %v", result, expected)
+	}
+}
+
+// TestOnlyFieldsSliceOfMaps tests OnlyFields with a slice of maps.
+func TestOnlyFieldsSliceOfMaps(t *testing.T) {
+	data := []R{{"ID": 1, "Name": "Go"}, {"ID": 2, "Name": "Loop"}}
+	expected := []R{{"ID": 1}, {"ID": 2}}
+
+	if result := OnlyFields(data, "ID"); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("OnlyFields() = %v, want %v", result, expected)
+	}
+}
@@ -0,0 +1,132 @@
+package resp
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// DefaultFieldsParam is the query parameter
+// of the sparse fieldsets used by JSONFields.
+const DefaultFieldsParam = "fields"
+
+// FieldsFromRequest returns the list of the fields requested in the
+// query parameter of the request as a comma-separated list, e.g.
+// `?fields=id,email`. The parameter can be repeated.
+//
+// If the type is specified, the JSON:API style parameter is used,
+// e.g. `?fields[user]=id,email`, which falls back to the plain
+// parameter. The result is intended to be passed to OnlyJSONFields,
+// since the clients know the JSON names of the fields. It returns nil
+// if no fields are requested.
+//
+// Example Usage:
+//
+//	fields := resp.FieldsFromRequest(r, "fields", "user")
+//	if len(fields) > 0 {
+//	    data = resp.OnlyJSONFields(user, fields...)
+//	}
+func FieldsFromRequest(
+	r *http.Request,
+	param string,
+	typ ...string,
+) []string {
+	if r.URL == nil {
+		return nil
+	}
+
+	query := r.URL.Query()
+	values := query[param]
+	if len(typ) > 0 && typ[0] != "" {
+		if typed, ok := query[param+"["+typ[0]+"]"]; ok {
+			values = typed
+		}
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" || seen[field] {
+				continue
+			}
+
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// JSONFields sends the data as a JSON response, keeping only the fields
+// requested in the `fields` query parameter of the request (see
+// FieldsFromRequest and OnlyJSONFields). The data is sent unchanged if
+// no fields are requested. The names must match the JSON names of the
+// struct fields or the map keys.
+//
+// Example Usage:
+//
+//	// GET /users/1?fields=id,email
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.JSONFields(w, r, user)
+//	}
+func JSONFields(
+	w http.ResponseWriter,
+	r *http.Request,
+	data any,
+	opts ...Option,
+) error {
+	if fields := FieldsFromRequest(r, DefaultFieldsParam); len(fields) > 0 {
+		data = OnlyJSONFields(data, fields...)
+	}
+
+	return NewResponse(w, opts...).JSON(data)
+}
+
+// OnlyJSONFields is like OnlyFields, but the struct fields are matched
+// by their JSON names and the result is keyed by them, as encoding/json
+// encodes the struct: the fields tagged with `json:"-"` are never
+// selected, the empty omitempty fields are omitted and the fields of the
+// embedded structs are promoted. Use it with the field names that come
+// from the clients, e.g. with FieldsFromRequest.
+//
+// Example Usage:
+//
+//	type User struct {
+//		ID       int    `json:"id"`
+//		Email    string `json:"email"`
+//		Password string `json:"-"`
+//	}
+//
+//	data := resp.OnlyJSONFields(user, "id", "email")
+//	// {"email": "...", "id": 1}
+func OnlyJSONFields(data any, fields ...string) any {
+	selected := fieldSet(fields)
+	return transformFields(data,
+		func(rv reflect.Value) R { return onlyJSONFields(rv, selected) },
+		func(m map[string]any) R { return onlyFieldsMap(m, fields...) })
+}
+
+// onlyJSONFields returns the fields of the struct value
+// selected by their JSON names.
+func onlyJSONFields(rv reflect.Value, selected map[string]bool) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(selected))
+
+	for _, field := range info.flat {
+		if field.jsonSkip || !selected[field.jsonName] {
+			continue
+		}
+
+		value, ok := fieldByPath(rv, field.path)
+		if !ok || (field.omitEmpty && isEmptyValue(value)) {
+			continue
+		}
+
+		result[field.jsonName] = value.Interface()
+	}
+
+	return result
+}
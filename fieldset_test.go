@@ -0,0 +1,114 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestFieldsFromRequest tests the parsing of the sparse fieldsets.
+func TestFieldsFromRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		typ  []string
+		want []string
+	}{
+		{
+			name: "Plain list",
+			url:  "/?fields=id,email",
+			want: []string{"id", "email"},
+		},
+		{
+			name: "Repeated, spaces and duplicates",
+			url:  "/?fields=id,%20email&fields=id,,name",
+			want: []string{"id", "email", "name"},
+		},
+		{
+			name: "JSON:API style",
+			url:  "/?fields[user]=id,name&fields[post]=title",
+			typ:  []string{"user"},
+			want: []string{"id", "name"},
+		},
+		{
+			name: "JSON:API style falls back to plain",
+			url:  "/?fields=id",
+			typ:  []string{"user"},
+			want: []string{"id"},
+		},
+		{
+			name: "No fields",
+			url:  "/?sort=id",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			got := FieldsFromRequest(r, "fields", tt.typ...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldsFromRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestJSONFields tests the JSON response with sparse fieldsets.
+func TestJSONFields(t *testing.T) {
+	type User struct {
+		ID       int
+		Email    string
+		Password string
+	}
+	user := User{ID: 1, Email: "a@example.com", Password: "secret"}
+
+	tests := map[string]string{
+		"/?fields=ID,Email": `{"Email":"a@example.com","ID":1}` + "\n",
+		"/": `{"ID":1,"Email":"a@example.com","Password":"secret"}` +
+			"\n",
+	}
+
+	for url, want := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		if err := JSONFields(w, r, user); err != nil {
+			t.Fatalf("JSONFields() error = %v", err)
+		}
+
+		if got := w.Body.String(); got != want {
+			t.Errorf("JSONFields(%s) = %s, want %s", url, got, want)
+		}
+	}
+}
+
+// TestJSONFields_Tags tests that the fields are selected
+// by their JSON names.
+func TestJSONFields_Tags(t *testing.T) {
+	type User struct {
+		ID       int    `json:"id"`
+		Email    string `json:"email"`
+		Name     string `json:"name,omitempty"`
+		Password string `json:"-"`
+	}
+	users := []User{{ID: 1, Email: "a@example.com", Password: "secret"}}
+
+	tests := map[string]string{
+		"/?fields=id,email":         `[{"email":"a@example.com","id":1}]`,
+		"/?fields=ID,Email":         `[{}]`,
+		"/?fields=id,name,Password": `[{"id":1}]`,
+	}
+
+	for url, want := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		if err := JSONFields(w, r, users); err != nil {
+			t.Fatalf("JSONFields() error = %v", err)
+		}
+
+		if got := w.Body.String(); got != want+"\n" {
+			t.Errorf("JSONFields(%s) = %s, want %s", url, got, want)
+		}
+	}
+}
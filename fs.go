@@ -0,0 +1,146 @@
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// IndexFile is the file served by ServeFS for a directory.
+const IndexFile = "index.html"
+
+// ServeFS sends a file from the file system (e.g. embed.FS) to the
+// client. It mirrors ServeFile: the Content-Type is detected from the
+// file extension (or the content), and conditional and range requests
+// are handled (see ServeContent).
+//
+// For a directory, its index.html file is served; the request for a
+// directory without the trailing slash is redirected to the path with
+// the slash, so relative links of the page work. Directory listings
+// aren't served. A missing file is reported as a 404 error response
+// (see Error), a forbidden one as a 403 error response.
+//
+// Example Usage:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.ServeFS(w, r, static, path.Join("static", r.URL.Path))
+//	}
+func ServeFS(
+	w http.ResponseWriter,
+	r *http.Request,
+	fsys fs.FS,
+	name string,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).ServeFS(r, fsys, name)
+}
+
+// ServeFS sends a file from the file system.
+// See the ServeFS function for details.
+func (r *Response) ServeFS(
+	req *http.Request,
+	fsys fs.FS,
+	name string,
+) (err error) {
+	defer r.finish(&err)
+
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+
+	f, info, err := openFS(fsys, name)
+	if err != nil {
+		return r.fsError(err)
+	}
+	defer f.Close()
+
+	if info.IsDir() {
+		if req.URL != nil && !strings.HasSuffix(req.URL.Path, "/") {
+			return r.localRedirect(req, path.Base(req.URL.Path)+"/")
+		}
+
+		name = path.Join(name, IndexFile)
+		if f, info, err = openFS(fsys, name); err != nil {
+			return r.fsError(err)
+		}
+		defer f.Close()
+
+		if info.IsDir() {
+			return r.fsError(fs.ErrNotExist)
+		}
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return r.fsError(err)
+		}
+		content = bytes.NewReader(data)
+	}
+
+	return r.ServeContent(req, info.Name(), info.ModTime(), content)
+}
+
+// openFS opens the file of the file system and returns its info.
+func openFS(fsys fs.FS, name string) (fs.File, fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return f, info, nil
+}
+
+// fsError sends the error response of the file system error: 404 for
+// missing files, 403 for forbidden ones, and 500 for other errors.
+// The file system error is returned only for the 500 response.
+func (r *Response) fsError(err error) error {
+	code := StatusInternalServerError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		code = StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		code = StatusForbidden
+	}
+
+	if r.statusCode == StatusUndefined {
+		r.statusCode = code
+	}
+
+	if sendErr := r.Error(code, statusMessages[code]); sendErr != nil {
+		return sendErr
+	}
+
+	if code == StatusInternalServerError {
+		return err
+	}
+
+	return nil
+}
+
+// localRedirect redirects the request to the relative path,
+// keeping the query string.
+func (r *Response) localRedirect(req *http.Request, target string) error {
+	if q := req.URL.RawQuery; q != "" {
+		target += "?" + q
+	}
+
+	r.httpWriter.Header().Set(HeaderLocation, target)
+	r.writeHeader(StatusMovedPermanently)
+	return nil
+}
@@ -0,0 +1,159 @@
+package resp
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// noSeekFS is a file system whose files don't implement io.Seeker.
+type noSeekFS struct {
+	fs.FS
+}
+
+// noSeekFile hides the Seek method of the file.
+type noSeekFile struct {
+	fs.File
+}
+
+// Open opens the file without the Seek method.
+func (f noSeekFS) Open(name string) (fs.File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return noSeekFile{file}, nil
+}
+
+// testFS returns the file system for the ServeFS tests.
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"static/app.js":          {Data: []byte("console.log(1)")},
+		"static/docs/index.html": {Data: []byte("<h1>Docs</h1>")},
+		"static/empty/.keep":     {Data: []byte{}},
+	}
+}
+
+// TestServeFS tests the ServeFS function.
+func TestServeFS(t *testing.T) {
+	tests := []struct {
+		name       string
+		fsys       fs.FS
+		url        string
+		file       string
+		wantStatus int
+		wantType   string
+		wantBody   string
+		wantLoc    string
+	}{
+		{
+			name:       "File",
+			fsys:       testFS(),
+			url:        "/app.js",
+			file:       "static/app.js",
+			wantStatus: StatusOK,
+			wantType:   "text/javascript; charset=utf-8",
+			wantBody:   "console.log(1)",
+		},
+		{
+			name:       "File without Seek",
+			fsys:       noSeekFS{testFS()},
+			url:        "/app.js",
+			file:       "/static/../static/app.js",
+			wantStatus: StatusOK,
+			wantType:   "text/javascript; charset=utf-8",
+			wantBody:   "console.log(1)",
+		},
+		{
+			name:       "Directory index",
+			fsys:       testFS(),
+			url:        "/docs/",
+			file:       "static/docs",
+			wantStatus: StatusOK,
+			wantType:   "text/html; charset=utf-8",
+			wantBody:   "<h1>Docs</h1>",
+		},
+		{
+			name:       "Directory redirect",
+			fsys:       testFS(),
+			url:        "/docs?lang=uk",
+			file:       "static/docs",
+			wantStatus: StatusMovedPermanently,
+			wantLoc:    "docs/?lang=uk",
+		},
+		{
+			name:       "Directory without index",
+			fsys:       testFS(),
+			url:        "/empty/",
+			file:       "static/empty",
+			wantStatus: StatusNotFound,
+			wantType:   MIMEApplicationJSONCharsetUTF8,
+			wantBody:   `{"code":404,"message":"Not Found"}` + "\n",
+		},
+		{
+			name:       "Missing file",
+			fsys:       testFS(),
+			url:        "/missing.js",
+			file:       "static/missing.js",
+			wantStatus: StatusNotFound,
+			wantType:   MIMEApplicationJSONCharsetUTF8,
+			wantBody:   `{"code":404,"message":"Not Found"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if err := ServeFS(w, r, tt.fsys, tt.file); err != nil {
+				t.Fatalf("ServeFS() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeFS() status = %d, want %d",
+					w.Code, tt.wantStatus)
+			}
+
+			if tt.wantType != "" {
+				got := w.Header().Get(HeaderContentType)
+				if got != tt.wantType {
+					t.Errorf("ServeFS() Content-Type = %q, want %q",
+						got, tt.wantType)
+				}
+			}
+
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("ServeFS() body = %q, want %q",
+					w.Body.String(), tt.wantBody)
+			}
+
+			if got := w.Header().Get(HeaderLocation); got != tt.wantLoc {
+				t.Errorf("ServeFS() Location = %q, want %q",
+					got, tt.wantLoc)
+			}
+		})
+	}
+}
+
+// TestServeFSRange tests the range requests of ServeFS.
+func TestServeFSRange(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	r.Header.Set(HeaderRange, "bytes=0-6")
+
+	if err := ServeFS(w, r, testFS(), "static/app.js"); err != nil {
+		t.Fatalf("ServeFS() error = %v", err)
+	}
+
+	if w.Code != StatusPartialContent {
+		t.Errorf("ServeFS() status = %d, want %d",
+			w.Code, StatusPartialContent)
+	}
+
+	if !strings.HasPrefix(w.Body.String(), "console") {
+		t.Errorf("ServeFS() body = %q", w.Body.String())
+	}
+}
@@ -1,7 +1,10 @@
 module github.com/goloop/resp
 
-go 1.20
+go 1.21
 
-require github.com/goloop/g v1.12.1
+require (
+	github.com/goloop/g v1.12.1
+	golang.org/x/text v0.14.0
+)
 
 require github.com/goloop/trit v1.7.1 // indirect
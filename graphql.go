@@ -0,0 +1,75 @@
+package resp
+
+import "net/http"
+
+// GraphQLLocation is the location of the error in the GraphQL document.
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is an error of the GraphQL response.
+type GraphQLError struct {
+	// Message is the description of the error for the developer.
+	Message string `json:"message"`
+
+	// Locations are the locations of the error in the document.
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+
+	// Path is the path of the response field with the error,
+	// of the field names (strings) and list indices (ints).
+	Path []any `json:"path,omitempty"`
+
+	// Extensions is the additional information about the error,
+	// e.g. {"code": "UNAUTHENTICATED"}.
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Error returns the message of the error.
+func (e GraphQLError) Error() string {
+	return e.Message
+}
+
+// GraphQLResponse is the JSON body sent by GraphQL.
+type GraphQLResponse struct {
+	Data   any            `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// GraphQL sends the result of the GraphQL operation in the standard
+// response envelope: {"data": ..., "errors": [...]}. The errors entry
+// is omitted if there are no errors, and the data is null if it is nil.
+//
+// Following the GraphQL over HTTP conventions, the status is 200 (OK)
+// even if there are errors, unless it is set with the options (e.g.
+// 400 for the requests that can't be parsed or validated).
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    data, err := schema.Execute(r.Context(), query)
+//	    var errs []resp.GraphQLError
+//	    if err != nil {
+//	        errs = append(errs, resp.GraphQLError{
+//	            Message:    err.Error(),
+//	            Path:       []any{"user", 0, "email"},
+//	            Extensions: map[string]any{"code": "FORBIDDEN"},
+//	        })
+//	    }
+//
+//	    resp.GraphQL(w, data, errs)
+//	}
+func GraphQL(
+	w http.ResponseWriter,
+	data any,
+	errs []GraphQLError,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).GraphQL(data, errs)
+}
+
+// GraphQL sends the result of the GraphQL operation in the standard
+// response envelope. See the GraphQL function for details.
+func (r *Response) GraphQL(data any, errs []GraphQLError) error {
+	return r.JSON(GraphQLResponse{Data: data, Errors: errs})
+}
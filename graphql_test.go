@@ -0,0 +1,73 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGraphQL tests the GraphQL function.
+func TestGraphQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       any
+		errs       []GraphQLError
+		opts       []Option
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Data",
+			data:       R{"user": R{"id": 1}},
+			wantStatus: StatusOK,
+			wantBody:   `{"data":{"user":{"id":1}}}` + "\n",
+		},
+		{
+			name: "Partial data with errors",
+			data: R{"user": nil},
+			errs: []GraphQLError{{
+				Message:    "forbidden",
+				Locations:  []GraphQLLocation{{Line: 2, Column: 3}},
+				Path:       []any{"user", 0, "email"},
+				Extensions: map[string]any{"code": "FORBIDDEN"},
+			}},
+			wantStatus: StatusOK,
+			wantBody: `{"data":{"user":null},"errors":[{"message":` +
+				`"forbidden","locations":[{"line":2,"column":3}],` +
+				`"path":["user",0,"email"],` +
+				`"extensions":{"code":"FORBIDDEN"}}]}` + "\n",
+		},
+		{
+			name:       "Request error",
+			errs:       []GraphQLError{{Message: "syntax error"}},
+			opts:       []Option{WithStatusBadRequest()},
+			wantStatus: StatusBadRequest,
+			wantBody: `{"data":null,"errors":[{"message":` +
+				`"syntax error"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := GraphQL(w, tt.data, tt.errs, tt.opts...); err != nil {
+				t.Fatalf("GraphQL() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestGraphQLError tests that GraphQLError is an error.
+func TestGraphQLError(t *testing.T) {
+	var err error = GraphQLError{Message: "not found"}
+	if err.Error() != "not found" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "not found")
+	}
+}
@@ -0,0 +1,134 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// HandlerFunc is a handler that returns the error instead of sending
+// it, e.g. `return err` instead of `resp.Err(w, err); return`. It
+// implements http.Handler: the returned error is sent with Err, so the
+// status code is derived from the error (see StatusCoder and
+// RegisterErrorStatus).
+//
+// The message of the server errors (5xx) that don't implement
+// StatusCoder isn't sent, since it may have the internal details (e.g.
+// SQL or file paths): the status text is sent instead, and the error
+// is logged (see WithLogger).
+//
+// If the handler has already sent the status code when it returns the
+// error, the error can't be sent and is only logged (see WithLogger).
+//
+// Example Usage:
+//
+//	mux.Handle("/users/{id}", resp.HandlerFunc(
+//	    func(w http.ResponseWriter, r *http.Request) error {
+//	        user, err := store.User(r.PathValue("id"))
+//	        if err != nil {
+//	            return err // e.g. 404 for the NotFoundError
+//	        }
+//	        return resp.JSON(w, user)
+//	    }))
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls the handler and sends the returned error.
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, nil)
+}
+
+// WrapHandler converts the handler returning the error into
+// http.Handler like HandlerFunc, with the options applied to the error
+// responses, e.g. WithErrorFormat or WithLogger. Unlike Wrap, the
+// options don't apply to the responses sent by the handler itself.
+//
+// Example Usage:
+//
+//	mux.Handle("/", resp.WrapHandler(index,
+//	    resp.WithErrorTemplate(pages), resp.WithLogger(logger)))
+func WrapHandler(h HandlerFunc, opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, opts)
+	})
+}
+
+// serve calls the handler and sends the returned error
+// with the options, if the status code isn't sent yet.
+func (h HandlerFunc) serve(
+	w http.ResponseWriter,
+	r *http.Request,
+	opts []Option,
+) {
+	hw := &handlerWriter{ResponseWriter: w}
+	err := h(hw, r)
+	if err == nil {
+		return
+	}
+
+	response := NewResponse(w, opts...)
+	if hw.wroteHeader {
+		response.logError(err)
+		return
+	}
+
+	var coder StatusCoder
+	status := errorStatus(err)
+	if status < 500 || errors.As(err, &coder) {
+		response.Err(err)
+		return
+	}
+
+	// The internal details of the error are only logged.
+	if response.statusCode == StatusUndefined {
+		response.statusCode = status
+	}
+	response.Err(errors.New(StatusText(response.statusCode)))
+	response.logError(err)
+}
+
+// handlerWriter is the http.ResponseWriter used by HandlerFunc.
+// It records whether the status code is sent.
+type handlerWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader sends the status code. The informational status codes
+// (e.g. 103 Early Hints) don't count, since the final status follows.
+func (w *handlerWriter) WriteHeader(code int) {
+	if code >= 200 || code == StatusSwitchingProtocols {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the data, sending the status code first if needed.
+func (w *handlerWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// ReadFrom copies the data to the body, keeping the fast path
+// (e.g. sendfile) of the underlying writer if it is available.
+func (w *handlerWriter) ReadFrom(src io.Reader) (int64, error) {
+	w.wroteHeader = true
+	return io.Copy(w.ResponseWriter, src)
+}
+
+// FlushError flushes the underlying writer. The flush
+// sends the status code if it isn't sent yet.
+func (w *handlerWriter) FlushError() error {
+	w.wroteHeader = true
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush is like FlushError, for the handlers that use http.Flusher.
+func (w *handlerWriter) Flush() {
+	w.FlushError()
+}
+
+// Unwrap returns the original http.ResponseWriter,
+// for use with http.ResponseController.
+func (w *handlerWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,149 @@
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// errHandlerNoRows is the error registered with RegisterErrorStatus.
+var errHandlerNoRows = errors.New("no rows")
+
+// TestHandlerFunc tests the HandlerFunc type.
+func TestHandlerFunc(t *testing.T) {
+	RegisterErrorStatus(errHandlerNoRows, StatusNotFound)
+
+	tests := []struct {
+		name       string
+		handler    HandlerFunc
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "No error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return JSON(w, R{"ok": true})
+			},
+			wantStatus: StatusOK,
+			want:       `{"ok":true}` + "\n",
+		},
+		{
+			name: "Status coder",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return notFoundError{7}
+			},
+			wantStatus: StatusNotFound,
+			want:       `{"code":404,"message":"user 7 not found"}` + "\n",
+		},
+		{
+			name: "Internal error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return errors.New("open /etc/app/db.conf: permission denied")
+			},
+			wantStatus: StatusInternalServerError,
+			want: `{"code":500,"message":"Internal Server Error"}` +
+				"\n",
+		},
+		{
+			name: "Registered error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return fmt.Errorf("find user: %w", errHandlerNoRows)
+			},
+			wantStatus: StatusNotFound,
+			want: `{"code":404,"message":"find user: no rows"}` +
+				"\n",
+		},
+		{
+			name: "Error after write",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				if err := String(w, "partial"); err != nil {
+					return err
+				}
+				return errors.New("failed")
+			},
+			wantStatus: StatusOK,
+			want:       "partial",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWrapHandler tests that the options of WrapHandler
+// apply to the error responses.
+func TestWrapHandler(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	h := WrapHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("failed")
+	}, WithErrorFormat(ErrorFormatText), WithLogger(logger))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	text := StatusText(StatusInternalServerError)
+	if w.Code != StatusInternalServerError || w.Body.String() != text {
+		t.Errorf("response = %d %q, want %d %q", w.Code, w.Body.String(),
+			StatusInternalServerError, text)
+	}
+
+	if !strings.Contains(logs.String(), "failed") {
+		t.Errorf("logs = %q, want the failure", logs.String())
+	}
+
+	h = WrapHandler(func(w http.ResponseWriter, r *http.Request) error {
+		NoContent(w)
+		return errors.New("late failure")
+	}, WithLogger(logger))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, StatusNoContent)
+	}
+
+	if !strings.Contains(logs.String(), "late failure") {
+		t.Errorf("logs = %q, want the late failure", logs.String())
+	}
+}
+
+// TestHandlerFunc_Flush tests that the writer of HandlerFunc implements
+// http.Flusher, and that the error after the flush is only logged.
+func TestHandlerFunc_Flush(t *testing.T) {
+	h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("writer doesn't implement http.Flusher")
+		}
+
+		f.Flush()
+		return errors.New("failed")
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if !w.Flushed || w.Code != StatusOK || w.Body.Len() != 0 {
+		t.Errorf("response = %d %q, flushed %v, want %d, flushed",
+			w.Code, w.Body.String(), w.Flushed, StatusOK)
+	}
+}
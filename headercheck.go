@@ -0,0 +1,121 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidHeader is returned (wrapped) by the response methods when
+// the header validation is enabled (see WithHeaderValidation) and a
+// header has an invalid name or value.
+var ErrInvalidHeader = errors.New("invalid header")
+
+// WithHeaderValidation enables the validation of the header names and
+// values: the names must be tokens (RFC 9110, 5.1), and the values
+// must not contain CR, LF or other control characters (except HTAB),
+// e.g. from user-controlled filenames used for the header injection.
+//
+// The invalid headers set with SetHeader and AddHeader (and the options
+// based on them) are rejected at once, and the invalid headers set
+// directly in the writer's header map are removed before the headers
+// are sent. Instead of letting net/http drop or rewrite them silently,
+// the response method returns an error wrapping ErrInvalidHeader, but
+// the response is sent without these headers.
+//
+// Example Usage:
+//
+//	resp.SetDefaults(resp.WithHeaderValidation())
+func WithHeaderValidation() Option {
+	return func(r *Response) *Response {
+		r.validateHeaders = true
+		return r
+	}
+}
+
+// checkHeader returns true if the header can be set. If the validation
+// is enabled and the header is invalid, the error is recorded.
+func (r *Response) checkHeader(key string, values ...string) bool {
+	if !r.validateHeaders {
+		return true
+	}
+
+	if err := validateHeader(key, values); err != nil {
+		r.headerErr = errors.Join(r.headerErr, err)
+		return false
+	}
+
+	return true
+}
+
+// removeInvalidHeaders removes the invalid headers before they are
+// sent, if the validation is enabled. It is called when the status
+// code is sent.
+func (r *Response) removeInvalidHeaders() {
+	if !r.validateHeaders {
+		return
+	}
+
+	h := r.httpWriter.Header()
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := validateHeader(key, h[key]); err != nil {
+			r.headerErr = errors.Join(r.headerErr, err)
+			delete(h, key)
+		}
+	}
+}
+
+// validateHeader returns an error wrapping ErrInvalidHeader
+// if the name or any of the values is invalid.
+func validateHeader(key string, values []string) error {
+	if !validHeaderName(key) {
+		return fmt.Errorf("%w: name %q", ErrInvalidHeader, key)
+	}
+
+	for _, v := range values {
+		if !validHeaderValue(v) {
+			return fmt.Errorf("%w: value of %s: %q",
+				ErrInvalidHeader, key, v)
+		}
+	}
+
+	return nil
+}
+
+// validHeaderName reports whether the name is a token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// validHeaderValue reports whether the value has no control
+// characters other than HTAB (in particular, no CR and LF).
+func validHeaderValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; (c < 0x20 && c != '\t') || c == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
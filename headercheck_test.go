@@ -0,0 +1,96 @@
+package resp
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithHeaderValidation tests the WithHeaderValidation option.
+func TestWithHeaderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(r *Response)
+		invalid string
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			set: func(r *Response) {
+				r.SetHeader("X-Name", "value\twith tab")
+				r.AddHeader("X-Custom_Header.1", "a", "b")
+			},
+		},
+		{
+			name: "CRLF in value",
+			set: func(r *Response) {
+				r.SetHeader("X-Name", "a\r\nSet-Cookie: x=1")
+			},
+			invalid: "X-Name",
+			wantErr: true,
+		},
+		{
+			name: "Invalid name",
+			set: func(r *Response) {
+				r.AddHeader("X Name", "value")
+			},
+			invalid: "X Name",
+			wantErr: true,
+		},
+		{
+			name: "Set directly",
+			set: func(r *Response) {
+				r.httpWriter.Header()["X-Name"] = []string{"a\nb"}
+			},
+			invalid: "X-Name",
+			wantErr: true,
+		},
+		{
+			name: "Option",
+			set: func(r *Response) {
+				WithHeader("X-File", "report\x00.pdf")(r)
+			},
+			invalid: "X-File",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := NewResponse(w, WithHeaderValidation())
+			tt.set(r)
+
+			err := r.String("ok")
+			if got := errors.Is(err, ErrInvalidHeader); got != tt.wantErr {
+				t.Fatalf("String() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.invalid != "" {
+				if _, ok := w.Header()[tt.invalid]; ok {
+					t.Errorf("header %q is sent", tt.invalid)
+				}
+			}
+
+			if w.Body.String() != "ok" {
+				t.Errorf("body = %q, want %q", w.Body.String(), "ok")
+			}
+		})
+	}
+}
+
+// TestWithHeaderValidation_Disabled tests that the headers
+// aren't validated by default.
+func TestWithHeaderValidation_Disabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewResponse(w)
+	r.SetHeader("X-Name", "a\nb")
+
+	if err := r.String("ok"); err != nil {
+		t.Fatalf("String() error = %v", err)
+	}
+
+	if _, ok := w.Header()["X-Name"]; !ok {
+		t.Errorf("header X-Name isn't set")
+	}
+}
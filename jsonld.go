@@ -0,0 +1,71 @@
+package resp
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// JSONLD sends the data as a JSON-LD document (application/ld+json),
+// e.g. the structured data of a page. The context IRI is injected as
+// the "@context" key of the top-level JSON object, unless the data
+// has its own context. The data that isn't encoded as a JSON object
+// (e.g. a slice of nodes) is sent as the "@graph" of the document.
+// If the context IRI is empty, the data is sent unchanged.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.JSONLD(w, resp.R{
+//	        "@type": "Person",
+//	        "name":  "Go Loop",
+//	    }, "https://schema.org")
+//	}
+func JSONLD(
+	w http.ResponseWriter,
+	data any,
+	contextIRI string,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).JSONLD(data, contextIRI)
+}
+
+// JSONLD sends the data as a JSON-LD document.
+// See the JSONLD function for details.
+func (r *Response) JSONLD(data any, contextIRI string) error {
+	r.prepare(StatusOK, MIMEApplicationLDJSON)
+	if contextIRI == "" {
+		return r.JSON(data)
+	}
+
+	if obj, ok := jsonMap(data); ok {
+		if _, ok := obj["@context"]; ok {
+			return r.JSON(data)
+		}
+
+		doc := make(R, len(obj)+1)
+		doc["@context"] = contextIRI
+		for k, v := range obj {
+			doc[k] = v
+		}
+
+		return r.JSON(doc)
+	}
+
+	object := false
+	doc, err := r.setJSONField(data, "@context", true,
+		func(existing json.RawMessage) (any, bool) {
+			object = true
+			return contextIRI, existing == nil
+		})
+	if err != nil {
+		err = r.encodeError("failed to encode JSON-LD response", err)
+		r.finish(&err)
+		return err
+	}
+
+	if !object {
+		return r.JSON(R{"@context": contextIRI, "@graph": data})
+	}
+
+	return r.JSON(doc)
+}
@@ -0,0 +1,83 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestJSONLD tests the JSONLD function.
+func TestJSONLD(t *testing.T) {
+	type person struct {
+		Type string `json:"@type"`
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name       string
+		data       any
+		contextIRI string
+		want       string
+	}{
+		{
+			name:       "Map",
+			data:       R{"@type": "Person", "name": "Go Loop"},
+			contextIRI: "https://schema.org",
+			want: `{"@context":"https://schema.org",` +
+				`"@type":"Person","name":"Go Loop"}` + "\n",
+		},
+		{
+			name:       "Struct",
+			data:       person{Type: "Person", Name: "Go Loop"},
+			contextIRI: "https://schema.org",
+			want: `{"@context":"https://schema.org",` +
+				`"@type":"Person","name":"Go Loop"}` + "\n",
+		},
+		{
+			name:       "Own context",
+			data:       R{"@context": "https://example.org", "name": "x"},
+			contextIRI: "https://schema.org",
+			want:       `{"@context":"https://example.org","name":"x"}` + "\n",
+		},
+		{
+			name:       "Graph",
+			data:       []R{{"@id": "a"}, {"@id": "b"}},
+			contextIRI: "https://schema.org",
+			want: `{"@context":"https://schema.org",` +
+				`"@graph":[{"@id":"a"},{"@id":"b"}]}` + "\n",
+		},
+		{
+			name: "Without context",
+			data: R{"@id": "a"},
+			want: `{"@id":"a"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := JSONLD(w, tt.data, tt.contextIRI); err != nil {
+				t.Fatalf("JSONLD() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+
+			got := w.Header().Get(HeaderContentType)
+			if got != MIMEApplicationLDJSON {
+				t.Errorf("Content-Type = %q, want %q",
+					got, MIMEApplicationLDJSON)
+			}
+		})
+	}
+}
+
+// TestJSONLD_EncodeError tests that the data
+// that can't be encoded is reported.
+func TestJSONLD_EncodeError(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := JSONLD(w, make(chan int), "https://schema.org")
+	if err == nil {
+		t.Fatal("JSONLD() error = nil, want error")
+	}
+}
@@ -0,0 +1,139 @@
+package resp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// WithContext sets the context of the streaming responses (e.g. the
+// context of the request): the stream is terminated when the context
+// is canceled, for example when the client disconnects.
+func WithContext(ctx context.Context) Option {
+	return func(r *Response) *Response {
+		r.ctx = ctx
+		return r
+	}
+}
+
+// WithFlushBatch sets the number of the elements written between the
+// flushes of the streaming responses (1 by default, i.e. every element
+// is flushed). A larger batch reduces the number of the small network
+// writes of fast producers.
+func WithFlushBatch(n int) Option {
+	return func(r *Response) *Response {
+		r.flushBatch = n
+		return r
+	}
+}
+
+// StreamJSONChan writes the values received from the channel as a JSON
+// array, incrementally: each value is encoded and flushed to the client
+// as soon as it arrives (or per batch, see WithFlushBatch). The array is
+// closed when the channel is closed.
+//
+// If the context set with the WithContext option is canceled, or a
+// value can't be encoded, the error is returned and the array is left
+// unterminated, so the client doesn't take the partial result for the
+// complete one. The producer must stop sending when the context is
+// canceled, since the channel isn't drained.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    rows := make(chan Row)
+//	    go db.StreamRows(r.Context(), rows) // closes rows when done
+//
+//	    resp.StreamJSONChan(w, rows, resp.WithContext(r.Context()))
+//	}
+func StreamJSONChan[T any](
+	w http.ResponseWriter,
+	ch <-chan T,
+	opts ...Option,
+) error {
+	return streamJSONChan(NewResponse(w, opts...), ch)
+}
+
+// streamJSONChan writes the values of the channel as a JSON array.
+func streamJSONChan[T any](r *Response, ch <-chan T) (err error) {
+	defer r.finish(&err)
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	batch := r.flushBatch
+	if batch < 1 {
+		batch = 1
+	}
+
+	r.prepare(StatusOK, MIMEApplicationJSONCharsetUTF8)
+	r.writeHeader(r.statusCode)
+	if _, err := r.write([]byte("[")); err != nil {
+		return err
+	}
+	r.flush()
+
+	var buf bytes.Buffer
+	for count := 0; ; count++ {
+		var (
+			value T
+			ok    bool
+		)
+
+		select {
+		case value, ok = <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if !ok {
+			break
+		}
+
+		buf.Reset()
+		if count > 0 {
+			buf.WriteByte(',')
+		}
+		if err := r.encodeJSON(&buf, value); err != nil {
+			return r.encodeError("failed to encode JSON stream", err)
+		}
+
+		if _, err := r.write(bytes.TrimRight(buf.Bytes(), "\n")); err != nil {
+			return err
+		}
+
+		if (count+1)%batch == 0 {
+			r.flush()
+		}
+	}
+
+	if _, err := r.write([]byte("]\n")); err != nil {
+		return err
+	}
+	r.flush()
+	return nil
+}
+
+// encodeJSON encodes the value with the custom
+// JSON encoder, if it is set, or encoding/json.
+func (r *Response) encodeJSON(buf *bytes.Buffer, value any) error {
+	if r.jsonEncodeFunc != nil {
+		return r.jsonEncodeFunc(buf, value)
+	}
+
+	return json.NewEncoder(buf).Encode(value)
+}
+
+// flush sends the buffered data to the client, if the
+// writer supports it (see http.ResponseController).
+func (r *Response) flush() {
+	err := http.NewResponseController(r.httpWriter).Flush()
+	if err != nil && !errors.Is(err, http.ErrNotSupported) &&
+		r.writeErr == nil {
+		r.writeErr = err
+	}
+}
@@ -0,0 +1,125 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// flushRecorder is a recorder that counts the flushes.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+// Flush counts the flush.
+func (w *flushRecorder) Flush() {
+	w.flushes++
+	w.ResponseRecorder.Flush()
+}
+
+// TestStreamJSONChan tests the StreamJSONChan function.
+func TestStreamJSONChan(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	tests := []struct {
+		name        string
+		items       []item
+		batch       int
+		want        string
+		wantFlushes int
+	}{
+		{
+			name:        "Empty",
+			want:        "[]\n",
+			wantFlushes: 2,
+		},
+		{
+			name:        "Flush per element",
+			items:       []item{{1}, {2}, {3}},
+			want:        `[{"id":1},{"id":2},{"id":3}]` + "\n",
+			wantFlushes: 5,
+		},
+		{
+			name:        "Flush per batch",
+			items:       []item{{1}, {2}, {3}},
+			batch:       2,
+			want:        `[{"id":1},{"id":2},{"id":3}]` + "\n",
+			wantFlushes: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan item)
+			go func() {
+				defer close(ch)
+				for _, it := range tt.items {
+					ch <- it
+				}
+			}()
+
+			w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+			err := StreamJSONChan(w, ch, WithFlushBatch(tt.batch))
+			if err != nil {
+				t.Fatalf("StreamJSONChan() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("StreamJSONChan() body = %q, want %q", got, tt.want)
+			}
+
+			if w.flushes != tt.wantFlushes {
+				t.Errorf("flushes = %d, want %d", w.flushes, tt.wantFlushes)
+			}
+
+			want := MIMEApplicationJSONCharsetUTF8
+			if got := w.Header().Get(HeaderContentType); got != want {
+				t.Errorf("Content-Type = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestStreamJSONChan_Canceled tests that the array
+// is left unterminated when the context is canceled.
+func TestStreamJSONChan_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+
+	w := httptest.NewRecorder()
+	err := StreamJSONChan(w, ch, WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StreamJSONChan() error = %v, want %v",
+			err, context.Canceled)
+	}
+
+	if got, want := w.Body.String(), "[1"; got != want {
+		t.Errorf("StreamJSONChan() body = %q, want %q", got, want)
+	}
+}
+
+// TestStreamJSONChan_EncodeError tests that the array
+// is left unterminated if a value can't be encoded.
+func TestStreamJSONChan_EncodeError(t *testing.T) {
+	ch := make(chan any, 2)
+	ch <- 1
+	ch <- make(chan int)
+	close(ch)
+
+	w := httptest.NewRecorder()
+	if err := StreamJSONChan(w, ch); err == nil {
+		t.Fatal("StreamJSONChan() error = nil, want encode error")
+	}
+
+	if got, want := w.Body.String(), "[1"; got != want {
+		t.Errorf("StreamJSONChan() body = %q, want %q", got, want)
+	}
+}
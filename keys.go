@@ -0,0 +1,177 @@
+package resp
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts the key to snake_case, e.g. "UserID" to "user_id".
+func SnakeCase(key string) string {
+	return strings.Join(splitWords(key), "_")
+}
+
+// KebabCase converts the key to kebab-case, e.g. "UserID" to "user-id".
+func KebabCase(key string) string {
+	return strings.Join(splitWords(key), "-")
+}
+
+// CamelCase converts the key to camelCase, e.g. "user_id" to "userId".
+func CamelCase(key string) string {
+	words := splitWords(key)
+	for i := 1; i < len(words); i++ {
+		runes := []rune(words[i])
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+
+	return strings.Join(words, "")
+}
+
+// splitWords splits the key into lower-case words. The words are
+// separated by underscores, hyphens, spaces, dots, and case changes;
+// acronyms are kept together, e.g. "HTTPServer" is "http", "server".
+func splitWords(key string) []string {
+	var words []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, c := range runes {
+		switch {
+		case c == '_' || c == '-' || c == ' ' || c == '.':
+			flush()
+			continue
+		case unicode.IsUpper(c) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+
+		word = append(word, c)
+	}
+	flush()
+
+	return words
+}
+
+// TransformKeys rewrites the keys of the provided data with the
+// transform function (e.g. SnakeCase, CamelCase, KebabCase) and returns
+// the result. The keys of `R` maps, maps with string keys, and structs
+// (converted to `R` maps keyed by the JSON names of the fields) are
+// rewritten at every depth, including the elements of slices and
+// arrays, so the results of OnlyFields and ExcludeFields can be passed
+// as well. Other values are returned unchanged.
+//
+// Example Usage:
+//
+//	type User struct {
+//		UserID    int
+//		FirstName string
+//	}
+//
+//	data := resp.TransformKeys(user, resp.SnakeCase)
+//	// {"first_name": "Go", "user_id": 1}
+func TransformKeys(data any, transform func(string) string) any {
+	if data == nil {
+		return nil
+	}
+
+	return transformKeys(reflect.ValueOf(data), transform)
+}
+
+// transformKeys rewrites the keys of the value.
+func transformKeys(rv reflect.Value, transform func(string) string) any {
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if rv.IsNil() || isMarshaler(rv) {
+			return rv.Interface()
+		}
+
+		elem := rv.Elem()
+		switch elem.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array,
+			reflect.Interface, reflect.Ptr:
+			return transformKeys(elem, transform)
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		result := make(R, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := transform(iter.Key().String())
+			result[key] = transformKeys(iter.Value(), transform)
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			break
+		}
+
+		// Byte slices are encoded as base64 strings.
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		result := make([]any, rv.Len())
+		for i := range result {
+			result[i] = transformKeys(rv.Index(i), transform)
+		}
+		return result
+	case reflect.Struct:
+		if isMarshaler(rv) {
+			break
+		}
+
+		result := make(R)
+		rt := rv.Type()
+		for i := 0; i < rv.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, _, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+
+			result[transform(name)] = transformKeys(rv.Field(i), transform)
+		}
+		return result
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	return rv.Interface()
+}
+
+// isMarshaler reports whether the value has its own JSON representation.
+func isMarshaler(rv reflect.Value) bool {
+	if !rv.CanInterface() {
+		return false
+	}
+
+	switch rv.Interface().(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+		return true
+	}
+
+	return false
+}
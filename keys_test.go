@@ -0,0 +1,113 @@
+package resp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestKeyCases tests the key case conversions.
+func TestKeyCases(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		kebab string
+		camel string
+	}{
+		{"UserID", "user_id", "user-id", "userId"},
+		{"user_id", "user_id", "user-id", "userId"},
+		{"firstName", "first_name", "first-name", "firstName"},
+		{"HTTPServer", "http_server", "http-server", "httpServer"},
+		{"Address2Line", "address2_line", "address2-line", "address2Line"},
+		{"created-at", "created_at", "created-at", "createdAt"},
+		{"ID", "id", "id", "id"},
+		{"", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SnakeCase(tt.key); got != tt.snake {
+			t.Errorf("SnakeCase(%q) = %q, want %q", tt.key, got, tt.snake)
+		}
+
+		if got := KebabCase(tt.key); got != tt.kebab {
+			t.Errorf("KebabCase(%q) = %q, want %q", tt.key, got, tt.kebab)
+		}
+
+		if got := CamelCase(tt.key); got != tt.camel {
+			t.Errorf("CamelCase(%q) = %q, want %q", tt.key, got, tt.camel)
+		}
+	}
+}
+
+// TestTransformKeys tests the rewriting of the keys at every depth.
+func TestTransformKeys(t *testing.T) {
+	type Address struct {
+		StreetName string
+		ZipCode    string `json:"zipCode"`
+	}
+
+	type User struct {
+		UserID    int
+		CreatedAt time.Time
+		Address   *Address
+		Tags      []string
+		Secret    string `json:"-"`
+		private   int
+	}
+
+	user := User{
+		UserID:    1,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Address:   &Address{StreetName: "Main", ZipCode: "01001"},
+		Tags:      []string{"a"},
+		Secret:    "secret",
+		private:   2,
+	}
+
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{
+			name: "Struct",
+			data: user,
+			want: `{"address":{"street_name":"Main","zip_code":"01001"},` +
+				`"created_at":"2024-01-01T00:00:00Z","tags":["a"],` +
+				`"user_id":1}`,
+		},
+		{
+			name: "Nested maps and filtered results",
+			data: R{
+				"TotalCount": 1,
+				"Items":      OnlyFields([]User{user}, "UserID"),
+				"pageInfo":   map[string]any{"hasNext": false},
+			},
+			want: `{"items":[{"user_id":1}],"page_info":{"has_next":false},` +
+				`"total_count":1}`,
+		},
+		{
+			name: "Scalar",
+			data: 42,
+			want: `42`,
+		},
+		{
+			name: "Nil",
+			data: nil,
+			want: `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(TransformKeys(tt.data, SnakeCase))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("TransformKeys() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
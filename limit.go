@@ -0,0 +1,93 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrBodyTooLarge is returned (wrapped) by the response methods when
+// the body exceeds the limit set with the WithMaxBodySize option.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// bodyLimit is the state of the body size limit of the response.
+type bodyLimit struct {
+	max       int64 // maximum size of the body
+	trailer   bool  // the X-Body-Truncated trailer is declared
+	truncated bool  // the body is truncated
+}
+
+// WithMaxBodySize limits the size of the response body to n bytes,
+// protecting against accidentally sending unbounded data (e.g. a huge
+// slice encoded as JSON). The body is truncated at the limit, the rest
+// of the data is discarded, and the response method returns an error
+// wrapping ErrBodyTooLarge. The X-Body-Truncated trailer (declared in
+// the Trailer header) is sent as "true" if the body is truncated and as
+// "false" otherwise, so the clients can detect an incomplete body.
+//
+// Since the status code is usually sent before the body is encoded,
+// the limit doesn't change it. The zero-copy fast path (sendfile) of
+// the writer is disabled to count the body bytes. If n isn't positive,
+// the size isn't limited.
+//
+// Example Usage:
+//
+//	err := resp.JSON(w, rows, resp.WithMaxBodySize(10<<20))
+//	if errors.Is(err, resp.ErrBodyTooLarge) {
+//	    log.Printf("response of %s is truncated", r.URL.Path)
+//	}
+func WithMaxBodySize(n int64) Option {
+	return func(r *Response) *Response {
+		if n <= 0 {
+			r.bodyLimit = nil
+			return r
+		}
+
+		r.bodyLimit = &bodyLimit{max: n}
+		return r
+	}
+}
+
+// startBodyLimit declares the X-Body-Truncated trailer, if the body
+// size is limited. It is called when the status code is sent.
+func (r *Response) startBodyLimit(code int) {
+	if r.bodyLimit == nil || !bodyAllowed(code) {
+		return
+	}
+
+	r.bodyLimit.trailer = true
+	r.httpWriter.Header().Add(HeaderTrailer, HeaderXBodyTruncated)
+}
+
+// finishBodyLimit sends the X-Body-Truncated trailer.
+func (r *Response) finishBodyLimit() {
+	if r.bodyLimit == nil || !r.bodyLimit.trailer {
+		return
+	}
+
+	r.httpWriter.Header().Set(HeaderXBodyTruncated,
+		strconv.FormatBool(r.bodyLimit.truncated))
+	r.bodyLimit.trailer = false
+}
+
+// limitBody returns the part of the data that fits
+// into the body size limit.
+func (r *Response) limitBody(p []byte) []byte {
+	if r.bodyLimit == nil {
+		return p
+	}
+
+	rest := r.bodyLimit.max - r.written
+	if int64(len(p)) <= rest {
+		return p
+	}
+
+	r.bodyLimit.truncated = true
+	return p[:rest]
+}
+
+// bodyTooLarge returns the error of the truncated body.
+func (r *Response) bodyTooLarge() error {
+	return fmt.Errorf("%w: the limit is %d bytes",
+		ErrBodyTooLarge, r.bodyLimit.max)
+}
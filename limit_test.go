@@ -0,0 +1,90 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWithMaxBodySize tests the WithMaxBodySize option.
+func TestWithMaxBodySize(t *testing.T) {
+	tests := []struct {
+		name          string
+		serve         func(w *httptest.ResponseRecorder) error
+		wantBody      string
+		wantTruncated string
+		wantErr       bool
+	}{
+		{
+			name: "Within limit",
+			serve: func(w *httptest.ResponseRecorder) error {
+				return String(w, "Hello", WithMaxBodySize(5))
+			},
+			wantBody:      "Hello",
+			wantTruncated: "false",
+		},
+		{
+			name: "JSON over limit",
+			serve: func(w *httptest.ResponseRecorder) error {
+				return JSON(w, []int{1, 2, 3}, WithMaxBodySize(4))
+			},
+			wantBody:      "[1,2",
+			wantTruncated: "true",
+			wantErr:       true,
+		},
+		{
+			name: "Stream over limit",
+			serve: func(w *httptest.ResponseRecorder) error {
+				// The struct hides the WriteTo method of the reader.
+				src := struct{ io.Reader }{strings.NewReader("abcdef")}
+				return Stream(w, src, WithMaxBodySize(3))
+			},
+			wantBody:      "abc",
+			wantTruncated: "true",
+			wantErr:       true,
+		},
+		{
+			name: "Not limited",
+			serve: func(w *httptest.ResponseRecorder) error {
+				return String(w, "Hello", WithMaxBodySize(0))
+			},
+			wantBody: "Hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := tt.serve(w)
+			if tt.wantErr != errors.Is(err, ErrBodyTooLarge) {
+				t.Fatalf("serve error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+
+			res := w.Result()
+			got := res.Trailer.Get(HeaderXBodyTruncated)
+			if got != tt.wantTruncated {
+				t.Errorf("X-Body-Truncated = %q, want %q",
+					got, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+// TestWithMaxBodySizeNoBody tests that the trailer
+// isn't declared for the responses without a body.
+func TestWithMaxBodySizeNoBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := NoContent(w, WithMaxBodySize(10)); err != nil {
+		t.Fatalf("NoContent() error = %v", err)
+	}
+
+	if got := w.Header().Get(HeaderTrailer); got != "" {
+		t.Errorf("Trailer = %q, want none", got)
+	}
+}
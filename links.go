@@ -0,0 +1,194 @@
+package resp
+
+import "encoding/json"
+
+// DefaultLinksKey is the key of the links block
+// injected into JSON responses by WithLinks.
+const DefaultLinksKey = "links"
+
+// ResourceLink is a hypermedia link of the resource (HATEOAS).
+type ResourceLink struct {
+	Rel       string `json:"-"`                   // relation, e.g. "self"
+	Href      string `json:"href"`                // URL or URI template
+	Method    string `json:"method,omitempty"`    // HTTP method, e.g. "POST"
+	Templated bool   `json:"templated,omitempty"` // Href is a URI template
+	Type      string `json:"type,omitempty"`      // media type of the target
+	Title     string `json:"title,omitempty"`     // human-readable title
+}
+
+// Links is the list of the hypermedia links of the resource.
+// It is encoded as a JSON object keyed by the relations, e.g.
+// {"self": {"href": "/users/1"}}; the links with the same
+// relation are grouped into an array.
+//
+// Example Usage:
+//
+//	links := resp.Links{}.
+//	    Self("/users/1").
+//	    Related("orders", "/users/1/orders").
+//	    Add(resp.ResourceLink{
+//	        Rel:    "deactivate",
+//	        Href:   "/users/1/deactivation",
+//	        Method: http.MethodPost,
+//	    })
+type Links []ResourceLink
+
+// Add returns the links with the given links appended.
+func (l Links) Add(links ...ResourceLink) Links {
+	return append(l, links...)
+}
+
+// Self returns the links with the "self" link appended.
+func (l Links) Self(href string) Links {
+	return l.Add(ResourceLink{Rel: "self", Href: href})
+}
+
+// Next returns the links with the "next" link appended.
+func (l Links) Next(href string) Links {
+	return l.Add(ResourceLink{Rel: "next", Href: href})
+}
+
+// Prev returns the links with the "prev" link appended.
+func (l Links) Prev(href string) Links {
+	return l.Add(ResourceLink{Rel: "prev", Href: href})
+}
+
+// Related returns the links with the link of the relation appended.
+func (l Links) Related(rel, href string) Links {
+	return l.Add(ResourceLink{Rel: rel, Href: href})
+}
+
+// Template returns the links with the templated link (RFC 6570 URI
+// template) of the relation appended, e.g. "/users{?q,page}".
+func (l Links) Template(rel, href string) Links {
+	return l.Add(ResourceLink{Rel: rel, Href: href, Templated: true})
+}
+
+// MarshalJSON encodes the links as the JSON object keyed by the
+// relations, in the order of the first link of each relation.
+func (l Links) MarshalJSON() ([]byte, error) {
+	var order []string
+	groups := make(map[string][]ResourceLink, len(l))
+	for _, link := range l {
+		if _, ok := groups[link.Rel]; !ok {
+			order = append(order, link.Rel)
+		}
+		groups[link.Rel] = append(groups[link.Rel], link)
+	}
+
+	buf := []byte{'{'}
+	for i, rel := range order {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		key, err := json.Marshal(rel)
+		if err != nil {
+			return nil, err
+		}
+
+		var value []byte
+		if group := groups[rel]; len(group) == 1 {
+			value, err = json.Marshal(group[0])
+		} else {
+			value, err = json.Marshal(group)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+
+	return append(buf, '}'), nil
+}
+
+// header returns the links sent as the Link headers. The templated
+// links aren't sent, since the header requires the URI reference.
+func (l Links) header() []LinkHeader {
+	headers := make([]LinkHeader, 0, len(l))
+	for _, link := range l {
+		if link.Templated {
+			continue
+		}
+
+		headers = append(headers, LinkHeader{
+			URI:   link.Href,
+			Rel:   link.Rel,
+			Type:  link.Type,
+			Title: link.Title,
+		})
+	}
+
+	return headers
+}
+
+// WithLinks adds the hypermedia links to the response: they are sent
+// as the Link headers and injected into the top-level JSON object of
+// the response under the "links" key (see WithLinksKey). The links
+// set by several calls are combined.
+//
+// If the data already has the key, or isn't encoded as a JSON object
+// (e.g. arrays), the body is sent unchanged, but the headers are sent
+// anyway. The templated links are sent only in the body.
+//
+// Example Usage:
+//
+//	resp.JSON(w, user, resp.WithLinks(resp.Links{}.
+//	    Self("/users/1").
+//	    Related("orders", "/users/1/orders")))
+//	// Link: </users/1>; rel="self"
+//	// Link: </users/1/orders>; rel="orders"
+//	// {"id": 1, "links": {"self": {"href": "/users/1"}, ...}}
+func WithLinks(links Links) Option {
+	return func(r *Response) *Response {
+		AddLink(links.header()...)(r)
+		r.links = append(r.links, links...)
+		return r
+	}
+}
+
+// WithLinksKey sets the key of the links block in JSON responses.
+// The default key is DefaultLinksKey.
+func WithLinksKey(key string) Option {
+	return func(r *Response) *Response {
+		r.linksKey = key
+		return r
+	}
+}
+
+// injectLinks returns the data with the links injected.
+// If there are no links, the data isn't a JSON object or it
+// already has the key, the data is returned unchanged.
+func (r *Response) injectLinks(data any) (any, error) {
+	if len(r.links) == 0 {
+		return data, nil
+	}
+
+	key := r.linksKey
+	if key == "" {
+		key = DefaultLinksKey
+	}
+
+	obj, ok := jsonMap(data)
+	if !ok {
+		return r.setJSONField(data, key, true,
+			func(existing json.RawMessage) (any, bool) {
+				return r.links, existing == nil
+			})
+	}
+
+	if _, ok := obj[key]; ok {
+		return data, nil
+	}
+
+	result := make(R, len(obj)+1)
+	for k, v := range obj {
+		result[k] = v
+	}
+	result[key] = r.links
+
+	return result, nil
+}
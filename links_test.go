@@ -0,0 +1,127 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestLinks_MarshalJSON tests the MarshalJSON method of Links.
+func TestLinks_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		links Links
+		want  string
+	}{
+		{
+			name:  "Empty",
+			links: Links{},
+			want:  `{}`,
+		},
+		{
+			name: "Relations",
+			links: Links{}.
+				Self("/users/1").
+				Template("search", "/users{?q}").
+				Add(ResourceLink{
+					Rel:    "delete",
+					Href:   "/users/1",
+					Method: "DELETE",
+				}),
+			want: `{"self":{"href":"/users/1"},` +
+				`"search":{"href":"/users{?q}","templated":true},` +
+				`"delete":{"href":"/users/1","method":"DELETE"}}`,
+		},
+		{
+			name: "Same relation",
+			links: Links{}.
+				Related("item", "/items/1").
+				Next("/users?page=2").
+				Related("item", "/items/2"),
+			want: `{"item":[{"href":"/items/1"},{"href":"/items/2"}],` +
+				`"next":{"href":"/users?page=2"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.links.MarshalJSON()
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithLinks tests the WithLinks option.
+func TestWithLinks(t *testing.T) {
+	links := Links{}.
+		Self("/users/1").
+		Template("search", "/users{?q}")
+
+	tests := []struct {
+		name string
+		data any
+		opts []Option
+		want string
+	}{
+		{
+			name: "Object",
+			data: R{"id": 1},
+			want: `{"id":1,"links":{"self":{"href":"/users/1"},` +
+				`"search":{"href":"/users{?q}","templated":true}}}` + "\n",
+		},
+		{
+			name: "Struct",
+			data: struct {
+				ID int `json:"id"`
+			}{1},
+			opts: []Option{WithLinksKey("_links")},
+			want: `{"_links":{"self":{"href":"/users/1"},` +
+				`"search":{"href":"/users{?q}","templated":true}},` +
+				`"id":1}` + "\n",
+		},
+		{
+			name: "Own key",
+			data: R{"id": 1, "links": "own"},
+			want: `{"id":1,"links":"own"}` + "\n",
+		},
+		{
+			name: "Array",
+			data: []int{1, 2},
+			want: `[1,2]` + "\n",
+		},
+		{
+			name: "With meta",
+			data: R{"id": 1},
+			opts: []Option{WithMeta("v", 1)},
+			want: `{"id":1,"links":{"self":{"href":"/users/1"},` +
+				`"search":{"href":"/users{?q}","templated":true}},` +
+				`"meta":{"v":1}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			opts := append([]Option{WithLinks(links)}, tt.opts...)
+			if err := JSON(w, tt.data, opts...); err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+
+			want := []string{`</users/1>; rel="self"`}
+			if got := w.Header().Values(HeaderLink); !reflect.DeepEqual(
+				got, want) {
+				t.Errorf("Link = %q, want %q", got, want)
+			}
+		})
+	}
+}
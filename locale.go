@@ -0,0 +1,183 @@
+package resp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocaleFormatter controls how values are converted to text in tabular
+// exports (see the CSV function), so the files match the regional
+// settings of the recipient.
+//
+// The zero value formats numbers with a dot as the decimal separator,
+// without thousands grouping, dates in RFC 3339 format, and uses
+// a comma as the field delimiter.
+//
+// Example Usage:
+//
+//	f := resp.LocaleDeDE
+//	f.DateFormat = "02.01.2006 15:04"
+//
+//	resp.CSV(w, rows, resp.WithLocaleFormatter(f))
+type LocaleFormatter struct {
+	// DecimalSeparator separates the integer and fractional parts
+	// of a number. Default is ".".
+	DecimalSeparator string
+
+	// ThousandsSeparator groups the digits of the integer part by three.
+	// If empty, the digits aren't grouped.
+	ThousandsSeparator string
+
+	// DateFormat is the layout (as in time.Format) used for time.Time
+	// values. Default is time.RFC3339.
+	DateFormat string
+
+	// Delimiter is the field delimiter of CSV files. Default is ','.
+	// Locales that use a comma as the decimal separator usually use ';'.
+	Delimiter rune
+}
+
+// Predefined locale formatters.
+var (
+	// LocaleEnUS is the formatter for the United States.
+	LocaleEnUS = LocaleFormatter{
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		DateFormat:         "01/02/2006",
+		Delimiter:          ',',
+	}
+
+	// LocaleEnGB is the formatter for the United Kingdom.
+	LocaleEnGB = LocaleFormatter{
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		DateFormat:         "02/01/2006",
+		Delimiter:          ',',
+	}
+
+	// LocaleDeDE is the formatter for Germany.
+	LocaleDeDE = LocaleFormatter{
+		DecimalSeparator:   ",",
+		ThousandsSeparator: ".",
+		DateFormat:         "02.01.2006",
+		Delimiter:          ';',
+	}
+
+	// LocaleFrFR is the formatter for France.
+	LocaleFrFR = LocaleFormatter{
+		DecimalSeparator:   ",",
+		ThousandsSeparator: " ", // narrow no-break space
+		DateFormat:         "02/01/2006",
+		Delimiter:          ';',
+	}
+
+	// LocaleUkUA is the formatter for Ukraine.
+	LocaleUkUA = LocaleFormatter{
+		DecimalSeparator:   ",",
+		ThousandsSeparator: " ", // no-break space
+		DateFormat:         "02.01.2006",
+		Delimiter:          ';',
+	}
+)
+
+// Format converts the value to text according to the locale.
+//
+// Integers and floats use the decimal and thousands separators,
+// time.Time values use the date format, nil is converted to an empty
+// string, and any other value is formatted with fmt.Sprint.
+func (f LocaleFormatter) Format(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case int:
+		return f.formatNumber(strconv.FormatInt(int64(val), 10))
+	case int8:
+		return f.formatNumber(strconv.FormatInt(int64(val), 10))
+	case int16:
+		return f.formatNumber(strconv.FormatInt(int64(val), 10))
+	case int32:
+		return f.formatNumber(strconv.FormatInt(int64(val), 10))
+	case int64:
+		return f.formatNumber(strconv.FormatInt(val, 10))
+	case uint:
+		return f.formatNumber(strconv.FormatUint(uint64(val), 10))
+	case uint8:
+		return f.formatNumber(strconv.FormatUint(uint64(val), 10))
+	case uint16:
+		return f.formatNumber(strconv.FormatUint(uint64(val), 10))
+	case uint32:
+		return f.formatNumber(strconv.FormatUint(uint64(val), 10))
+	case uint64:
+		return f.formatNumber(strconv.FormatUint(val, 10))
+	case float32:
+		return f.formatNumber(strconv.FormatFloat(float64(val), 'f', -1, 32))
+	case float64:
+		return f.formatNumber(strconv.FormatFloat(val, 'f', -1, 64))
+	case time.Time:
+		layout := f.DateFormat
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return val.Format(layout)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// delimiter returns the CSV field delimiter.
+func (f LocaleFormatter) delimiter() rune {
+	if f.Delimiter == 0 {
+		return ','
+	}
+	return f.Delimiter
+}
+
+// formatNumber applies the separators of the locale to the number
+// formatted by the strconv package (e.g. "-1234567.89").
+func (f LocaleFormatter) formatNumber(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	// NaN and Inf values are returned as is.
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return sign + s
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	if f.ThousandsSeparator != "" && len(intPart) > 3 {
+		var b strings.Builder
+		head := len(intPart) % 3
+		if head > 0 {
+			b.WriteString(intPart[:head])
+		}
+
+		for i := head; i < len(intPart); i += 3 {
+			if b.Len() > 0 {
+				b.WriteString(f.ThousandsSeparator)
+			}
+			b.WriteString(intPart[i : i+3])
+		}
+		intPart = b.String()
+	}
+
+	if fracPart == "" {
+		return sign + intPart
+	}
+
+	sep := f.DecimalSeparator
+	if sep == "" {
+		sep = "."
+	}
+
+	return sign + intPart + sep + fracPart
+}
@@ -0,0 +1,56 @@
+package resp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestLocaleFormatter_Format tests the Format method.
+func TestLocaleFormatter_Format(t *testing.T) {
+	date := time.Date(2024, time.March, 7, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		f    LocaleFormatter
+		v    any
+		want string
+	}{
+		{"zero int", LocaleFormatter{}, 1234567, "1234567"},
+		{"zero float", LocaleFormatter{}, 1234.5, "1234.5"},
+		{"zero date", LocaleFormatter{}, date, "2024-03-07T10:30:00Z"},
+		{"zero nil", LocaleFormatter{}, nil, ""},
+		{"zero bool", LocaleFormatter{}, true, "true"},
+		{"en int", LocaleEnUS, 1234567, "1,234,567"},
+		{"en small", LocaleEnUS, int8(-12), "-12"},
+		{"en float", LocaleEnUS, -1234.25, "-1,234.25"},
+		{"en date", LocaleEnUS, date, "03/07/2024"},
+		{"de float", LocaleDeDE, 1234567.125, "1.234.567,125"},
+		{"de uint", LocaleDeDE, uint64(1000), "1.000"},
+		{"de float32", LocaleDeDE, float32(0.5), "0,5"},
+		{"de date", LocaleDeDE, date, "07.03.2024"},
+		{"ua float", LocaleUkUA, 12345.5, "12\u00a0345,5"},
+		{"nan", LocaleDeDE, math.NaN(), "NaN"},
+		{"inf", LocaleDeDE, math.Inf(-1), "-Inf"},
+		{"string", LocaleDeDE, "1234.5", "1234.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Format(tt.v); got != tt.want {
+				t.Errorf("Format(%v) = %q, want %q", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLocaleFormatter_Delimiter tests the default delimiter.
+func TestLocaleFormatter_Delimiter(t *testing.T) {
+	if got := (LocaleFormatter{}).delimiter(); got != ',' {
+		t.Errorf("delimiter() = %q, want ','", got)
+	}
+
+	if got := LocaleDeDE.delimiter(); got != ';' {
+		t.Errorf("delimiter() = %q, want ';'", got)
+	}
+}
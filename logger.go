@@ -0,0 +1,50 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// WithLogger sets the logger used to report the errors of the
+// response methods, since they are often ignored by handlers.
+//
+// Failures to write to the client (e.g. the connection is closed,
+// short writes) are logged at the Warn level, and all other failures
+// (e.g. the data can't be encoded) are logged at the Error level.
+// The error is still returned by the response method.
+//
+// Example Usage:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+//	resp.SetDefaults(resp.WithLogger(logger))
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *Response) *Response {
+		r.logger = logger
+		return r
+	}
+}
+
+// logError logs the error returned by a response method.
+func (r *Response) logError(err error) {
+	if r.logger == nil {
+		return
+	}
+
+	level, msg := slog.LevelError, "resp: failed to send response"
+	if r.writeErr != nil && errors.Is(err, r.writeErr) {
+		level, msg = slog.LevelWarn, "resp: failed to write response"
+		if errors.Is(err, io.ErrShortWrite) {
+			msg = "resp: short write"
+		}
+	}
+
+	r.logger.LogAttrs(context.Background(), level, msg,
+		slog.Int("status", r.sentStatus),
+		slog.Int64("bytes", r.written),
+		slog.String("content_type",
+			r.httpWriter.Header().Get(HeaderContentType)),
+		slog.Any("error", err),
+	)
+}
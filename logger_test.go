@@ -0,0 +1,93 @@
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// shortWriter is an http.ResponseWriter that writes only
+// half of the data without error.
+type shortWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w shortWriter) Write(p []byte) (int, error) {
+	return w.ResponseRecorder.Write(p[:len(p)/2])
+}
+
+// newTestLogger returns a logger that writes to the buffer.
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, nil))
+}
+
+// TestWithLogger tests that errors are logged at the proper level.
+func TestWithLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		w    http.ResponseWriter
+		send func(w http.ResponseWriter, opt Option) error
+		want []string
+	}{
+		{
+			"encode error",
+			httptest.NewRecorder(),
+			func(w http.ResponseWriter, opt Option) error {
+				return JSON(w, make(chan int), opt)
+			},
+			[]string{"level=ERROR", "failed to send response",
+				"status=200"},
+		},
+		{
+			"write error",
+			&mockErrorWriter{err: errors.New("broken pipe")},
+			func(w http.ResponseWriter, opt Option) error {
+				return JSON(w, R{"a": 1}, opt)
+			},
+			[]string{"level=WARN", "failed to write response",
+				"broken pipe"},
+		},
+		{
+			"short write",
+			shortWriter{httptest.NewRecorder()},
+			func(w http.ResponseWriter, opt Option) error {
+				return HTML(w, "<p>hello</p>", opt)
+			},
+			[]string{"level=WARN", "short write", "bytes=6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.send(tt.w, WithLogger(newTestLogger(&buf)))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			got := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("log %q does not contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestWithLogger_Success tests that nothing is logged on success.
+func TestWithLogger_Success(t *testing.T) {
+	var buf bytes.Buffer
+	w := httptest.NewRecorder()
+	if err := JSON(w, R{"a": 1}, WithLogger(newTestLogger(&buf))); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("unexpected log %q", buf.String())
+	}
+}
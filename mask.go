@@ -0,0 +1,190 @@
+package resp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaskRule is the rule of MaskFields that replaces the value
+// of the field with the masked value.
+type MaskRule struct {
+	Field string        // name of the field or the map key
+	Mask  func(any) any // returns the masked value
+}
+
+// MaskWith returns the rule that masks the field with the function.
+func MaskWith(field string, mask func(any) any) MaskRule {
+	return MaskRule{Field: field, Mask: mask}
+}
+
+// MaskEmail returns the rule that masks the local part of the email
+// address except the first character, e.g. "j***@example.com".
+// Values that aren't email addresses are masked completely.
+func MaskEmail(field string) MaskRule {
+	return MaskWith(field, func(v any) any {
+		return maskEmail(fmt.Sprint(v))
+	})
+}
+
+// MaskLast4 returns the rule that masks all characters except
+// the last four, e.g. "************1111" for the card number.
+// Values not longer than four characters are masked completely.
+func MaskLast4(field string) MaskRule {
+	return MaskWith(field, func(v any) any {
+		return maskExceptLast(fmt.Sprint(v), 4)
+	})
+}
+
+// Redact returns the rule that replaces the value of the field
+// with the RedactedValue.
+func Redact(field string) MaskRule {
+	return MaskWith(field, func(any) any {
+		return RedactedValue
+	})
+}
+
+// MaskFields replaces the values of the fields of the provided data
+// with the partially masked values according to the rules, and returns
+// the result as an `R` map. Unlike ExcludeFields, the fields are kept,
+// e.g. to show "j***@example.com" as compliance requires. The input
+// handling is the same as in OnlyFields: the operation can be performed
+// on a single object, a slice of objects, an array of objects, or a map,
+// and other data is returned unchanged. Only the top-level fields are
+// processed; nil values are kept as is.
+//
+// Example Usage:
+//
+//	data := resp.MaskFields(user,
+//		resp.MaskEmail("Email"),
+//		resp.MaskLast4("Card"),
+//		resp.Redact("SSN"),
+//	)
+//	// {"Email": "j***@example.com", "Card": "************1111",
+//	//  "SSN": "[REDACTED]", ...}
+func MaskFields(data any, rules ...MaskRule) any {
+	masks := make(map[string]func(any) any, len(rules))
+	for _, rule := range rules {
+		masks[rule.Field] = rule.Mask
+	}
+
+	rv := reflect.ValueOf(data)
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		rv = rv.Elem()
+		if rv.Kind() == reflect.Struct {
+			return maskFields(rv, masks)
+		}
+	case reflect.Slice, reflect.Array:
+		length := rv.Len()
+		if length > 0 {
+			elemKind := rv.Index(0).Kind()
+			if elemKind == reflect.Ptr {
+				elemKind = rv.Index(0).Elem().Kind()
+			}
+			if elemKind == reflect.Struct {
+				result := make([]R, length)
+				for i := 0; i < length; i++ {
+					elem := rv.Index(i)
+					if elem.Kind() == reflect.Ptr {
+						elem = elem.Elem()
+					}
+					result[i] = maskFields(elem, masks)
+				}
+				return result
+			}
+		}
+	case reflect.Struct:
+		return maskFields(rv, masks)
+	case reflect.Map:
+		if m, ok := data.(map[string]any); ok {
+			return maskFieldsMap(m, masks)
+		}
+		if m, ok := data.(R); ok {
+			return maskFieldsMap(m, masks)
+		}
+	}
+
+	return data
+}
+
+// maskFields masks the fields of the provided struct value
+// and returns them as an `R` map.
+func maskFields(rv reflect.Value, masks map[string]func(any) any) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(info.fields))
+
+	for _, field := range info.fields {
+		result[field.name] = maskValue(
+			rv.Field(field.index).Interface(),
+			masks[field.name],
+		)
+	}
+
+	return result
+}
+
+// maskFieldsMap masks the values of the provided map
+// and returns them as an `R` map.
+func maskFieldsMap(data map[string]any, masks map[string]func(any) any) R {
+	result := make(R, len(data))
+	for key, value := range data {
+		result[key] = maskValue(value, masks[key])
+	}
+
+	return result
+}
+
+// maskValue returns the value masked with the function,
+// or the value unchanged if there is no function or it is nil.
+func maskValue(value any, mask func(any) any) any {
+	if mask == nil || value == nil {
+		return value
+	}
+
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return value
+		}
+		value = rv.Elem().Interface()
+	}
+
+	return mask(value)
+}
+
+// maskEmail masks the local part of the email address
+// except the first character.
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return maskExceptLast(email, 0)
+	}
+
+	_, size := utf8.DecodeRuneInString(email)
+	return email[:size] + "***" + email[at:]
+}
+
+// maskExceptLast replaces all characters of the string
+// except the last n with asterisks.
+func maskExceptLast(s string, n int) string {
+	count := utf8.RuneCountInString(s)
+	if count <= n {
+		n = 0
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	i := 0
+	for _, c := range s {
+		if i < count-n {
+			b.WriteByte('*')
+		} else {
+			b.WriteRune(c)
+		}
+		i++
+	}
+
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package resp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMaskFields tests the MaskFields function.
+func TestMaskFields(t *testing.T) {
+	type Customer struct {
+		Email string
+		Card  string
+		SSN   int
+		Note  *string
+		Name  string
+	}
+
+	customer := Customer{
+		Email: "john@example.com",
+		Card:  "4111111111111111",
+		SSN:   123456789,
+		Name:  "John",
+	}
+	rules := []MaskRule{
+		MaskEmail("Email"),
+		MaskLast4("Card"),
+		Redact("SSN"),
+		Redact("Note"),
+	}
+
+	expected := R{
+		"Email": "j***@example.com",
+		"Card":  "************1111",
+		"SSN":   RedactedValue,
+		"Note":  (*string)(nil),
+		"Name":  "John",
+	}
+
+	tests := []struct {
+		name string
+		data any
+		want any
+	}{
+		{name: "Struct", data: customer, want: expected},
+		{name: "Pointer", data: &customer, want: expected},
+		{name: "Slice", data: []Customer{customer}, want: []R{expected}},
+		{
+			name: "Map",
+			data: R{"Email": "ann@example.com", "Card": "1234"},
+			want: R{"Email": "a***@example.com", "Card": "****"},
+		},
+		{name: "Non-struct", data: 42, want: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MaskFields(tt.data, rules...)
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("MaskFields() = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+// TestMaskHelpers tests the masking of the values.
+func TestMaskHelpers(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{maskEmail("j@example.com"), "j***@example.com"},
+		{maskEmail("їжак@example.com"), "ї***@example.com"},
+		{maskEmail("not-an-email"), "************"},
+		{maskEmail("@example.com"), "************"},
+		{maskExceptLast("4111 1111", 4), "*****1111"},
+		{maskExceptLast("абвгд", 4), "*бвгд"},
+		{maskExceptLast("123", 4), "***"},
+		{maskExceptLast("", 4), ""},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package resp
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// DefaultMetaKey is the key of the metadata block
+// injected into JSON responses.
+const DefaultMetaKey = "meta"
+
+// globalMeta holds the metadata set by SetGlobalMeta.
+var globalMeta struct {
+	sync.RWMutex
+	meta R
+}
+
+// SetGlobalMeta sets the metadata injected into every JSON response,
+// e.g. the build version or the region. Each call replaces the
+// previously set metadata; call it with nil to remove it.
+//
+// It is intended to be called once, at application startup.
+// See WithMeta for the details of the injection.
+//
+// Example Usage:
+//
+//	resp.SetGlobalMeta(resp.R{"version": version, "region": "eu-west-1"})
+func SetGlobalMeta(meta R) {
+	globalMeta.Lock()
+	defer globalMeta.Unlock()
+
+	globalMeta.meta = make(R, len(meta))
+	for k, v := range meta {
+		globalMeta.meta[k] = v
+	}
+}
+
+// WithMeta adds the key-value pair to the metadata block of the
+// JSON response.
+//
+// The metadata (the global metadata set by SetGlobalMeta, overridden
+// by the values set with WithMeta) is injected under the "meta" key
+// (see WithMetaKey) into the top-level JSON object of the response.
+// If the data already has the key with an object value, the metadata
+// is merged into it and the values of the data take precedence. Data
+// that isn't encoded as a JSON object (e.g. arrays) is sent unchanged.
+//
+// Example Usage:
+//
+//	resp.JSON(w, user, resp.WithMeta("trace_id", traceID))
+//	// {"id": 1, "name": "Go Loop", "meta": {"trace_id": "..."}}
+func WithMeta(key string, value any) Option {
+	return func(r *Response) *Response {
+		if r.meta == nil {
+			r.meta = make(R)
+		}
+		r.meta[key] = value
+		return r
+	}
+}
+
+// WithMetaKey sets the key of the metadata block in JSON responses.
+// The default key is DefaultMetaKey.
+func WithMetaKey(key string) Option {
+	return func(r *Response) *Response {
+		r.metaKey = key
+		return r
+	}
+}
+
+// collectMeta returns the metadata of the response,
+// or nil if there is no metadata.
+func (r *Response) collectMeta() R {
+	globalMeta.RLock()
+	defer globalMeta.RUnlock()
+
+	if len(globalMeta.meta) == 0 && len(r.meta) == 0 {
+		return nil
+	}
+
+	meta := make(R, len(globalMeta.meta)+len(r.meta))
+	for k, v := range globalMeta.meta {
+		meta[k] = v
+	}
+
+	for k, v := range r.meta {
+		meta[k] = v
+	}
+
+	return meta
+}
+
+// injectMeta returns the data with the metadata injected.
+// If there is no metadata or the data isn't a JSON object,
+// the data is returned unchanged.
+func (r *Response) injectMeta(data any) (any, error) {
+	meta := r.collectMeta()
+	if meta == nil {
+		return data, nil
+	}
+
+	key := r.metaKey
+	if key == "" {
+		key = DefaultMetaKey
+	}
+
+	if obj, ok := jsonMap(data); ok {
+		return mergeMeta(obj, key, meta), nil
+	}
+
+	return r.setJSONField(data, key, false,
+		func(existing json.RawMessage) (any, bool) {
+			if existing == nil {
+				return meta, true
+			}
+
+			var own map[string]json.RawMessage
+			if err := json.Unmarshal(existing, &own); err != nil {
+				// The key is used by the data for something else.
+				return nil, false
+			}
+
+			merged := make(R, len(meta)+len(own))
+			for k, v := range meta {
+				merged[k] = v
+			}
+
+			for k, v := range own {
+				merged[k] = v
+			}
+
+			return merged, true
+		})
+}
+
+// jsonMap returns the data as the map of the JSON object
+// if it is a map with string keys.
+func jsonMap(data any) (map[string]any, bool) {
+	switch m := data.(type) {
+	case R:
+		return m, true
+	case map[string]any:
+		return m, true
+	}
+
+	return nil, false
+}
+
+// setJSONField returns the data encoded with the JSON encoder of the
+// response with the top-level field of the key set to the value
+// returned by the function for the existing value of the field (nil if
+// there is no such field), unless the function returns false. The other
+// fields are kept as encoded, in their order, and the new field is
+// added after them, or before them if first is true. If the data isn't
+// encoded as a JSON object (e.g. arrays), it is returned unchanged.
+func (r *Response) setJSONField(
+	data any,
+	key string,
+	first bool,
+	value func(existing json.RawMessage) (any, bool),
+) (any, error) {
+	var buf bytes.Buffer
+	if err := r.encodeJSON(&buf, data); err != nil {
+		return nil, err
+	}
+
+	obj := bytes.TrimSpace(buf.Bytes())
+	if len(obj) == 0 || obj[0] != '{' {
+		return data, nil
+	}
+
+	start, end, err := jsonFieldSpan(obj, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing json.RawMessage
+	if start >= 0 {
+		existing = obj[start:end]
+	}
+
+	v, ok := value(existing)
+	if !ok {
+		return data, nil
+	}
+
+	var field bytes.Buffer
+	if err := r.encodeJSON(&field, v); err != nil {
+		return nil, err
+	}
+	encoded := bytes.TrimSpace(field.Bytes())
+
+	result := make([]byte, 0, len(obj)+len(key)+len(encoded)+4)
+	if start >= 0 {
+		result = append(result, obj[:start]...)
+		result = append(result, encoded...)
+		result = append(result, obj[end:]...)
+		return json.RawMessage(result), nil
+	}
+
+	// The new field is added to the start or the end of the object.
+	name, _ := json.Marshal(key)
+	body := bytes.TrimSpace(obj[1 : len(obj)-1])
+	result = append(result, '{')
+	if !first && len(body) > 0 {
+		result = append(result, body...)
+		result = append(result, ',')
+	}
+	result = append(result, name...)
+	result = append(result, ':')
+	result = append(result, encoded...)
+	if first && len(body) > 0 {
+		result = append(result, ',')
+		result = append(result, body...)
+	}
+	result = append(result, '}')
+
+	return json.RawMessage(result), nil
+}
+
+// jsonFieldSpan returns the offsets of the value of the top-level field
+// of the key in the encoded JSON object, or -1 if there is no field.
+func jsonFieldSpan(obj []byte, key string) (start, end int, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(obj))
+	if _, err := decoder.Token(); err != nil {
+		return -1, -1, err
+	}
+
+	for decoder.More() {
+		name, err := decoder.Token()
+		if err != nil {
+			return -1, -1, err
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return -1, -1, err
+		}
+
+		if name == key {
+			end := int(decoder.InputOffset())
+			return end - len(raw), end, nil
+		}
+	}
+
+	return -1, -1, nil
+}
+
+// mergeMeta returns a copy of the object with the metadata
+// stored under the key.
+func mergeMeta(obj map[string]any, key string, meta R) R {
+	result := make(R, len(obj)+1)
+	for k, v := range obj {
+		result[k] = v
+	}
+
+	existing, ok := obj[key]
+	if !ok {
+		result[key] = meta
+		return result
+	}
+
+	var own map[string]any
+	switch m := existing.(type) {
+	case R:
+		own = m
+	case map[string]any:
+		own = m
+	default:
+		// The key is used by the data for something else.
+		return result
+	}
+
+	merged := make(R, len(meta)+len(own))
+	for k, v := range meta {
+		merged[k] = v
+	}
+
+	for k, v := range own {
+		merged[k] = v
+	}
+
+	result[key] = merged
+	return result
+}
@@ -0,0 +1,120 @@
+package resp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithMeta tests the injection of the metadata into JSON responses.
+func TestWithMeta(t *testing.T) {
+	SetGlobalMeta(R{"version": "1.2.3", "region": "eu"})
+	defer SetGlobalMeta(nil)
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	type page struct {
+		Total int64 `json:"total"`
+		Meta  R     `json:"meta"`
+		Items []int `json:"items"`
+	}
+
+	unescaped := func(w io.Writer, v any) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(v)
+	}
+
+	tests := []struct {
+		name string
+		data any
+		opts []Option
+		want string
+	}{
+		{
+			name: "Struct with global meta",
+			data: user{ID: 1, Name: "Go"},
+			want: `{"id":1,"name":"Go",` +
+				`"meta":{"region":"eu","version":"1.2.3"}}` + "\n",
+		},
+		{
+			name: "Per-response meta overrides global",
+			data: R{"ok": true},
+			opts: []Option{WithMeta("region", "us")},
+			want: `{"meta":{"region":"us","version":"1.2.3"},"ok":true}` +
+				"\n",
+		},
+		{
+			name: "Existing meta is merged",
+			data: R{"meta": R{"page": 2, "region": "ua"}},
+			want: `{"meta":{"page":2,"region":"ua","version":"1.2.3"}}` +
+				"\n",
+		},
+		{
+			name: "Custom key",
+			data: R{"ok": true},
+			opts: []Option{WithMetaKey("_meta")},
+			want: `{"_meta":{"region":"eu","version":"1.2.3"},"ok":true}` +
+				"\n",
+		},
+		{
+			name: "Non-object meta value is kept",
+			data: R{"meta": "mine"},
+			want: `{"meta":"mine"}` + "\n",
+		},
+		{
+			name: "Struct keeps field order and int64 precision",
+			data: page{Total: 9007199254740993, Items: []int{1}},
+			want: `{"total":9007199254740993,` +
+				`"meta":{"region":"eu","version":"1.2.3"},"items":[1]}` + "\n",
+		},
+		{
+			name: "Struct meta is merged in place",
+			data: page{Total: 1, Meta: R{"region": "us", "id": 1 << 62}},
+			want: `{"total":1,"meta":{"id":4611686018427387904,` +
+				`"region":"us","version":"1.2.3"},"items":null}` + "\n",
+		},
+		{
+			name: "Custom JSON encoder is used",
+			data: user{ID: 1, Name: "<Go>"},
+			opts: []Option{ApplyJSONEncoder(unescaped)},
+			want: `{"id":1,"name":"<Go>",` +
+				`"meta":{"region":"eu","version":"1.2.3"}}` + "\n",
+		},
+		{
+			name: "Array is unchanged",
+			data: []int{1, 2},
+			want: "[1,2]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := JSON(w, tt.data, tt.opts...); err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("JSON() body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithMetaWithoutGlobal tests that the data is unchanged
+// when there is no metadata.
+func TestWithMetaWithoutGlobal(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := JSON(w, R{"ok": true}); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	if got := w.Body.String(); got != "{\"ok\":true}\n" {
+		t.Errorf("JSON() body = %s", got)
+	}
+}
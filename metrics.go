@@ -0,0 +1,46 @@
+package resp
+
+import (
+	"net/http"
+	"time"
+)
+
+// Metrics is the interface of a metrics collector that observes
+// written responses. See the WithMetrics option.
+type Metrics interface {
+	// ObserveResponse is called after the response is written.
+	ObserveResponse(
+		method string,
+		route string,
+		status int,
+		bytes int64,
+		duration time.Duration,
+	)
+}
+
+// WithMetrics reports the response to the metrics collector after it
+// is written. The method is taken from the request. The route should
+// be the route pattern (e.g. "/users/{id}"), not the request path,
+// to keep the cardinality of the metrics low; if it is empty, the
+// request path is used.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.JSON(w, data, resp.WithMetrics(metrics, r, "/users/{id}"))
+//	}
+func WithMetrics(m Metrics, req *http.Request, route string) Option {
+	if route == "" && req.URL != nil {
+		route = req.URL.Path
+	}
+
+	return WithAfterWrite(func(info ResponseInfo) {
+		m.ObserveResponse(
+			req.Method,
+			route,
+			info.Status,
+			info.Bytes,
+			info.Duration,
+		)
+	})
+}
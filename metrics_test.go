@@ -0,0 +1,54 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testMetrics is a Metrics implementation that stores
+// the last observation.
+type testMetrics struct {
+	method, route string
+	status        int
+	bytes         int64
+	calls         int
+}
+
+func (m *testMetrics) ObserveResponse(
+	method, route string,
+	status int,
+	bytes int64,
+	duration time.Duration,
+) {
+	m.method, m.route = method, route
+	m.status, m.bytes = status, bytes
+	m.calls++
+}
+
+// TestWithMetrics tests the WithMetrics option.
+func TestWithMetrics(t *testing.T) {
+	m := &testMetrics{}
+	r := httptest.NewRequest(http.MethodPost, "/users/7", nil)
+
+	w := httptest.NewRecorder()
+	err := String(w, "created", WithMetrics(m, r, "/users/{id}"),
+		WithStatusCreated())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.calls != 1 || m.method != http.MethodPost ||
+		m.route != "/users/{id}" || m.status != http.StatusCreated ||
+		m.bytes != 7 {
+		t.Errorf("WithMetrics() observed %+v", m)
+	}
+
+	// The path is used when the route isn't set.
+	w = httptest.NewRecorder()
+	NoContent(w, WithMetrics(m, r, ""))
+	if m.route != "/users/7" || m.status != http.StatusNoContent {
+		t.Errorf("WithMetrics() observed %+v", m)
+	}
+}
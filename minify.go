@@ -0,0 +1,159 @@
+package resp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Minifier minifies the response bodies of the media types.
+// Implement it to plug in a full-featured minifier (e.g. an adapter
+// for github.com/tdewolff/minify) without adding the dependency to
+// resp itself. See the WithMinify option.
+type Minifier interface {
+	// Minify returns the minified data of the media type
+	// (e.g. "text/html"), or the data unchanged if the media
+	// type isn't supported.
+	Minify(mediaType string, data []byte) ([]byte, error)
+}
+
+// DefaultMinifier is the built-in Minifier: it compacts JSON
+// (json.Compact) and collapses the runs of whitespace of HTML into
+// a single space or line feed, except within the pre, textarea,
+// script and style elements, whose content is kept as is.
+var DefaultMinifier Minifier = defaultMinifier{}
+
+// WithMinify strips the insignificant whitespace from the HTML and JSON
+// responses (HTML, SafeHTML, Render, JSON) before writing, which saves
+// the bandwidth of large server-rendered pages and indented JSON (e.g.
+// from a custom encoder). The DefaultMinifier is used unless a custom
+// one is passed.
+//
+// The minified JSON is buffered, so it is sent only
+// after it is encoded completely.
+//
+// Example Usage:
+//
+//	resp.HTML(w, page, resp.WithMinify())
+func WithMinify(m ...Minifier) Option {
+	return func(r *Response) *Response {
+		r.minifier = DefaultMinifier
+		if len(m) > 0 && m[0] != nil {
+			r.minifier = m[0]
+		}
+
+		return r
+	}
+}
+
+// minify minifies the data of the media type
+// if the minification is enabled.
+func (r *Response) minify(mediaType string, data []byte) ([]byte, error) {
+	if r.minifier == nil {
+		return data, nil
+	}
+
+	result, err := r.minifier.Minify(mediaType, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to minify %s: %w", mediaType, err)
+	}
+
+	return result, nil
+}
+
+// defaultMinifier is the implementation of the DefaultMinifier.
+type defaultMinifier struct{}
+
+// rawHTMLElements matches the HTML elements
+// whose content must not be minified.
+var rawHTMLElements = regexp.MustCompile(
+	`(?is)<(pre|textarea|script|style)\b.*?</(?:pre|textarea|script|style)>`)
+
+// Minify minifies the JSON and HTML data.
+func (defaultMinifier) Minify(mediaType string, data []byte) ([]byte, error) {
+	switch mediaType {
+	case MIMEApplicationJSON:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err != nil {
+			return nil, err
+		}
+
+		// Keep the line feed written by json.Encoder.
+		if bytes.HasSuffix(data, []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	case MIMETextHTML:
+		return minifyHTML(data), nil
+	}
+
+	return data, nil
+}
+
+// minifyHTML collapses the runs of whitespace of the HTML data,
+// except within the raw elements.
+func minifyHTML(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+
+	last := 0
+	for _, loc := range rawHTMLElements.FindAllIndex(data, -1) {
+		result = collapseSpace(result, data[last:loc[0]])
+		result = append(result, data[loc[0]:loc[1]]...)
+		last = loc[1]
+	}
+	result = collapseSpace(result, data[last:])
+
+	return bytes.TrimSpace(result)
+}
+
+// collapseSpace appends the data to the dst with each run of
+// whitespace replaced by a line feed (if the run contains one)
+// or a space.
+func collapseSpace(dst, data []byte) []byte {
+	for i := 0; i < len(data); {
+		if !isHTMLSpace(data[i]) {
+			dst = append(dst, data[i])
+			i++
+			continue
+		}
+
+		sep := byte(' ')
+		for ; i < len(data) && isHTMLSpace(data[i]); i++ {
+			if data[i] == '\n' {
+				sep = '\n'
+			}
+		}
+		dst = append(dst, sep)
+	}
+
+	return dst
+}
+
+// isHTMLSpace reports whether the byte is an HTML whitespace.
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// writeMinifiedJSON encodes the data into a buffer,
+// minifies it and writes the result.
+func (r *Response) writeMinifiedJSON(data any) error {
+	var buf bytes.Buffer
+	if r.jsonEncodeFunc != nil {
+		if err := r.jsonEncodeFunc(&buf, data); err != nil {
+			return r.encodeError("custom JSON encoder failed", err)
+		}
+	} else if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return r.encodeError("failed to encode JSON response", err)
+	}
+
+	body, err := r.minify(MIMEApplicationJSON, buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	r.setBodyDigest(body)
+	r.writeHeader(r.statusCode)
+	_, err = r.write(body)
+	return err
+}
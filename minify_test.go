@@ -0,0 +1,89 @@
+package resp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithMinifyHTML tests the minification of the HTML responses.
+func TestWithMinifyHTML(t *testing.T) {
+	html := "\n<html>\n  <body>\n    <p>Hello,   <b>World</b></p>\n" +
+		"    <pre>  keep\n    this  </pre>\n" +
+		"    <script>if (a  <  b) {}</script>\n  </body>\n</html>\n"
+	want := "<html>\n<body>\n<p>Hello, <b>World</b></p>\n" +
+		"<pre>  keep\n    this  </pre>\n" +
+		"<script>if (a  <  b) {}</script>\n</body>\n</html>"
+
+	w := httptest.NewRecorder()
+	if err := HTML(w, html, WithMinify()); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+
+	if got := w.Body.String(); got != want {
+		t.Errorf("HTML() body = %q, want %q", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	if err := HTML(w, html); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+
+	if got := w.Body.String(); got != html {
+		t.Errorf("HTML() body = %q, want unchanged", got)
+	}
+}
+
+// TestWithMinifyJSON tests the minification of the JSON responses.
+func TestWithMinifyJSON(t *testing.T) {
+	indent := func(w io.Writer, v any) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	w := httptest.NewRecorder()
+	err := JSON(w, R{"name": "Go", "tags": []string{"a", "b"}},
+		ApplyJSONEncoder(indent), WithMinify())
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	want := `{"name":"Go","tags":["a","b"]}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("JSON() body = %q, want %q", got, want)
+	}
+}
+
+// replaceMinifier is a custom Minifier of the tests.
+type replaceMinifier struct{ err error }
+
+// Minify returns the error or replaces the data.
+func (m replaceMinifier) Minify(mediaType string, data []byte) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return []byte(mediaType), nil
+}
+
+// TestWithMinifyCustom tests the custom Minifier.
+func TestWithMinifyCustom(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := HTML(w, "<p>x</p>", WithMinify(replaceMinifier{})); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+
+	if got := w.Body.String(); got != MIMETextHTML {
+		t.Errorf("HTML() body = %q, want %q", got, MIMETextHTML)
+	}
+
+	errMinify := errors.New("minify failed")
+	w = httptest.NewRecorder()
+	err := JSON(w, R{"a": 1}, WithMinify(replaceMinifier{err: errMinify}))
+	if !errors.Is(err, errMinify) {
+		t.Errorf("JSON() error = %v, want %v", err, errMinify)
+	}
+}
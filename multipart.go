@@ -0,0 +1,133 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+)
+
+// errPartWriterClosed is returned when a part is written
+// after the part writer is closed.
+var errPartWriterClosed = errors.New("multipart writer is closed")
+
+// PartWriter writes the parts of a multipart/x-mixed-replace
+// response. See the MultipartReplace function for details.
+type PartWriter struct {
+	r      *Response
+	mw     *multipart.Writer
+	closed bool
+}
+
+// MultipartReplace starts a multipart/x-mixed-replace response (e.g. an
+// MJPEG camera stream), where each part replaces the previous one in the
+// client, and returns the writer of the parts. If the boundary is empty,
+// a random one is used.
+//
+// The status and headers are sent immediately. Each part is flushed to
+// the client as soon as it is written. The writer must be closed to end
+// the stream (the after-write hooks are called then).
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    pw, err := resp.MultipartReplace(w, "frame")
+//	    if err != nil {
+//	        return
+//	    }
+//	    defer pw.Close()
+//
+//	    for frame := range camera.Frames(r.Context()) {
+//	        if err := pw.WritePart("image/jpeg", frame); err != nil {
+//	            return
+//	        }
+//	    }
+//	}
+func MultipartReplace(
+	w http.ResponseWriter,
+	boundary string,
+	opts ...Option,
+) (*PartWriter, error) {
+	return NewResponse(w, opts...).MultipartReplace(boundary)
+}
+
+// MultipartReplace starts a multipart/x-mixed-replace response.
+// See the MultipartReplace function for details.
+func (r *Response) MultipartReplace(boundary string) (*PartWriter, error) {
+	mw := multipart.NewWriter(r.body())
+	if boundary != "" {
+		if err := mw.SetBoundary(boundary); err != nil {
+			err = fmt.Errorf("invalid multipart boundary: %w", err)
+			r.finish(&err)
+			return nil, err
+		}
+	}
+
+	r.httpWriter.Header().Set(HeaderContentType,
+		MIMEMultipartMixedReplace+"; boundary="+mw.Boundary())
+	r.prepare(StatusOK)
+	r.writeHeader(r.statusCode)
+	r.flush()
+
+	return &PartWriter{r: r, mw: mw}, nil
+}
+
+// Boundary returns the boundary that separates the parts.
+func (pw *PartWriter) Boundary() string {
+	return pw.mw.Boundary()
+}
+
+// WritePart writes the part with the content type and
+// the Content-Length header, and flushes it to the client.
+func (pw *PartWriter) WritePart(contentType string, data []byte) error {
+	header := http.Header{}
+	header.Set(HeaderContentType, contentType)
+	return pw.WritePartHeader(header, data)
+}
+
+// WritePartHeader writes the part with the headers, and flushes it to
+// the client. The Content-Length header is set if it isn't in the headers.
+func (pw *PartWriter) WritePartHeader(header http.Header, data []byte) error {
+	if pw.closed {
+		return errPartWriterClosed
+	}
+
+	h := make(textproto.MIMEHeader, len(header)+1)
+	for k, v := range header {
+		h[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+	if _, ok := h[HeaderContentLength]; !ok {
+		h.Set(HeaderContentLength, strconv.Itoa(len(data)))
+	}
+
+	part, err := pw.mw.CreatePart(h)
+	if err != nil {
+		return fmt.Errorf("failed to write part: %w", err)
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to write part: %w", err)
+	}
+
+	pw.r.flush()
+	return pw.r.writeErr
+}
+
+// Close writes the closing boundary, ends the response and calls the
+// after-write hooks. Closing the closed writer has no effect.
+func (pw *PartWriter) Close() (err error) {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+	defer pw.r.finish(&err)
+
+	if err := pw.mw.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart stream: %w", err)
+	}
+
+	pw.r.flush()
+	return nil
+}
@@ -0,0 +1,100 @@
+package resp
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMultipartReplace tests the MultipartReplace function.
+func TestMultipartReplace(t *testing.T) {
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	var info ResponseInfo
+	pw, err := MultipartReplace(w, "frame", WithAfterWrite(
+		func(i ResponseInfo) { info = i },
+	))
+	if err != nil {
+		t.Fatalf("MultipartReplace() error = %v", err)
+	}
+
+	if pw.Boundary() != "frame" {
+		t.Errorf("Boundary() = %q, want %q", pw.Boundary(), "frame")
+	}
+
+	if err := pw.WritePart("image/jpeg", []byte("first")); err != nil {
+		t.Fatalf("WritePart() error = %v", err)
+	}
+
+	header := http.Header{}
+	header.Set(HeaderContentType, "image/jpeg")
+	header.Set("X-Timestamp", "42")
+	if err := pw.WritePartHeader(header, []byte("second")); err != nil {
+		t.Fatalf("WritePartHeader() error = %v", err)
+	}
+
+	if err := pw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := pw.WritePart("image/jpeg", nil); err == nil {
+		t.Error("WritePart() after Close error = nil")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(
+		w.Header().Get(HeaderContentType))
+	if err != nil || mediaType != MIMEMultipartMixedReplace ||
+		params["boundary"] != "frame" {
+		t.Fatalf("Content-Type = %q", w.Header().Get(HeaderContentType))
+	}
+
+	if w.flushes != 4 {
+		t.Errorf("flushes = %d, want %d", w.flushes, 4)
+	}
+
+	if info.Status != StatusOK || info.Bytes != int64(w.Body.Len()) {
+		t.Errorf("ResponseInfo = %+v", info)
+	}
+
+	mr := multipart.NewReader(strings.NewReader(w.Body.String()), "frame")
+	wants := []struct{ body, length, stamp string }{
+		{"first", "5", ""},
+		{"second", "6", "42"},
+	}
+	for _, want := range wants {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+
+		body, _ := io.ReadAll(part)
+		if string(body) != want.body {
+			t.Errorf("part body = %q, want %q", body, want.body)
+		}
+
+		if got := part.Header.Get(HeaderContentLength); got != want.length {
+			t.Errorf("part Content-Length = %q, want %q", got, want.length)
+		}
+
+		if got := part.Header.Get("X-Timestamp"); got != want.stamp {
+			t.Errorf("part X-Timestamp = %q, want %q", got, want.stamp)
+		}
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("NextPart() error = %v, want %v", err, io.EOF)
+	}
+}
+
+// TestMultipartReplace_InvalidBoundary tests that
+// an invalid boundary is rejected.
+func TestMultipartReplace_InvalidBoundary(t *testing.T) {
+	w := httptest.NewRecorder()
+	if _, err := MultipartReplace(w, "bad boundary\n"); err == nil {
+		t.Fatal("MultipartReplace() error = nil, want error")
+	}
+}
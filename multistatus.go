@@ -0,0 +1,195 @@
+package resp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// StatusResult is the result of an item of the batch request,
+// sent in the 207 (Multi-Status) response.
+type StatusResult struct {
+	// ID identifies the item, e.g. its index or URL
+	// (the href of the WebDAV response).
+	ID string `json:"id"`
+
+	// Status is the status code of the item.
+	Status int `json:"status"`
+
+	// Headers are the headers of the item response.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body is the body of the item response, e.g. the created
+	// resource or the ErrorResponse. It isn't sent as XML.
+	Body any `json:"body,omitempty"`
+}
+
+// MultiStatusEnvelope is the JSON body sent by MultiStatus.
+type MultiStatusEnvelope struct {
+	Results []StatusResult `json:"results"`
+}
+
+// MultiStatusBuilder collects the results of the items of the batch
+// request. It is safe for concurrent use, so the items can be
+// processed in parallel.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    batch := resp.NewMultiStatusBuilder()
+//	    for i, item := range items {
+//	        id := strconv.Itoa(i)
+//	        created, err := store.Create(item)
+//	        if err != nil {
+//	            batch.Add(id, resp.StatusConflict,
+//	                resp.ErrorResponse{Code: 1, Message: err.Error()})
+//	            continue
+//	        }
+//	        batch.Add(id, resp.StatusCreated, created)
+//	    }
+//
+//	    batch.Send(w)
+//	}
+type MultiStatusBuilder struct {
+	mu      sync.Mutex
+	results []StatusResult
+}
+
+// NewMultiStatusBuilder creates a new MultiStatusBuilder.
+func NewMultiStatusBuilder() *MultiStatusBuilder {
+	return &MultiStatusBuilder{}
+}
+
+// Add adds the result of the item with the status and the body,
+// and returns the builder.
+func (b *MultiStatusBuilder) Add(
+	id string,
+	status int,
+	body any,
+) *MultiStatusBuilder {
+	return b.AddResult(StatusResult{ID: id, Status: status, Body: body})
+}
+
+// AddResult adds the result of the item and returns the builder.
+func (b *MultiStatusBuilder) AddResult(
+	result StatusResult,
+) *MultiStatusBuilder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.results = append(b.results, result)
+	return b
+}
+
+// Results returns a copy of the collected results, in the order
+// they are added.
+func (b *MultiStatusBuilder) Results() []StatusResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]StatusResult(nil), b.results...)
+}
+
+// Send sends the collected results with MultiStatus.
+func (b *MultiStatusBuilder) Send(w http.ResponseWriter, opts ...Option) error {
+	return MultiStatus(w, b.Results(), opts...)
+}
+
+// SendXML sends the collected results with MultiStatusXML.
+func (b *MultiStatusBuilder) SendXML(
+	w http.ResponseWriter,
+	opts ...Option,
+) error {
+	return MultiStatusXML(w, b.Results(), opts...)
+}
+
+// MultiStatus sends the results of the items of the batch request as
+// the 207 (Multi-Status) JSON response: {"results": [{"id": "1",
+// "status": 201, "body": {...}}, ...]}. The status can be changed with
+// the options, e.g. to 200 if all the items succeeded.
+//
+// Example Usage:
+//
+//	resp.MultiStatus(w, []resp.StatusResult{
+//	    {ID: "1", Status: resp.StatusCreated, Body: user},
+//	    {ID: "2", Status: resp.StatusConflict},
+//	})
+func MultiStatus(
+	w http.ResponseWriter,
+	results []StatusResult,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).MultiStatus(results)
+}
+
+// MultiStatus sends the results as the 207 (Multi-Status) JSON
+// response. See the MultiStatus function for details.
+func (r *Response) MultiStatus(results []StatusResult) error {
+	if results == nil {
+		results = []StatusResult{}
+	}
+
+	r.prepare(StatusMultiStatus)
+	return r.JSON(MultiStatusEnvelope{Results: results})
+}
+
+// davMultiStatus is the WebDAV multistatus XML document (RFC 4918).
+type davMultiStatus struct {
+	XMLName   xml.Name      `xml:"D:multistatus"`
+	Namespace string        `xml:"xmlns:D,attr"`
+	Responses []davResponse `xml:"D:response"`
+}
+
+// davResponse is the response of the WebDAV multistatus document.
+type davResponse struct {
+	Href   string `xml:"D:href"`
+	Status string `xml:"D:status"`
+}
+
+// MultiStatusXML sends the results of the items as the 207
+// (Multi-Status) WebDAV XML response (RFC 4918), where the ID of each
+// result is sent as the href, e.g.:
+//
+//	<D:multistatus xmlns:D="DAV:">
+//	  <D:response>
+//	    <D:href>/files/a.txt</D:href>
+//	    <D:status>HTTP/1.1 423 Locked</D:status>
+//	  </D:response>
+//	</D:multistatus>
+//
+// The headers and the bodies of the results aren't sent.
+func MultiStatusXML(
+	w http.ResponseWriter,
+	results []StatusResult,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).MultiStatusXML(results)
+}
+
+// MultiStatusXML sends the results as the 207 (Multi-Status) WebDAV
+// XML response. See the MultiStatusXML function for details.
+func (r *Response) MultiStatusXML(results []StatusResult) (err error) {
+	defer r.finish(&err)
+
+	doc := davMultiStatus{Namespace: "DAV:"}
+	for _, result := range results {
+		doc.Responses = append(doc.Responses, davResponse{
+			Href: result.ID,
+			Status: fmt.Sprintf("HTTP/1.1 %d %s",
+				result.Status, StatusText(result.Status)),
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return r.encodeError("failed to encode multistatus", err)
+	}
+	data = append([]byte(xml.Header), data...)
+
+	r.prepare(StatusMultiStatus, MIMEApplicationXMLCharsetUTF8)
+	r.setBodyDigest(data)
+	r.writeHeader(r.statusCode)
+	_, err = r.write(data)
+	return err
+}
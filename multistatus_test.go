@@ -0,0 +1,151 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestMultiStatus tests the MultiStatus function.
+func TestMultiStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []StatusResult
+		opts       []Option
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "Results",
+			results: []StatusResult{
+				{ID: "1", Status: StatusCreated, Body: R{"id": 1}},
+				{
+					ID:      "2",
+					Status:  StatusConflict,
+					Headers: map[string]string{"Retry-After": "10"},
+				},
+			},
+			wantStatus: StatusMultiStatus,
+			want: `{"results":[` +
+				`{"id":"1","status":201,"body":{"id":1}},` +
+				`{"id":"2","status":409,` +
+				`"headers":{"Retry-After":"10"}}]}` + "\n",
+		},
+		{
+			name:       "Empty",
+			wantStatus: StatusMultiStatus,
+			want:       `{"results":[]}` + "\n",
+		},
+		{
+			name:       "Custom status",
+			results:    []StatusResult{{ID: "1", Status: StatusOK}},
+			opts:       []Option{WithStatusOK()},
+			wantStatus: StatusOK,
+			want:       `{"results":[{"id":"1","status":200}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := MultiStatus(w, tt.results, tt.opts...); err != nil {
+				t.Fatalf("MultiStatus() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			ct := w.Header().Get(HeaderContentType)
+			if ct != MIMEApplicationJSONCharsetUTF8 {
+				t.Errorf("Content-Type = %q, want %q",
+					ct, MIMEApplicationJSONCharsetUTF8)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMultiStatusXML tests the MultiStatusXML function.
+func TestMultiStatusXML(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := MultiStatusXML(w, []StatusResult{
+		{ID: "/files/a.txt", Status: StatusOK},
+		{ID: "/files/b&c.txt", Status: StatusLocked, Body: "ignored"},
+	})
+	if err != nil {
+		t.Fatalf("MultiStatusXML() error = %v", err)
+	}
+
+	if w.Code != StatusMultiStatus {
+		t.Errorf("status = %d, want %d", w.Code, StatusMultiStatus)
+	}
+
+	ct := w.Header().Get(HeaderContentType)
+	if ct != MIMEApplicationXMLCharsetUTF8 {
+		t.Errorf("Content-Type = %q, want %q",
+			ct, MIMEApplicationXMLCharsetUTF8)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<D:multistatus xmlns:D="DAV:">`,
+		`<D:href>/files/a.txt</D:href>`,
+		`<D:status>HTTP/1.1 200 OK</D:status>`,
+		`<D:href>/files/b&amp;c.txt</D:href>`,
+		`<D:status>HTTP/1.1 423 Locked</D:status>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body = %s, want to contain %s", body, want)
+		}
+	}
+
+	if strings.Contains(body, "ignored") {
+		t.Errorf("body = %s, want without the result body", body)
+	}
+}
+
+// TestMultiStatusBuilder tests the MultiStatusBuilder type.
+func TestMultiStatusBuilder(t *testing.T) {
+	b := NewMultiStatusBuilder()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b.Add(strconv.Itoa(i), StatusCreated, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	results := b.Results()
+	if len(results) != 10 {
+		t.Fatalf("results = %d, want %d", len(results), 10)
+	}
+
+	b.AddResult(StatusResult{ID: "x", Status: StatusNotFound})
+	if len(results) != 10 {
+		t.Errorf("Results() isn't a copy")
+	}
+
+	w := httptest.NewRecorder()
+	if err := b.Send(w); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if w.Code != StatusMultiStatus {
+		t.Errorf("status = %d, want %d", w.Code, StatusMultiStatus)
+	}
+
+	want := `{"id":"x","status":404}]}`
+	if got := w.Body.String(); !strings.HasSuffix(got, want+"\n") {
+		t.Errorf("body = %s, want suffix %s", got, want)
+	}
+}
@@ -0,0 +1,228 @@
+package resp
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Variant describes a representation the server can produce.
+// It is used by Negotiate to select the representation that
+// best matches the Accept header of the request.
+type Variant struct {
+	// MediaType is the media type of the representation,
+	// e.g. "application/json".
+	MediaType string `json:"media_type"`
+
+	// Profile is the optional profile of the representation
+	// (the "profile" media type parameter).
+	Profile string `json:"profile,omitempty"`
+
+	// Version is the optional version of the representation
+	// (the "version" media type parameter).
+	Version string `json:"version,omitempty"`
+
+	// URI is the optional location of the representation. If empty,
+	// the alternate links point to the requested URL.
+	URI string `json:"href,omitempty"`
+}
+
+// NotAcceptableResponse represents the body of the 406 Not Acceptable
+// response sent by Negotiate. It lists the available variants so the
+// client can correct its Accept header.
+type NotAcceptableResponse struct {
+	ErrorResponse
+	Variants []Variant `json:"variants"`
+}
+
+// acceptRange represents a media range of the Accept header.
+type acceptRange struct {
+	mediaType string
+	params    map[string]string
+	q         float64
+	order     int
+}
+
+// specificity returns the precedence of the media range:
+// more specific ranges override less specific ones.
+func (a acceptRange) specificity() int {
+	switch {
+	case a.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(a.mediaType, "/*"):
+		return 1
+	}
+	return 2 + len(a.params)
+}
+
+// matches returns true if the variant matches the media range.
+func (a acceptRange) matches(v Variant) bool {
+	mt := strings.ToLower(v.MediaType)
+	switch {
+	case a.mediaType == "*/*":
+	case strings.HasSuffix(a.mediaType, "/*"):
+		if !strings.HasPrefix(mt, strings.TrimSuffix(a.mediaType, "*")) {
+			return false
+		}
+	case a.mediaType != mt:
+		return false
+	}
+
+	if p, ok := a.params["profile"]; ok && p != v.Profile {
+		return false
+	}
+
+	if p, ok := a.params["version"]; ok && p != v.Version {
+		return false
+	}
+
+	return true
+}
+
+// parseAccept parses the Accept header into a list of media ranges.
+// Invalid media ranges are skipped.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for i, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+			delete(params, "q")
+		}
+
+		ranges = append(ranges, acceptRange{
+			mediaType: mediaType,
+			params:    params,
+			q:         q,
+			order:     i,
+		})
+	}
+
+	// The most specific ranges are checked first,
+	// so they define the quality of the variant.
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+
+	return ranges
+}
+
+// quality returns the quality of the variant for the media ranges,
+// or zero if the variant isn't acceptable.
+func quality(ranges []acceptRange, v Variant) float64 {
+	for _, a := range ranges {
+		if a.matches(v) {
+			return a.q
+		}
+	}
+	return 0
+}
+
+// Negotiate selects the variant that best matches the Accept header of
+// the request. The variants are listed in the order of server preference
+// that is used when several variants have the same quality. If the Accept
+// header is missing, the first variant is selected.
+//
+// If no variant is acceptable, Negotiate sends the 406 Not Acceptable
+// response and returns false. The response body lists the available
+// variants, and a Link header with rel="alternate" is set for each
+// variant so the client can correct the request.
+//
+// The Accept header is merged into the Vary header (see WithoutAutoVary).
+//
+// Example usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    v, ok := resp.Negotiate(w, r, []resp.Variant{
+//	        {MediaType: resp.MIMEApplicationJSON},
+//	        {MediaType: resp.MIMETextCSV},
+//	    })
+//	    if !ok {
+//	        return // 406 is already sent
+//	    }
+//
+//	    switch v.MediaType {
+//	    case resp.MIMETextCSV:
+//	        resp.CSV(w, rows)
+//	    default:
+//	        resp.JSON(w, data)
+//	    }
+//	}
+func Negotiate(
+	w http.ResponseWriter,
+	r *http.Request,
+	variants []Variant,
+	opts ...Option,
+) (Variant, bool) {
+	return NewResponse(w, opts...).Negotiate(r, variants)
+}
+
+// Negotiate selects the variant that best matches the Accept header of
+// the request. See the Negotiate function for details.
+func (r *Response) Negotiate(
+	req *http.Request,
+	variants []Variant,
+) (Variant, bool) {
+	r.varyOn(HeaderAccept)
+
+	accept := req.Header.Values(HeaderAccept)
+	if len(accept) == 0 && len(variants) > 0 {
+		return variants[0], true
+	}
+
+	ranges := parseAccept(strings.Join(accept, ","))
+	best, bestQ := -1, 0.0
+	for i, v := range variants {
+		if q := quality(ranges, v); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	if best >= 0 {
+		return variants[best], true
+	}
+
+	r.notAcceptable(req, variants)
+	return Variant{}, false
+}
+
+// notAcceptable sends the 406 Not Acceptable response
+// with the list of available variants.
+func (r *Response) notAcceptable(req *http.Request, variants []Variant) {
+	for _, v := range variants {
+		uri := v.URI
+		if uri == "" {
+			uri = req.URL.RequestURI()
+		}
+
+		AddLink(LinkHeader{
+			URI:  uri,
+			Rel:  "alternate",
+			Type: v.MediaType,
+		})(r)
+	}
+
+	if variants == nil {
+		variants = []Variant{}
+	}
+
+	r.SetStatus(StatusNotAcceptable)
+	r.JSON(NotAcceptableResponse{
+		ErrorResponse: *newErrorResponse(StatusNotAcceptable),
+		Variants:      variants,
+	})
+}
@@ -0,0 +1,120 @@
+package resp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testVariants is the list of variants used in tests.
+var testVariants = []Variant{
+	{MediaType: MIMEApplicationJSON},
+	{MediaType: MIMEApplicationJSON, Profile: "compact", Version: "2"},
+	{MediaType: MIMETextCSV, URI: "/report.csv"},
+}
+
+// TestNegotiate tests the Negotiate function.
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   int
+	}{
+		{"no accept", "", 0},
+		{"exact", "text/csv", 2},
+		{"wildcard", "*/*", 0},
+		{"subtype wildcard", "text/*", 2},
+		{"quality", "application/json;q=0.5, text/csv", 2},
+		{"profile", "application/json;profile=compact", 1},
+		{"version", "application/json; version=2", 1},
+		{"excluded", "text/csv;q=0, */*;q=0.1", 0},
+		{"specific over wildcard", "*/*;q=0.1, text/csv;q=0.9", 2},
+		{"invalid skipped", "a b c, text/csv", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/report", nil)
+			if tt.accept != "" {
+				r.Header.Set(HeaderAccept, tt.accept)
+			}
+
+			got, ok := Negotiate(w, r, testVariants)
+			if !ok {
+				t.Fatalf("Negotiate() returned false")
+			}
+
+			if got != testVariants[tt.want] {
+				t.Errorf("Negotiate() = %+v, want %+v",
+					got, testVariants[tt.want])
+			}
+
+			if v := w.Header().Get(HeaderVary); v != HeaderAccept {
+				t.Errorf("Negotiate() Vary = %q", v)
+			}
+		})
+	}
+}
+
+// TestNegotiate_NotAcceptable tests the 406 response of Negotiate.
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/report?x=1", nil)
+	r.Header.Set(HeaderAccept, "application/xml")
+
+	if _, ok := Negotiate(w, r, testVariants); ok {
+		t.Fatal("Negotiate() returned true for unacceptable request")
+	}
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Errorf("Negotiate() status = %d, want %d",
+			w.Code, http.StatusNotAcceptable)
+	}
+
+	links := w.Header().Values(HeaderLink)
+	want := []string{
+		`</report?x=1>; rel="alternate"; type="application/json"`,
+		`</report?x=1>; rel="alternate"; type="application/json"`,
+		`</report.csv>; rel="alternate"; type="text/csv"`,
+	}
+	if len(links) != len(want) {
+		t.Fatalf("Negotiate() Link = %v", links)
+	}
+
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("Negotiate() Link[%d] = %q, want %q",
+				i, links[i], want[i])
+		}
+	}
+
+	var body NotAcceptableResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Negotiate() body is not JSON: %v", err)
+	}
+
+	if body.Code != http.StatusNotAcceptable || len(body.Variants) != 3 {
+		t.Errorf("Negotiate() body = %+v", body)
+	}
+
+	if body.Variants[1].Profile != "compact" {
+		t.Errorf("Negotiate() variant = %+v", body.Variants[1])
+	}
+}
+
+// TestNegotiate_NoVariants tests Negotiate without variants.
+func TestNegotiate_NoVariants(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if _, ok := Negotiate(w, r, nil); ok {
+		t.Error("Negotiate() returned true without variants")
+	}
+
+	want := `{"code":406,"message":"Not Acceptable","variants":[]}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("Negotiate() body = %q, want %q", got, want)
+	}
+}
@@ -3,9 +3,12 @@ package resp
 import (
 	"fmt"
 	"net/http"
-	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"golang.org/x/text/encoding"
 )
 
 // Option represents a response option.
@@ -19,12 +22,69 @@ type WarningHeader struct {
 	Date  time.Time
 }
 
-// LinkHeader represents a Link header.
+// LinkHeader represents a Link header (RFC 8288).
 type LinkHeader struct {
 	URI   string
 	Rel   string
 	Type  string
 	Title string
+
+	// HrefLang is the language of the target, e.g. "en".
+	HrefLang string
+
+	// Media is the media query of the target, e.g. "(min-width: 600px)".
+	Media string
+
+	// As is the destination of the preloaded target,
+	// e.g. "style", "script" or "font".
+	As string
+
+	// CrossOrigin is the CORS mode of the request of the target:
+	// "anonymous" or "use-credentials".
+	CrossOrigin string
+
+	// Anchor is the context of the link, if it isn't the resource.
+	Anchor string
+
+	// Params are other parameters of the link, sent in the order of
+	// the names; the parameter with the empty value is sent without it.
+	Params map[string]string
+}
+
+// String returns the value of the Link header.
+func (l LinkHeader) String() string {
+	var sb strings.Builder
+	sb.WriteString("<" + l.URI + ">; rel=" + quoteString(l.Rel))
+
+	attrs := []struct{ name, value string }{
+		{"type", l.Type},
+		{"title", l.Title},
+		{"hreflang", l.HrefLang},
+		{"media", l.Media},
+		{"as", l.As},
+		{"crossorigin", l.CrossOrigin},
+		{"anchor", l.Anchor},
+	}
+	for _, attr := range attrs {
+		if attr.value != "" {
+			sb.WriteString("; " + attr.name + "=" + quoteString(attr.value))
+		}
+	}
+
+	names := make([]string, 0, len(l.Params))
+	for name := range l.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString("; " + name)
+		if value := l.Params[name]; value != "" {
+			sb.WriteString("=" + quoteString(value))
+		}
+	}
+
+	return sb.String()
 }
 
 // WithHeader adds the provided header key-value pair to the response.
@@ -363,9 +423,19 @@ func AddContentType(value string) Option {
 	return WithHeader(HeaderContentType, value)
 }
 
-// AddETag sets the ETag header.
+// AddETag sets the ETag header. The value is quoted if needed, e.g.
+// `"v1"` for "v1" and `W/"v1"` for "W/v1", so the If-None-Match and
+// If-Match headers of the clients match it. The header isn't set if
+// the value has the characters not allowed in the ETag (the double
+// quotes, the spaces and the control characters). See ETagStrong and
+// ETagWeak for the generated ETags.
 func AddETag(value string) Option {
-	return WithHeader(HeaderETag, value)
+	return func(r *Response) *Response {
+		if etag, ok := normalizeETag(value); ok {
+			r.httpWriter.Header().Set(HeaderETag, etag)
+		}
+		return r
+	}
 }
 
 // AddLastModified sets the Last-Modified header.
@@ -430,28 +500,106 @@ func AddRetryAfter[T int | time.Time | time.Duration](value T) Option {
 	}
 }
 
-// AddContentDisposition sets the Content-Disposition header.
+// AddSunset sets the Sunset header (RFC 8594), the date at which
+// the resource is expected to become unresponsive.
+func AddSunset(t time.Time) Option {
+	return WithHeader(HeaderSunset, t.UTC().Format(http.TimeFormat))
+}
+
+// AddDeprecation sets the Deprecation header (RFC 9745), the date at
+// which the resource was (or will be) deprecated. The date is sent as
+// a structured field date, e.g. "@1688169599".
+func AddDeprecation(t time.Time) Option {
+	return WithStructuredField(HeaderDeprecation, SFItem{Value: t})
+}
+
+// AddPriority sets the Priority header (RFC 9218), the priority of the
+// response for the intermediaries, e.g. "u=5, i". The urgency ranges
+// from 0 (the highest) to 7 (the lowest), the default is 3; the values
+// outside the range are clamped. The incremental response can be used
+// by the client before it is received completely, e.g. a progressive
+// image.
+func AddPriority(urgency int, incremental bool) Option {
+	priority := SFDictionary{{Key: "u", Value: min(max(urgency, 0), 7)}}
+	if incremental {
+		priority = append(priority, SFDictMember{Key: "i", Value: true})
+	}
+
+	return WithStructuredField(HeaderPriority, priority)
+}
+
+// AltSvcEntry is an alternative service of the Alt-Svc header (RFC 7838),
+// e.g. the HTTP/3 endpoint of the origin.
+type AltSvcEntry struct {
+	// Protocol is the ALPN protocol ID, e.g. "h3" or "h2".
+	Protocol string
+
+	// Authority is the host and the port of the alternative service,
+	// e.g. ":443" for the same host or "alt.example.com:443".
+	Authority string
+
+	// MaxAge is the freshness lifetime of the entry;
+	// the client assumes 24 hours if it is zero.
+	MaxAge time.Duration
+
+	// Persist keeps the entry when the client's network changes.
+	Persist bool
+}
+
+// String returns the entry as the member of the Alt-Svc header,
+// e.g. `h3=":443"; ma=86400`.
+func (e AltSvcEntry) String() string {
+	value := e.Protocol + "=" + quoteString(e.Authority)
+	if e.MaxAge > 0 {
+		value += "; ma=" + strconv.FormatInt(int64(e.MaxAge.Seconds()), 10)
+	}
+
+	if e.Persist {
+		value += "; persist=1"
+	}
+
+	return value
+}
+
+// AddAltSvc sets the Alt-Svc header (RFC 7838) that advertises the
+// alternative services of the origin, e.g. the HTTP/3 endpoint of the
+// frontend. Without the entries, the header is set to "clear", which
+// invalidates the alternative services advertised before.
+//
+// Example Usage:
+//
+//	resp.JSON(w, data, resp.AddAltSvc(
+//	    resp.AltSvcEntry{Protocol: "h3", Authority: ":443",
+//	        MaxAge: 24 * time.Hour},
+//	    resp.AltSvcEntry{Protocol: "h2", Authority: ":443"}))
+//	// Alt-Svc: h3=":443"; ma=86400, h2=":443"
+func AddAltSvc(entries ...AltSvcEntry) Option {
+	if len(entries) == 0 {
+		return WithHeader(HeaderAltSvc, "clear")
+	}
+
+	values := make([]string, len(entries))
+	for i, e := range entries {
+		values[i] = e.String()
+	}
+
+	return WithHeader(HeaderAltSvc, strings.Join(values, ", "))
+}
+
+// AddContentDisposition sets the Content-Disposition header (RFC 6266).
+// The path separators of the filename are replaced with underscores and
+// the control characters are removed. If the filename contains non-ASCII
+// characters or quotes, or useUTF8Encoding is true, both the ASCII
+// fallback filename= and the UTF-8 filename*= parameters are sent.
 func AddContentDisposition(
 	dispositionType,
 	filename string,
 	useUTF8Encoding ...bool,
 ) Option {
 	return func(r *Response) *Response {
-		// Check if UTF-8 encoding is needed for the filename.
-		if len(useUTF8Encoding) > 0 && useUTF8Encoding[0] {
-			// Encode the filename using URL encoding.
-			encodedFilename := url.PathEscape(filename)
-			value := fmt.Sprintf(
-				`%s; filename*=UTF-8''%s`,
-				dispositionType,
-				encodedFilename,
-			)
-			return WithHeader(HeaderContentDisposition, value)(r)
-		} else {
-			// Standard encoding.
-			value := fmt.Sprintf(`%s; filename="%s"`, dispositionType, filename)
-			return WithHeader(HeaderContentDisposition, value)(r)
-		}
+		forceUTF8 := len(useUTF8Encoding) > 0 && useUTF8Encoding[0]
+		value := contentDisposition(dispositionType, filename, forceUTF8)
+		return WithHeader(HeaderContentDisposition, value)(r)
 	}
 }
 
@@ -635,6 +783,12 @@ func AddContentRange(start, end, total int) Option {
 	}
 }
 
+// AddTotalCount sets the X-Total-Count header to the total number
+// of items in the paginated collection.
+func AddTotalCount(total int) Option {
+	return WithHeader(HeaderXTotalCount, strconv.Itoa(total))
+}
+
 // AddAcceptRanges sets the Accept-Ranges header.
 func AddAccept(value ...string) Option {
 	return WithHeader(HeaderAccept, value...)
@@ -724,24 +878,68 @@ func AddAccessControlExposeHeaders(value ...string) Option {
 	return WithHeader(HeaderAccessControlExposeHeaders, value...)
 }
 
-// AddLink sets the Link header.
+// AddLink adds a Link header for each link.
+//
+// Example Usage:
+//
+//	resp.HTML(w, page, resp.AddLink(resp.LinkHeader{
+//	    URI:         "/fonts/inter.woff2",
+//	    Rel:         "preload",
+//	    As:          "font",
+//	    Type:        "font/woff2",
+//	    CrossOrigin: "anonymous",
+//	}))
+//	// Link: </fonts/inter.woff2>; rel="preload"; type="font/woff2";
+//	//     as="font"; crossorigin="anonymous"
 func AddLink(links ...LinkHeader) Option {
 	return func(r *Response) *Response {
 		for _, link := range links {
-			linkValue := fmt.Sprintf("<%s>; rel=\"%s\"", link.URI, link.Rel)
-			if link.Type != "" {
-				linkValue += fmt.Sprintf("; type=\"%s\"", link.Type)
-			}
-			if link.Title != "" {
-				linkValue += fmt.Sprintf("; title=\"%s\"", link.Title)
-			}
+			r.httpWriter.Header().Add(HeaderLink, link.String())
+		}
+		return r
+	}
+}
 
-			r.httpWriter.Header().Add(HeaderLink, linkValue)
+// AddLinkList adds the links as one comma-separated Link header,
+// e.g. to keep the number of the header lines small for the
+// preload and preconnect hints of the 103 (Early Hints) response.
+//
+// Example Usage:
+//
+//	resp.AddLinkList(
+//	    resp.LinkHeader{URI: "https://cdn.example.com", Rel: "preconnect"},
+//	    resp.LinkHeader{URI: "/app.js", Rel: "preload", As: "script"})
+//	// Link: <https://cdn.example.com>; rel="preconnect",
+//	//     </app.js>; rel="preload"; as="script"
+func AddLinkList(links ...LinkHeader) Option {
+	return func(r *Response) *Response {
+		if len(links) == 0 {
+			return r
 		}
+
+		values := make([]string, len(links))
+		for i, link := range links {
+			values[i] = link.String()
+		}
+
+		r.httpWriter.Header().Add(HeaderLink, strings.Join(values, ", "))
 		return r
 	}
 }
 
+// AddServiceDesc adds a Link header with the "service-desc" relation
+// (RFC 8631) pointing to a machine-readable description of the API,
+// e.g. an OpenAPI document.
+func AddServiceDesc(url string) Option {
+	return AddLink(LinkHeader{URI: url, Rel: "service-desc"})
+}
+
+// AddServiceDoc adds a Link header with the "service-doc" relation
+// (RFC 8631) pointing to human-readable documentation of the API.
+func AddServiceDoc(url string) Option {
+	return AddLink(LinkHeader{URI: url, Rel: "service-doc"})
+}
+
 // AddAccessControlAllowCredentials sets the
 // Access-Control-Allow-Credentials header.
 func AddAccessControlAllowCredentials(enable bool) Option {
@@ -882,3 +1080,72 @@ func ApplyJSONEncoder(encodeFunc JSONEncodeFunc) Option {
 		return r
 	}
 }
+
+// WithBOM writes the byte order mark before the body of text responses
+// (String, Stream and ServeFileAsDownload). It is useful for CSV files
+// that are opened in Excel, which detects UTF-8 only by the BOM.
+//
+// If the text encoding is set with WithTextEncoding, the byte order mark
+// of that encoding is written. Encodings that can't represent the byte
+// order mark (e.g. single-byte code pages) are written without it.
+//
+// Example Usage:
+//
+//	resp.ServeFileAsDownload(w, "report.csv", data,
+//	    resp.WithBOM(), resp.AddContentType("text/csv; charset=utf-8"))
+func WithBOM() Option {
+	return func(r *Response) *Response {
+		r.bom = true
+		return r
+	}
+}
+
+// WithTextEncoding converts the body of text responses (String, Stream
+// and ServeFileAsDownload) from UTF-8 into the provided encoding while
+// it is written. The Content-Type charset isn't changed automatically,
+// so it should be set to match the encoding.
+//
+// Example Usage:
+//
+//	import "golang.org/x/text/encoding/charmap"
+//
+//	resp.ServeFileAsDownload(w, "report.csv", data,
+//	    resp.WithTextEncoding(charmap.Windows1251),
+//	    resp.AddContentType("text/csv; charset=windows-1251"))
+func WithTextEncoding(enc encoding.Encoding) Option {
+	return func(r *Response) *Response {
+		r.textEncoding = enc
+		return r
+	}
+}
+
+// WithoutAutoVary disables automatic management of the Vary header.
+//
+// By default, whenever the package makes a decision based on a request
+// header (e.g. Origin in CORS), the name of that header is merged into
+// the Vary header, so caches don't serve a response to clients it
+// wasn't made for. Use this option only if the Vary header is managed
+// elsewhere (e.g. by a reverse proxy). The option also applies to the
+// Accept-Encoding header merged by the Compress middleware.
+func WithoutAutoVary() Option {
+	return func(r *Response) *Response {
+		r.noAutoVary = true
+		if cw := findCompressWriter(r.httpWriter); cw != nil {
+			cw.noAutoVary = true
+		}
+		return r
+	}
+}
+
+// WithLocaleFormatter sets the formatter used to convert values
+// to text in tabular exports (see the CSV function).
+//
+// Example Usage:
+//
+//	resp.CSV(w, rows, resp.WithLocaleFormatter(resp.LocaleUkUA))
+func WithLocaleFormatter(f LocaleFormatter) Option {
+	return func(r *Response) *Response {
+		r.localeFormatter = &f
+		return r
+	}
+}
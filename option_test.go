@@ -1032,9 +1032,36 @@ func TestAddETag(t *testing.T) {
 	resp.httpWriter.WriteHeader(resp.statusCode)
 
 	eTag := w.Header().Get("ETag")
-	if eTag != "123456" {
+	if eTag != `"123456"` {
 		t.Errorf("AddETag() did not set the correct ETag header: "+
-			"got %v, want %v", eTag, "123456")
+			"got %v, want %v", eTag, `"123456"`)
+	}
+}
+
+// TestAddETag_Normalize tests that AddETag quotes
+// the values and skips the invalid ones.
+func TestAddETag_Normalize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`"v1"`, `"v1"`},
+		{"v1", `"v1"`},
+		{`W/"v1"`, `W/"v1"`},
+		{"W/v1", `W/"v1"`},
+		{` "v1" `, `"v1"`},
+		{`""`, `""`},
+		{"v 1", ""},
+		{`"v"1"`, ""},
+		{"v\r\n1", ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		NewResponse(w, AddETag(tt.value))
+		if got := w.Header().Get(HeaderETag); got != tt.want {
+			t.Errorf("AddETag(%q) = %q, want %q", tt.value, got, tt.want)
+		}
 	}
 }
 
@@ -1236,7 +1263,8 @@ func TestAddContentDisposition_UTF8(t *testing.T) {
 
 	resp.httpWriter.WriteHeader(resp.statusCode)
 
-	want := `attachment; filename*=UTF-8''%E3%83%AD%E3%82%B7%E3%82%A2%E4%BA` +
+	want := `attachment; filename="___________.txt"; ` +
+		`filename*=UTF-8''%E3%83%AD%E3%82%B7%E3%82%A2%E4%BA` +
 		`%BA%E3%81%AF%E3%83%86%E3%83%AD%E3%83%AA%E3%82%B9%E3%83%88%E3%81%A0.txt`
 	contentDisposition := w.Header().Get("Content-Disposition")
 	if contentDisposition != want {
@@ -2007,6 +2035,69 @@ func TestAddLinkWithTitle(t *testing.T) {
 	}
 }
 
+// TestAddLinkWithAttributes tests the AddLink function
+// with the extended attributes.
+func TestAddLinkWithAttributes(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, AddLink(LinkHeader{
+		URI:         "/fonts/inter.woff2",
+		Rel:         "preload",
+		Type:        "font/woff2",
+		HrefLang:    "en",
+		Media:       "(min-width: 600px)",
+		As:          "font",
+		CrossOrigin: "anonymous",
+		Anchor:      "#main",
+		Params:      map[string]string{"nopush": "", "fetchpriority": "high"},
+	}))
+
+	want := `</fonts/inter.woff2>; rel="preload"; type="font/woff2"; ` +
+		`hreflang="en"; media="(min-width: 600px)"; as="font"; ` +
+		`crossorigin="anonymous"; anchor="#main"; ` +
+		`fetchpriority="high"; nopush`
+	if got := w.Header().Get(HeaderLink); got != want {
+		t.Errorf("AddLink() = %v, want %v", got, want)
+	}
+}
+
+// TestAddLinkList tests the AddLinkList function.
+func TestAddLinkList(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w,
+		AddLinkList(
+			LinkHeader{URI: "https://cdn.example.com", Rel: "preconnect"},
+			LinkHeader{URI: "/app.js", Rel: "preload", As: "script"}),
+		AddLinkList())
+
+	want := []string{`<https://cdn.example.com>; rel="preconnect", ` +
+		`</app.js>; rel="preload"; as="script"`}
+	if got := w.Header().Values(HeaderLink); !reflect.DeepEqual(got, want) {
+		t.Errorf("AddLinkList() = %q, want %q", got, want)
+	}
+}
+
+// TestAddServiceDesc tests the AddServiceDesc function.
+func TestAddServiceDesc(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, AddServiceDesc("/openapi.json"))
+
+	want := `</openapi.json>; rel="service-desc"`
+	if got := w.Header().Get(HeaderLink); got != want {
+		t.Errorf("AddServiceDesc() = %v, want %v", got, want)
+	}
+}
+
+// TestAddServiceDoc tests the AddServiceDoc function.
+func TestAddServiceDoc(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, AddServiceDoc("https://example.com/docs"))
+
+	want := `<https://example.com/docs>; rel="service-doc"`
+	if got := w.Header().Get(HeaderLink); got != want {
+		t.Errorf("AddServiceDoc() = %v, want %v", got, want)
+	}
+}
+
 // TestAddAccessControlAllowCredentials tests the
 // AddAccessControlAllowCredentials function.
 func TestAddAccessControlAllowCredentials(t *testing.T) {
@@ -2353,3 +2444,86 @@ func TestAsApplicationJavaScriptCharsetUTF8(t *testing.T) {
 			contentType, want)
 	}
 }
+
+// TestAddSunset tests the AddSunset function.
+func TestAddSunset(t *testing.T) {
+	w := httptest.NewRecorder()
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	NewResponse(w, AddSunset(time.Date(2025, 6, 1, 2, 0, 0, 0, loc)))
+
+	want := "Sun, 01 Jun 2025 00:00:00 GMT"
+	if got := w.Header().Get(HeaderSunset); got != want {
+		t.Errorf("AddSunset() = %v, want %v", got, want)
+	}
+}
+
+// TestAddDeprecation tests the AddDeprecation function.
+func TestAddDeprecation(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, AddDeprecation(time.Unix(1688169599, 0)))
+
+	if got := w.Header().Get(HeaderDeprecation); got != "@1688169599" {
+		t.Errorf("AddDeprecation() = %v, want @1688169599", got)
+	}
+}
+
+// TestAddPriority tests the AddPriority function.
+func TestAddPriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		urgency     int
+		incremental bool
+		want        string
+	}{
+		{"Default", 3, false, "u=3"},
+		{"Incremental", 5, true, "u=5, i"},
+		{"Highest", 0, false, "u=0"},
+		{"Below range", -1, false, "u=0"},
+		{"Above range", 10, true, "u=7, i"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewResponse(w, AddPriority(tt.urgency, tt.incremental))
+
+			if got := w.Header().Get(HeaderPriority); got != tt.want {
+				t.Errorf("AddPriority() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAddAltSvc tests the AddAltSvc function.
+func TestAddAltSvc(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []AltSvcEntry
+		want    string
+	}{
+		{
+			name: "HTTP/3",
+			entries: []AltSvcEntry{
+				{Protocol: "h3", Authority: ":443", MaxAge: 24 * time.Hour},
+				{Protocol: "h2", Authority: "alt.example.com:8443",
+					Persist: true},
+			},
+			want: `h3=":443"; ma=86400, h2="alt.example.com:8443"; persist=1`,
+		},
+		{
+			name: "Clear",
+			want: "clear",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewResponse(w, AddAltSvc(tt.entries...))
+
+			if got := w.Header().Get(HeaderAltSvc); got != tt.want {
+				t.Errorf("AddAltSvc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
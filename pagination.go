@@ -0,0 +1,188 @@
+package resp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageMeta is the pagination metadata of the Paginated envelope.
+type PageMeta struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// PageEnvelope is the JSON body sent by Paginated.
+type PageEnvelope struct {
+	Data any      `json:"data"`
+	Meta PageMeta `json:"meta"`
+}
+
+// NewPageMeta returns the pagination metadata; the number of pages
+// is calculated from the total number of items and the page size.
+func NewPageMeta(page, perPage, total int) PageMeta {
+	meta := PageMeta{Page: page, PerPage: perPage, Total: total}
+	if perPage > 0 && total > 0 {
+		meta.TotalPages = (total + perPage - 1) / perPage
+	}
+
+	return meta
+}
+
+// Paginated sends the page of the collection as a JSON response in
+// the standard envelope:
+//
+//	{"data": [...], "meta": {"page": 2, "per_page": 20, "total": 95,
+//	"total_pages": 5}}
+//
+// Use the AddTotalCount option to send the X-Total-Count header too.
+// The metadata set by SetGlobalMeta and WithMeta is merged into the
+// meta block.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    items, total := store.List(page, perPage)
+//	    resp.Paginated(w, items, page, perPage, total,
+//	        resp.AddTotalCount(total))
+//	}
+func Paginated(
+	w http.ResponseWriter,
+	items any,
+	page, perPage, total int,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).Paginated(items, page, perPage, total)
+}
+
+// Paginated sends the page of the collection as a JSON response
+// in the standard envelope. See the Paginated function for details.
+func (r *Response) Paginated(items any, page, perPage, total int) error {
+	return r.JSON(PageEnvelope{
+		Data: items,
+		Meta: NewPageMeta(page, perPage, total),
+	})
+}
+
+// WithPaginationLinks adds the RFC 8288 Link headers of the pagination
+// relations: "first" and "last", and "prev" and "next" when the page
+// has neighbours. The links are built from the base URL with the page
+// and per_page query parameters set; other query parameters of the base
+// URL are kept. Pages are numbered from 1. No links are added if the
+// base URL can't be parsed.
+//
+// Example Usage:
+//
+//	resp.Paginated(w, items, page, perPage, total,
+//	    resp.WithPaginationLinks(r.URL.String(), page, perPage, total))
+//	// Link: </users?page=3&per_page=20>; rel="next", ...
+func WithPaginationLinks(base string, page, perPage, total int) Option {
+	return func(r *Response) *Response {
+		u, err := url.Parse(base)
+		if err != nil {
+			return r
+		}
+
+		last := NewPageMeta(page, perPage, total).TotalPages
+		if last < 1 {
+			last = 1
+		}
+
+		pageURL := func(n int) string {
+			query := u.Query()
+			query.Set("page", strconv.Itoa(n))
+			query.Set("per_page", strconv.Itoa(perPage))
+
+			link := *u
+			link.RawQuery = query.Encode()
+			return link.String()
+		}
+
+		links := []LinkHeader{{URI: pageURL(1), Rel: "first"}}
+		if page > 1 {
+			prev := page - 1
+			if prev > last {
+				prev = last
+			}
+			links = append(links,
+				LinkHeader{URI: pageURL(prev), Rel: "prev"})
+		}
+
+		if page < last {
+			links = append(links,
+				LinkHeader{URI: pageURL(page + 1), Rel: "next"})
+		}
+
+		links = append(links, LinkHeader{URI: pageURL(last), Rel: "last"})
+		return AddLink(links...)(r)
+	}
+}
+
+// CollectionRangeUnit is the range unit of the collection ranges.
+const CollectionRangeUnit = "items"
+
+// WithCollectionRange sets the Content-Range header of the part of the
+// collection sent in the response, e.g. "items 0-24/100", and the
+// Accept-Ranges header to "items". The status code is set to 206
+// (Partial Content) unless the range covers the whole collection or
+// the status code is already set. Use a negative total if the size
+// of the collection is unknown.
+//
+// Example Usage:
+//
+//	// GET /users with the "Range: items=0-24" header.
+//	start, end, ok := resp.CollectionRange(r)
+//	...
+//	resp.JSON(w, users, resp.WithCollectionRange(start, end, total))
+func WithCollectionRange(start, end, total int) Option {
+	return func(r *Response) *Response {
+		size := "*"
+		if total >= 0 {
+			size = strconv.Itoa(total)
+		}
+
+		r.httpWriter.Header().Set(HeaderContentRange, fmt.Sprintf(
+			"%s %d-%d/%s", CollectionRangeUnit, start, end, size))
+		r.httpWriter.Header().Set(HeaderAcceptRanges, CollectionRangeUnit)
+
+		whole := start == 0 && total >= 0 && end >= total-1
+		if r.statusCode == StatusUndefined && !whole {
+			r.statusCode = StatusPartialContent
+		}
+
+		return r
+	}
+}
+
+// CollectionRange returns the first and the last (inclusive) positions
+// of the items requested in the Range header of the request, e.g.
+// "Range: items=0-24". The ok is false if there is no such header or
+// it isn't a single valid items range.
+func CollectionRange(r *http.Request) (start, end int, ok bool) {
+	value := r.Header.Get(HeaderRange)
+	spec, found := strings.CutPrefix(value, CollectionRangeUnit+"=")
+	if !found {
+		return 0, 0, false
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+
+	end, err = strconv.Atoi(last)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
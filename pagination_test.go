@@ -0,0 +1,225 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNewPageMeta tests the calculation of the number of pages.
+func TestNewPageMeta(t *testing.T) {
+	tests := []struct {
+		perPage, total, want int
+	}{
+		{perPage: 20, total: 95, want: 5},
+		{perPage: 20, total: 100, want: 5},
+		{perPage: 20, total: 0, want: 0},
+		{perPage: 0, total: 10, want: 0},
+	}
+
+	for _, tt := range tests {
+		meta := NewPageMeta(1, tt.perPage, tt.total)
+		if meta.TotalPages != tt.want {
+			t.Errorf("NewPageMeta(1, %d, %d).TotalPages = %d, want %d",
+				tt.perPage, tt.total, meta.TotalPages, tt.want)
+		}
+	}
+}
+
+// TestPaginated tests the Paginated function.
+func TestPaginated(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := Paginated(w, []int{1, 2}, 2, 2, 5, AddTotalCount(5))
+	if err != nil {
+		t.Fatalf("Paginated() error = %v", err)
+	}
+
+	want := `{"data":[1,2],"meta":{"page":2,"per_page":2,"total":5,` +
+		`"total_pages":3}}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("Paginated() body = %s, want %s", got, want)
+	}
+
+	if got := w.Header().Get(HeaderXTotalCount); got != "5" {
+		t.Errorf("Paginated() X-Total-Count = %q, want %q", got, "5")
+	}
+
+	if got := w.Header().Get(HeaderContentType); got !=
+		MIMEApplicationJSONCharsetUTF8 {
+		t.Errorf("Paginated() Content-Type = %q", got)
+	}
+}
+
+// TestPaginatedWithMeta tests that the metadata is merged
+// into the meta block of the envelope.
+func TestPaginatedWithMeta(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := Paginated(w, []string{}, 1, 10, 0, WithMeta("region", "eu"))
+	if err != nil {
+		t.Fatalf("Paginated() error = %v", err)
+	}
+
+	want := `{"data":[],"meta":{"page":1,"per_page":10,"region":"eu",` +
+		`"total":0,"total_pages":0}}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("Paginated() body = %s, want %s", got, want)
+	}
+}
+
+// TestWithPaginationLinks tests the Link headers of the pagination.
+func TestWithPaginationLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		page int
+		want []string
+	}{
+		{
+			name: "First page",
+			page: 1,
+			want: []string{
+				`</users?page=1&per_page=2&sort=id>; rel="first"`,
+				`</users?page=2&per_page=2&sort=id>; rel="next"`,
+				`</users?page=3&per_page=2&sort=id>; rel="last"`,
+			},
+		},
+		{
+			name: "Middle page",
+			page: 2,
+			want: []string{
+				`</users?page=1&per_page=2&sort=id>; rel="first"`,
+				`</users?page=1&per_page=2&sort=id>; rel="prev"`,
+				`</users?page=3&per_page=2&sort=id>; rel="next"`,
+				`</users?page=3&per_page=2&sort=id>; rel="last"`,
+			},
+		},
+		{
+			name: "Out of range",
+			page: 7,
+			want: []string{
+				`</users?page=1&per_page=2&sort=id>; rel="first"`,
+				`</users?page=3&per_page=2&sort=id>; rel="prev"`,
+				`</users?page=3&per_page=2&sort=id>; rel="last"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NoContent(w, WithPaginationLinks(
+				"/users?sort=id&page=9", tt.page, 2, 5))
+
+			got := w.Header().Values(HeaderLink)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Link = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Link[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestWithPaginationLinksInvalidURL tests that no links
+// are added for an invalid base URL.
+func TestWithPaginationLinksInvalidURL(t *testing.T) {
+	w := httptest.NewRecorder()
+	NoContent(w, WithPaginationLinks("%zz", 1, 10, 100))
+
+	if got := w.Header().Values(HeaderLink); len(got) != 0 {
+		t.Errorf("Link = %v, want none", got)
+	}
+}
+
+// TestWithCollectionRange tests the collection range headers.
+func TestWithCollectionRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end int
+		total      int
+		opts       []Option
+		wantRange  string
+		wantStatus int
+	}{
+		{
+			name:  "Partial",
+			start: 0, end: 24, total: 100,
+			wantRange:  "items 0-24/100",
+			wantStatus: StatusPartialContent,
+		},
+		{
+			name:  "Whole collection",
+			start: 0, end: 9, total: 10,
+			wantRange:  "items 0-9/10",
+			wantStatus: StatusOK,
+		},
+		{
+			name:  "Unknown total",
+			start: 0, end: 9, total: -1,
+			wantRange:  "items 0-9/*",
+			wantStatus: StatusPartialContent,
+		},
+		{
+			name:  "Explicit status",
+			start: 10, end: 19, total: 100,
+			opts:       []Option{WithStatus(StatusAccepted)},
+			wantRange:  "items 10-19/100",
+			wantStatus: StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			opts := append(tt.opts,
+				WithCollectionRange(tt.start, tt.end, tt.total))
+			if err := JSON(w, []int{}, opts...); err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+
+			if got := w.Header().Get(HeaderContentRange); got != tt.wantRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantRange)
+			}
+
+			if got := w.Header().Get(HeaderAcceptRanges); got != "items" {
+				t.Errorf("Accept-Ranges = %q, want %q", got, "items")
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestCollectionRange tests the parsing of the Range header.
+func TestCollectionRange(t *testing.T) {
+	tests := []struct {
+		header     string
+		start, end int
+		ok         bool
+	}{
+		{header: "items=0-24", start: 0, end: 24, ok: true},
+		{header: "items= 5-5", start: 5, end: 5, ok: true},
+		{header: "bytes=0-24"},
+		{header: "items=10-5"},
+		{header: "items=-5"},
+		{header: "items=0-"},
+		{header: ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		if tt.header != "" {
+			r.Header.Set(HeaderRange, tt.header)
+		}
+
+		start, end, ok := CollectionRange(r)
+		if start != tt.start || end != tt.end || ok != tt.ok {
+			t.Errorf("CollectionRange(%q) = %d, %d, %v", tt.header,
+				start, end, ok)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package resp
+
+import "reflect"
+
+// pickPlan is the list of the fields selected by Pick, resolved once
+// for the struct type.
+type pickPlan struct {
+	ptr    bool // the type is a pointer to the struct
+	fields []fieldInfo
+}
+
+// newPickPlan resolves the fields of the type (a struct or a pointer
+// to a struct); the unknown fields are ignored. It returns nil if the
+// type isn't a struct.
+func newPickPlan(rt reflect.Type, fields []string) *pickPlan {
+	plan := &pickPlan{}
+	if rt.Kind() == reflect.Ptr {
+		plan.ptr = true
+		rt = rt.Elem()
+	}
+
+	if rt.Kind() != reflect.Struct {
+		return nil
+	}
+
+	info := cachedStructInfo(rt)
+	for _, name := range fields {
+		if i, ok := info.byName[name]; ok {
+			field := info.fields[i]
+			field.path = []int{field.index}
+			plan.fields = append(plan.fields, field)
+		} else if i, ok := info.flatByName[name]; ok {
+			plan.fields = append(plan.fields, info.flat[i])
+		}
+	}
+
+	return plan
+}
+
+// pick returns the selected fields of the value as an `R` map,
+// or nil if the value is a nil pointer.
+func (p *pickPlan) pick(rv reflect.Value) R {
+	if p.ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	result := make(R, len(p.fields))
+	for _, field := range p.fields {
+		if value, ok := fieldByPath(rv, field.path); ok {
+			result[field.name] = value.Interface()
+		}
+	}
+
+	return result
+}
+
+// Pick extracts only the specified fields from the struct (or the
+// pointer to the struct) and returns them as an `R` map. It is the
+// typed version of OnlyFields: the type of the input is known at
+// compile time, so the fields are resolved without inspecting the
+// dynamic type of the value. It returns nil if T isn't a struct or
+// a pointer to a struct, or if the pointer is nil.
+//
+// Example Usage:
+//
+//	data := resp.Pick(user, "ID", "Email")
+//	// {"ID": 1, "Email": "user_a@example.com"}
+func Pick[T any](v T, fields ...string) R {
+	rv := reflect.ValueOf(&v).Elem()
+	plan := newPickPlan(rv.Type(), fields)
+	if plan == nil {
+		return nil
+	}
+
+	return plan.pick(rv)
+}
+
+// PickSlice extracts only the specified fields from each element of the
+// slice of structs (or pointers to structs) and returns a slice of `R`
+// maps. The fields are resolved once for the whole slice, so it is
+// faster than OnlyFields on large slices. It returns nil if T isn't a
+// struct or a pointer to a struct; nil pointers result in nil maps.
+//
+// Example Usage:
+//
+//	data := resp.PickSlice(users, "ID", "Email")
+//	// [{"ID": 1, "Email": "user_a@example.com"}, ...]
+func PickSlice[T any](s []T, fields ...string) []R {
+	plan := newPickPlan(reflect.TypeOf((*T)(nil)).Elem(), fields)
+	if plan == nil {
+		return nil
+	}
+
+	result := make([]R, len(s))
+	for i := range s {
+		result[i] = plan.pick(reflect.ValueOf(&s[i]).Elem())
+	}
+
+	return result
+}
@@ -0,0 +1,63 @@
+package resp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPick tests the Pick function.
+func TestPick(t *testing.T) {
+	user := User{ID: 1, Email: "user@example.com", Password: "secret"}
+	expected := R{"ID": 1, "Email": "user@example.com"}
+
+	if result := Pick(user, "ID", "Email", "Missing"); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("Pick() = %v, want %v", result, expected)
+	}
+
+	if result := Pick(&user, "ID", "Email"); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("Pick() pointer = %v, want %v", result, expected)
+	}
+
+	if result := Pick((*User)(nil), "ID"); result != nil {
+		t.Errorf("Pick() nil pointer = %v, want nil", result)
+	}
+
+	if result := Pick(42, "ID"); result != nil {
+		t.Errorf("Pick() non-struct = %v, want nil", result)
+	}
+
+	account := EmbeddedAccount{
+		ID:              1,
+		EmbeddedProfile: EmbeddedProfile{Email: "a@example.com"},
+	}
+	expected = R{"ID": 1, "Email": "a@example.com"}
+	if result := Pick(account, "ID", "Email"); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("Pick() promoted = %v, want %v", result, expected)
+	}
+}
+
+// TestPickSlice tests the PickSlice function.
+func TestPickSlice(t *testing.T) {
+	users := []*User{
+		{ID: 1, Email: "a@example.com", Password: "secret"},
+		nil,
+		{ID: 2, Email: "b@example.com", Password: "secret"},
+	}
+	expected := []R{{"ID": 1}, nil, {"ID": 2}}
+
+	if result := PickSlice(users, "ID"); !reflect.DeepEqual(
+		result, expected) {
+		t.Errorf("PickSlice() = %v, want %v", result, expected)
+	}
+
+	if result := PickSlice([]string{"a"}, "ID"); result != nil {
+		t.Errorf("PickSlice() non-struct = %v, want nil", result)
+	}
+
+	if result := PickSlice([]User{}, "ID"); len(result) != 0 {
+		t.Errorf("PickSlice() empty = %v", result)
+	}
+}
@@ -0,0 +1,230 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"sync"
+)
+
+// RendererConfig controls the parsing of the templates of a Renderer.
+type RendererConfig struct {
+	// Funcs are the functions available in the templates.
+	Funcs template.FuncMap
+
+	// Reload re-parses the templates from the file system on every
+	// render (development mode), so the changes of the templates are
+	// visible without a restart. By default the templates are parsed
+	// once and cached (production mode).
+	Reload bool
+
+	// Stream executes the templates directly to the response instead of
+	// a buffer (buffered mode), improving the time to the first byte of
+	// the large pages: the output is buffered until the end of the
+	// <head> section, which is sent and flushed at once (also through
+	// the Compress middleware), so the browser loads the styles and the
+	// scripts while the rest of the page is rendered. If the template
+	// fails before the <head> section is sent, nothing is written, like
+	// in the buffered mode; after that the page is truncated. The HTML
+	// isn't minified in this mode.
+	Stream bool
+}
+
+// Renderer renders the HTML templates of a file system (e.g. embed.FS,
+// or os.DirFS for the development mode). It is safe for concurrent use.
+//
+// Example Usage:
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	var renderer = must(resp.NewRenderer(templates,
+//	    []string{"templates/*.html"},
+//	    resp.RendererConfig{Reload: os.Getenv("DEV") != ""}))
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    renderer.Render(w, "index.html", page)
+//	}
+type Renderer struct {
+	fsys     fs.FS
+	patterns []string
+	config   RendererConfig
+
+	mu   sync.RWMutex
+	tmpl *template.Template
+}
+
+// NewRenderer parses the templates of the file system matching the
+// patterns (see template.ParseFS) and returns the Renderer. The error
+// is returned if the templates can't be parsed, also in the
+// development mode (see RendererConfig.Reload), so the broken
+// templates are reported at the startup.
+func NewRenderer(
+	fsys fs.FS,
+	patterns []string,
+	config ...RendererConfig,
+) (*Renderer, error) {
+	rd := &Renderer{fsys: fsys, patterns: patterns}
+	if len(config) > 0 {
+		rd.config = config[0]
+	}
+
+	tmpl, err := rd.parse()
+	if err != nil {
+		return nil, err
+	}
+	rd.tmpl = tmpl
+
+	return rd, nil
+}
+
+// Template returns the parsed templates: the cached ones in the
+// production mode, or the freshly parsed ones in the development mode.
+func (rd *Renderer) Template() (*template.Template, error) {
+	if !rd.config.Reload {
+		rd.mu.RLock()
+		defer rd.mu.RUnlock()
+		return rd.tmpl, nil
+	}
+
+	tmpl, err := rd.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	rd.mu.Lock()
+	rd.tmpl = tmpl
+	rd.mu.Unlock()
+
+	return tmpl, nil
+}
+
+// Render executes the named template with the data and sends the
+// result as an HTML response. The template is executed into a buffer,
+// so nothing is written if it fails and the error can still be sent
+// (see RendererConfig.Stream for the streaming mode).
+func (rd *Renderer) Render(
+	w http.ResponseWriter,
+	name string,
+	data any,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).Render(rd, name, data)
+}
+
+// parse parses the templates of the file system.
+func (rd *Renderer) parse() (*template.Template, error) {
+	tmpl := template.New("")
+	if rd.config.Funcs != nil {
+		tmpl = tmpl.Funcs(rd.config.Funcs)
+	}
+
+	return tmpl.ParseFS(rd.fsys, rd.patterns...)
+}
+
+// Render executes the named template of the renderer with the data
+// and sends the result as an HTML response.
+// See the Renderer.Render method for details.
+func (r *Response) Render(rd *Renderer, name string, data any) error {
+	tmpl, err := rd.Template()
+	if err != nil {
+		return err
+	}
+
+	if rd.config.Stream {
+		return r.streamTemplate(tmpl, name, data)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
+	return r.HTML(buf.String())
+}
+
+// streamTemplate executes the named template directly
+// to the response, see RendererConfig.Stream.
+func (r *Response) streamTemplate(
+	tmpl *template.Template,
+	name string,
+	data any,
+) (err error) {
+	sw := &templateWriter{r: r}
+	err = tmpl.ExecuteTemplate(sw, name, data)
+	if err != nil && sw.body == nil {
+		// Nothing is written, so the error can still be sent.
+		return err
+	}
+
+	defer r.finish(&err)
+
+	// The page without the <head> section is sent at once.
+	if sw.body == nil {
+		return sw.start(false)
+	}
+
+	if ferr := sw.body.Flush(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
+
+// headEnd is the end tag of the <head> section.
+var headEnd = []byte("</head>")
+
+// templateWriter is the writer of the streamed template. It buffers
+// the output until the end of the <head> section, then sends it with
+// the headers and writes the rest of the page through the buffer.
+type templateWriter struct {
+	r    *Response
+	head bytes.Buffer
+	body *bufio.Writer
+}
+
+// Write buffers the data, sending the <head>
+// section as soon as it is complete.
+func (w *templateWriter) Write(p []byte) (int, error) {
+	if w.body != nil {
+		return w.body.Write(p)
+	}
+
+	// The end tag may be split between the writes.
+	from := max(w.head.Len()-len(headEnd)+1, 0)
+	w.head.Write(p)
+	tail := bytes.ToLower(w.head.Bytes()[from:])
+	if !bytes.Contains(tail, headEnd) {
+		return len(p), nil
+	}
+
+	if err := w.start(true); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// start sends the headers and the buffered output,
+// and flushes them if needed.
+func (w *templateWriter) start(flush bool) error {
+	w.body = bufio.NewWriter(w.r.body())
+
+	w.r.prepare(http.StatusOK, MIMETextHTMLCharsetUTF8)
+	if !flush {
+		w.r.setBodyDigest(w.head.Bytes())
+	}
+	w.r.writeHeader(w.r.statusCode)
+
+	if _, err := w.r.write(w.head.Bytes()); err != nil {
+		return err
+	}
+
+	if flush {
+		w.r.flush()
+	}
+
+	return nil
+}
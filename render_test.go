@@ -0,0 +1,154 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+// TestRenderer tests the production and development modes of the
+// Renderer.
+func TestRenderer(t *testing.T) {
+	tests := []struct {
+		name   string
+		reload bool
+		want   string
+	}{
+		{name: "Production", reload: false, want: "<p>Hello, &lt;Go&gt;</p>"},
+		{name: "Development", reload: true, want: "<h1>Hello, &lt;Go&gt;</h1>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"views/index.html": {Data: []byte(
+					`<p>{{ greet . }}</p>`)},
+			}
+
+			rd, err := NewRenderer(fsys, []string{"views/*.html"},
+				RendererConfig{
+					Funcs:  map[string]any{"greet": greet},
+					Reload: tt.reload,
+				})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+
+			// The template changes after the start.
+			fsys["views/index.html"] = &fstest.MapFile{Data: []byte(
+				`<h1>{{ greet . }}</h1>`)}
+
+			w := httptest.NewRecorder()
+			if err := rd.Render(w, "index.html", "<Go>"); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("Render() body = %q, want %q", got, tt.want)
+			}
+
+			want := MIMETextHTMLCharsetUTF8
+			if got := w.Header().Get(HeaderContentType); got != want {
+				t.Errorf("Content-Type = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestRendererErrors tests the parse and execution errors.
+func TestRendererErrors(t *testing.T) {
+	fsys := fstest.MapFS{"bad.html": {Data: []byte(`{{ .Missing `)}}
+	if _, err := NewRenderer(fsys, []string{"*.html"}); err == nil {
+		t.Error("NewRenderer() error = nil, want parse error")
+	}
+
+	fsys = fstest.MapFS{"a.html": {Data: []byte(`{{ .Missing }}`)}}
+	rd, err := NewRenderer(fsys, []string{"*.html"})
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := rd.Render(w, "a.html", 1); err == nil {
+		t.Error("Render() error = nil, want execution error")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Render() body = %q, want empty", w.Body.String())
+	}
+}
+
+// TestRendererStream tests the streaming mode of the Renderer.
+func TestRendererStream(t *testing.T) {
+	const head = `<html><HEAD><title>{{ .Title }}</title></HEAD>`
+
+	tests := []struct {
+		name        string
+		page        string
+		wantErr     bool
+		wantBody    string
+		wantFlushes int
+	}{
+		{
+			name: "Head",
+			page: head + `<body>{{ .Body }}</body></html>`,
+			wantBody: `<html><HEAD><title>T</title></HEAD>` +
+				`<body>B</body></html>`,
+			wantFlushes: 1,
+		},
+		{
+			name:     "No head",
+			page:     `<p>{{ .Body }}</p>`,
+			wantBody: `<p>B</p>`,
+		},
+		{
+			name:    "Error before head",
+			page:    `<html><head>{{ .Missing }}</head></html>`,
+			wantErr: true,
+		},
+		{
+			name:        "Error after head",
+			page:        head + `<body>{{ .Missing }}</body></html>`,
+			wantErr:     true,
+			wantBody:    `<html><HEAD><title>T</title></HEAD><body>`,
+			wantFlushes: 1,
+		},
+	}
+
+	data := struct{ Title, Body string }{"T", "B"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"page.html": {Data: []byte(tt.page)}}
+			rd, err := NewRenderer(fsys, []string{"*.html"},
+				RendererConfig{Stream: true})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+
+			w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+			err = rd.Render(w, "page.html", data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+
+			if w.flushes != tt.wantFlushes {
+				t.Errorf("flushes = %d, want %d", w.flushes, tt.wantFlushes)
+			}
+
+			// Nothing is sent if the template fails before the head.
+			contentType := w.Header().Get(HeaderContentType)
+			if (contentType != "") != (tt.wantBody != "") {
+				t.Errorf("Content-Type = %q, want set %v",
+					contentType, tt.wantBody != "")
+			}
+		})
+	}
+}
+
+// greet is the template function of the tests.
+func greet(name string) string {
+	return "Hello, " + name
+}
@@ -142,8 +142,9 @@ func JSONP(
 //
 // Parameters:
 //   - w: The http.ResponseWriter that the text response will be written to.
-//   - data:    The string data to be sent as the response body. This can be
-//     any text data, including HTML or plain text.
+//   - data:    The data to be sent as the response body: a string, a byte
+//     slice, an error, a fmt.Stringer, an encoding.TextMarshaler, or any
+//     other value that is formatted by fmt.Sprint.
 //   - opts...: Optional configurations applied to the response. These can be
 //     used to set custom headers, status codes, or other response
 //     settings, including changing the Content-Type from its default
@@ -162,11 +163,25 @@ func JSONP(
 //	        // Handle error...
 //	    }
 //	}
-func String(w http.ResponseWriter, data string, opts ...Option) error {
+func String(w http.ResponseWriter, data any, opts ...Option) error {
 	response := NewResponse(w, opts...)
 	return response.String(data)
 }
 
+// Stringf formats the text according to the format specifier (see
+// fmt.Sprintf) and sends it as a plain text response. Since the options
+// can't follow the variadic arguments, use Response.Stringf to set them.
+//
+// Example usage:
+//
+//	resp.Stringf(w, "Hello, %s! You have %d new messages.", name, n)
+//
+//	resp.NewResponse(w, resp.WithStatusAccepted()).
+//	    Stringf("Job %d is queued", id)
+func Stringf(w http.ResponseWriter, format string, args ...any) error {
+	return NewResponse(w).Stringf(format, args...)
+}
+
 // Error sends an error response with a specified HTTP status code and
 // error message.
 //
@@ -174,15 +189,17 @@ func String(w http.ResponseWriter, data string, opts ...Option) error {
 // meaningful status codes and messages. It allows for flexible error
 // reporting by accepting an optional message parameter, making it suitable
 // for endpoints that need to provide more context about an error.
-// The Content-Type of the response is set to "text/plain" by default,
-// but this can be adjusted using options.
+// The error is sent as JSON by default; it can be sent as plain text or
+// as the HTML error page with WithErrorFormat, WithErrorTemplate and
+// WithErrorNegotiation.
 //
 // Parameters:
 //   - w: The http.ResponseWriter to which the error response will be written.
 //   - code: Custom error code.
 //   - message: The error message to be sent in the response body. This can
-//     provide additional context about the error. If no message is provided,
-//     a default message based on the status code will be used.
+//     provide additional context about the error. It is a string, or an
+//     error whose message is used (see Err), or any other value converted
+//     to the text like by String.
 //   - opts...: Optional configurations applied to the response. These can be
 //     used to set custom headers, status codes, or other response settings.
 //
@@ -202,13 +219,43 @@ func String(w http.ResponseWriter, data string, opts ...Option) error {
 func Error(
 	w http.ResponseWriter,
 	code int,
-	message string,
+	message any,
 	opts ...Option,
 ) error {
 	response := NewResponse(w, opts...)
 	return response.Error(code, message)
 }
 
+// Err sends the error as the error response. The status code is taken
+// from the first error in the chain (see errors.As) that implements
+// StatusCoder, or from the registered error that matches it (see
+// RegisterErrorStatus), and is StatusInternalServerError otherwise; the
+// status set with the options takes precedence. The message of the error is
+// sent, so don't send the errors with the internal details this way.
+//
+// Example usage:
+//
+//	type NotFoundError struct{ ID int }
+//
+//	func (e NotFoundError) Error() string {
+//	    return fmt.Sprintf("user %d not found", e.ID)
+//	}
+//
+//	func (e NotFoundError) StatusCode() int { return resp.StatusNotFound }
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    user, err := store.User(id)
+//	    if err != nil {
+//	        // 404 {"code": 404, "message": "get user: user 7 not found"}
+//	        resp.Err(w, fmt.Errorf("get user: %w", err))
+//	        return
+//	    }
+//	    resp.JSON(w, user)
+//	}
+func Err(w http.ResponseWriter, err error, opts ...Option) error {
+	return NewResponse(w, opts...).Err(err)
+}
+
 // Stream sends a stream response to the client.
 //
 // This function facilitates the sending of streaming data, such as file
@@ -430,6 +477,9 @@ func NoContent(w http.ResponseWriter, opts ...Option) error {
 // configured with various options to set custom headers, status codes,
 // or other response settings, making it versatile for web development needs.
 //
+// The content is sent as is, so it must not contain unescaped user input;
+// prefer SafeHTML or HTMLTemplate, which escape the content.
+//
 // Parameters:
 //   - w: The http.ResponseWriter to which the HTML content will be written.
 //   - data: The HTML content to be sent as the response body. This should
@@ -0,0 +1,150 @@
+// Package respbench provides a benchmarking harness for comparing JSON
+// encoders through the exact write path of the github.com/goloop/resp
+// package.
+//
+// The harness sends every payload through resp.JSON with every encoder,
+// so the measured numbers include header preparation, status writing
+// and the encoder itself, just like in a real handler.
+//
+// Example Usage:
+//
+//	import (
+//		"testing"
+//
+//		"github.com/goloop/resp/respbench"
+//		"github.com/goloop/resp/respjsoniter"
+//		jsoniter "github.com/json-iterator/go"
+//	)
+//
+//	func BenchmarkEncoders(b *testing.B) {
+//		payloads := []respbench.Payload{
+//			{Name: "user", Data: loadUser()},
+//			{Name: "users", Data: loadUsers(1000)},
+//		}
+//		encoders := []respbench.Encoder{
+//			respbench.StdEncoder(),
+//			{
+//				Name: "jsoniter",
+//				Func: respjsoniter.Encoder(jsoniter.ConfigFastest),
+//			},
+//		}
+//		respbench.CompareEncoders(b, payloads, encoders)
+//	}
+package respbench
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goloop/resp"
+)
+
+// Payload represents a named value to be encoded during benchmarking.
+type Payload struct {
+	Name string // name of the sub-benchmark
+	Data any    // value passed to resp.JSON
+}
+
+// Encoder represents a named JSON encoder to be benchmarked.
+// If Func is nil, the default encoder of the resp package is used.
+type Encoder struct {
+	Name string              // name of the sub-benchmark
+	Func resp.JSONEncodeFunc // encoder, nil for encoding/json
+}
+
+// StdEncoder returns the Encoder that represents the default
+// encoding/json path of the resp package.
+func StdEncoder() Encoder {
+	return Encoder{Name: "encoding/json"}
+}
+
+// CompareEncoders runs a sub-benchmark for each payload and encoder
+// pair, named as "payload/encoder". Each iteration sends the payload
+// through resp.JSON to a writer that discards the body.
+//
+// The number of bytes produced by the encoder is reported with
+// b.SetBytes, so the results contain the throughput (MB/s) that
+// can be compared between encoders. Allocations are reported too.
+//
+// If an encoder fails on a payload, the sub-benchmark is failed.
+func CompareEncoders(b *testing.B, payloads []Payload, encoders []Encoder) {
+	b.Helper()
+
+	for _, p := range payloads {
+		p := p
+		b.Run(p.Name, func(b *testing.B) {
+			for _, e := range encoders {
+				e := e
+				b.Run(e.Name, func(b *testing.B) {
+					benchmarkEncoder(b, p.Data, e.Func)
+				})
+			}
+		})
+	}
+}
+
+// benchmarkEncoder benchmarks a single payload and encoder pair.
+func benchmarkEncoder(b *testing.B, data any, f resp.JSONEncodeFunc) {
+	var opts []resp.Option
+	if f != nil {
+		opts = append(opts, resp.ApplyJSONEncoder(f))
+	}
+
+	// Make a probe run to check the encoder and to measure the size
+	// of the encoded payload.
+	probe := newDiscardWriter()
+	if err := resp.JSON(probe, data, opts...); err != nil {
+		b.Fatalf("encoder failed: %v", err)
+	}
+	b.SetBytes(probe.written)
+
+	w := newDiscardWriter()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		w.reset()
+		if err := resp.JSON(w, data, opts...); err != nil {
+			b.Fatalf("encoder failed: %v", err)
+		}
+	}
+}
+
+// discardWriter is an http.ResponseWriter that discards
+// the body and counts the written bytes.
+type discardWriter struct {
+	header  http.Header
+	status  int
+	written int64
+}
+
+// newDiscardWriter creates a new discardWriter.
+func newDiscardWriter() *discardWriter {
+	return &discardWriter{header: make(http.Header)}
+}
+
+// Header returns the header map.
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader stores the status code.
+func (w *discardWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+// Write counts and discards the data.
+func (w *discardWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	return len(p), nil
+}
+
+// reset prepares the writer for the next iteration while keeping
+// the allocated header map.
+func (w *discardWriter) reset() {
+	for k := range w.header {
+		delete(w.header, k)
+	}
+	w.status = 0
+	w.written = 0
+}
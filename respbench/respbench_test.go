@@ -0,0 +1,110 @@
+package respbench
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+// shortBenchtime limits the benchmark time for tests that
+// run the harness through testing.Benchmark.
+func shortBenchtime(t *testing.T) {
+	t.Helper()
+
+	old := flag.Lookup("test.benchtime").Value.String()
+	if err := flag.Set("test.benchtime", "10x"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { flag.Set("test.benchtime", old) })
+}
+
+// testUser is a payload used in tests.
+type testUser struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
+
+// TestCompareEncoders tests that CompareEncoders runs every
+// payload and encoder pair and reports the payload size.
+func TestCompareEncoders(t *testing.T) {
+	shortBenchtime(t)
+
+	calls := 0
+	counting := func(w io.Writer, v interface{}) error {
+		calls++
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	payloads := []Payload{
+		{Name: "user", Data: testUser{ID: 1, Email: "a@example.com"}},
+		{Name: "users", Data: []testUser{{ID: 1}, {ID: 2}}},
+	}
+	encoders := []Encoder{StdEncoder(), {Name: "counting", Func: counting}}
+
+	result := testing.Benchmark(func(b *testing.B) {
+		CompareEncoders(b, payloads, encoders)
+	})
+
+	if calls == 0 {
+		t.Error("CompareEncoders() did not call the custom encoder")
+	}
+
+	if result.N == 0 {
+		t.Error("CompareEncoders() did not run")
+	}
+}
+
+// TestBenchmarkEncoder_Error tests that a failing encoder
+// fails the benchmark instead of reporting numbers.
+func TestBenchmarkEncoder_Error(t *testing.T) {
+	shortBenchtime(t)
+
+	failing := func(w io.Writer, v interface{}) error {
+		return errors.New("encode failed")
+	}
+
+	result := testing.Benchmark(func(b *testing.B) {
+		benchmarkEncoder(b, 1, failing)
+	})
+
+	if result.N != 0 {
+		t.Errorf("benchmarkEncoder() ran %d iterations with failing encoder",
+			result.N)
+	}
+}
+
+// TestDiscardWriter tests the discardWriter type.
+func TestDiscardWriter(t *testing.T) {
+	w := newDiscardWriter()
+	w.Header().Set("X-Test", "1")
+	w.WriteHeader(201)
+	if n, err := w.Write([]byte("hello")); n != 5 || err != nil {
+		t.Errorf("Write() = %d, %v", n, err)
+	}
+
+	if w.status != 201 || w.written != 5 {
+		t.Errorf("discardWriter status = %d, written = %d",
+			w.status, w.written)
+	}
+
+	w.reset()
+	if len(w.Header()) != 0 || w.status != 0 || w.written != 0 {
+		t.Error("reset() did not clear the writer")
+	}
+}
+
+// BenchmarkCompareEncoders demonstrates the harness usage.
+func BenchmarkCompareEncoders(b *testing.B) {
+	users := make([]testUser, 100)
+	for i := range users {
+		users[i] = testUser{ID: i, Email: "user@example.com"}
+	}
+
+	CompareEncoders(b, []Payload{
+		{Name: "small", Data: users[0]},
+		{Name: "large", Data: users},
+	}, []Encoder{StdEncoder()})
+}
@@ -0,0 +1,16 @@
+module github.com/goloop/resp/respjsoniter
+
+go 1.21
+
+require (
+	github.com/goloop/resp v1.2.0
+	github.com/json-iterator/go v1.1.12
+)
+
+require (
+	github.com/goloop/g v1.12.1 // indirect
+	github.com/goloop/trit v1.7.1 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
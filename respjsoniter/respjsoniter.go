@@ -0,0 +1,97 @@
+// Package respjsoniter provides ready-made JSON encoders for the
+// github.com/goloop/resp package backed by github.com/json-iterator/go.
+//
+// The functions in this package return resp.JSONEncodeFunc values that
+// behave exactly like the default encoding/json path of resp: the
+// output is terminated by a single newline character, which is what
+// resp.Response.JSONP expects to trim before wrapping the payload into
+// the callback.
+//
+// Example Usage:
+//
+//	import (
+//		"github.com/goloop/resp"
+//		"github.com/goloop/resp/respjsoniter"
+//	)
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//		data := resp.R{"message": "Hello, World!"}
+//		if err := resp.JSON(w, data, respjsoniter.Standard()); err != nil {
+//			// handle error
+//		}
+//	}
+package respjsoniter
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/goloop/resp"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// bufferPool is a pool of buffers used by the buffered encoder.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// Encoder returns a resp.JSONEncodeFunc that streams the value directly
+// into the response writer using the provided jsoniter API.
+//
+// The streaming encoder doesn't hold the whole document in memory, but
+// if encoding fails half way, part of the document may already have been
+// sent to the client. Use BufferedEncoder when this is not acceptable.
+func Encoder(api jsoniter.API) resp.JSONEncodeFunc {
+	return func(w io.Writer, v interface{}) error {
+		return api.NewEncoder(w).Encode(v)
+	}
+}
+
+// BufferedEncoder returns a resp.JSONEncodeFunc that encodes the value
+// into a pooled buffer first and writes it to the response writer with
+// a single call. Nothing is written if the value can't be encoded.
+//
+// The encoder terminates the output with a newline, so it is
+// byte-for-byte compatible with json.Encoder.
+func BufferedEncoder(api jsoniter.API) resp.JSONEncodeFunc {
+	return func(w io.Writer, v interface{}) error {
+		buf := bufferPool.Get().(*bytes.Buffer)
+		defer func() {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}()
+
+		if err := api.NewEncoder(buf).Encode(v); err != nil {
+			return err
+		}
+
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+}
+
+// Standard returns an option that sets the streaming encoder based on
+// the jsoniter configuration compatible with the standard library.
+func Standard() resp.Option {
+	return resp.ApplyJSONEncoder(
+		Encoder(jsoniter.ConfigCompatibleWithStandardLibrary),
+	)
+}
+
+// Fastest returns an option that sets the streaming encoder based on
+// the fastest jsoniter configuration. Pay attention, this configuration
+// doesn't escape HTML and loses precision of float values.
+func Fastest() resp.Option {
+	return resp.ApplyJSONEncoder(Encoder(jsoniter.ConfigFastest))
+}
+
+// Buffered returns an option that sets the buffered encoder based on
+// the jsoniter configuration compatible with the standard library.
+func Buffered() resp.Option {
+	return resp.ApplyJSONEncoder(
+		BufferedEncoder(jsoniter.ConfigCompatibleWithStandardLibrary),
+	)
+}
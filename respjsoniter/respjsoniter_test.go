@@ -0,0 +1,108 @@
+package respjsoniter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goloop/resp"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// TestEncoder tests that the streaming encoder matches encoding/json.
+func TestEncoder(t *testing.T) {
+	data := resp.R{"name": "Go Loop", "html": "<b>"}
+
+	var want bytes.Buffer
+	if err := json.NewEncoder(&want).Encode(data); err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	encode := Encoder(jsoniter.ConfigCompatibleWithStandardLibrary)
+	if err := encode(&got, data); err != nil {
+		t.Fatalf("Encoder() returned error: %v", err)
+	}
+
+	if got.String() != want.String() {
+		t.Errorf("Encoder() = %q, want %q", got.String(), want.String())
+	}
+}
+
+// TestBufferedEncoder tests that the buffered encoder matches
+// encoding/json and keeps the trailing newline.
+func TestBufferedEncoder(t *testing.T) {
+	data := []int{1, 2, 3}
+
+	var got bytes.Buffer
+	encode := BufferedEncoder(jsoniter.ConfigCompatibleWithStandardLibrary)
+	if err := encode(&got, data); err != nil {
+		t.Fatalf("BufferedEncoder() returned error: %v", err)
+	}
+
+	if want := "[1,2,3]\n"; got.String() != want {
+		t.Errorf("BufferedEncoder() = %q, want %q", got.String(), want)
+	}
+}
+
+// TestBufferedEncoder_Error tests that nothing is written
+// when the value can't be encoded.
+func TestBufferedEncoder_Error(t *testing.T) {
+	var got bytes.Buffer
+	encode := BufferedEncoder(jsoniter.ConfigCompatibleWithStandardLibrary)
+	if err := encode(&got, make(chan int)); err == nil {
+		t.Error("BufferedEncoder() expected error for channel value")
+	}
+
+	if got.Len() != 0 {
+		t.Errorf("BufferedEncoder() wrote %q on error", got.String())
+	}
+
+	if err := encode(errWriter{}, 1); err == nil {
+		t.Error("BufferedEncoder() expected error from writer")
+	}
+}
+
+// TestOptions tests the ready-made options with resp.JSON and resp.JSONP.
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  resp.Option
+	}{
+		{"Standard", Standard()},
+		{"Fastest", Fastest()},
+		{"Buffered", Buffered()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := resp.JSON(w, resp.R{"a": 1}, tt.opt); err != nil {
+				t.Fatalf("JSON() returned error: %v", err)
+			}
+
+			if got := w.Body.String(); got != "{\"a\":1}\n" {
+				t.Errorf("JSON() body = %q", got)
+			}
+
+			w = httptest.NewRecorder()
+			err := resp.JSONP(w, resp.R{"a": 1}, "cb", tt.opt)
+			if err != nil {
+				t.Fatalf("JSONP() returned error: %v", err)
+			}
+
+			if got := w.Body.String(); got != "cb({\"a\":1});" {
+				t.Errorf("JSONP() body = %q", got)
+			}
+		})
+	}
+}
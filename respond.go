@@ -0,0 +1,127 @@
+package resp
+
+import (
+	"encoding/xml"
+	"html/template"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Respond sends the value with the response method selected by its
+// type, so generic handlers and frameworks can send any result through
+// one entry point:
+//
+//   - nil - NoContent;
+//   - error - Err (the status code is derived from the error);
+//   - template.HTML - HTML;
+//   - string - String;
+//   - []byte - Blob (MIMEOctetStream unless the content type is set);
+//   - io.Reader - Stream;
+//   - struct (or pointer to struct) - JSON or XML, by the Accept header
+//     of the request;
+//   - other values (maps, slices, etc.) - JSON.
+//
+// JSON is sent if the request is nil, the Accept header is missing, or
+// neither JSON nor XML is acceptable. The Accept header is merged into
+// the Vary header of the negotiated responses (see WithoutAutoVary).
+//
+// Example Usage:
+//
+//	func Handle(h func(*http.Request) (any, error)) http.HandlerFunc {
+//	    return func(w http.ResponseWriter, r *http.Request) {
+//	        v, err := h(r)
+//	        if err != nil {
+//	            v = err
+//	        }
+//	        resp.Respond(w, r, v)
+//	    }
+//	}
+func Respond(
+	w http.ResponseWriter,
+	r *http.Request,
+	v any,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).Respond(r, v)
+}
+
+// Respond sends the value with the response method selected by
+// its type. See the Respond function for details.
+func (r *Response) Respond(req *http.Request, v any) error {
+	switch v := v.(type) {
+	case nil:
+		return r.NoContent()
+	case error:
+		return r.Err(v)
+	case template.HTML:
+		return r.HTML(string(v))
+	case string:
+		return r.String(v)
+	case []byte:
+		return r.Blob("", v)
+	case io.Reader:
+		return r.Stream(v)
+	}
+
+	if isStruct(v) && r.acceptsXML(req) {
+		return r.writeXML(v)
+	}
+
+	return r.JSON(v)
+}
+
+// acceptsXML reports whether the request prefers XML to JSON.
+// JSON wins when the qualities are equal.
+func (r *Response) acceptsXML(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+
+	r.varyOn(HeaderAccept)
+	accept := req.Header.Values(HeaderAccept)
+	if len(accept) == 0 {
+		return false
+	}
+
+	ranges := parseAccept(strings.Join(accept, ","))
+	jsonQ := quality(ranges, Variant{MediaType: MIMEApplicationJSON})
+	xmlQ := max(
+		quality(ranges, Variant{MediaType: MIMEApplicationXML}),
+		quality(ranges, Variant{MediaType: MIMETextXML}),
+	)
+
+	return xmlQ > jsonQ
+}
+
+// writeXML encodes the value as an XML document and sends it.
+func (r *Response) writeXML(v any) (err error) {
+	defer r.finish(&err)
+
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return r.encodeError("failed to encode XML response", err)
+	}
+	data = append([]byte(xml.Header), data...)
+
+	r.prepare(StatusOK, MIMEApplicationXMLCharsetUTF8)
+	r.setBodyDigest(data)
+	r.writeHeader(r.statusCode)
+	_, err = r.write(data)
+	return err
+}
+
+// isStruct reports whether the value is a struct or a pointer to
+// a struct, i.e. it can be encoded as a single XML element.
+func isStruct(v any) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+
+	return rv.Kind() == reflect.Struct
+}
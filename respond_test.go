@@ -0,0 +1,141 @@
+package resp
+
+import (
+	"errors"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// respondUser is the struct sent in the Respond tests.
+type respondUser struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+// TestRespond tests the Respond function.
+func TestRespond(t *testing.T) {
+	user := respondUser{ID: 7, Name: "Go Loop"}
+	tests := []struct {
+		name       string
+		value      any
+		accept     string
+		wantStatus int
+		wantType   string
+		want       string
+	}{
+		{
+			name:       "Nil",
+			wantStatus: StatusNoContent,
+		},
+		{
+			name:       "Error",
+			value:      notFoundError{7},
+			wantStatus: StatusNotFound,
+			wantType:   MIMEApplicationJSON,
+			want:       `{"code":404,"message":"user 7 not found"}` + "\n",
+		},
+		{
+			name:       "HTML",
+			value:      template.HTML("<b>Hi</b>"),
+			wantStatus: StatusOK,
+			wantType:   MIMETextHTML,
+			want:       "<b>Hi</b>",
+		},
+		{
+			name:       "String",
+			value:      "Hi",
+			wantStatus: StatusOK,
+			wantType:   MIMETextPlain,
+			want:       "Hi",
+		},
+		{
+			name:       "Bytes",
+			value:      []byte{1, 2},
+			wantStatus: StatusOK,
+			wantType:   MIMEOctetStream,
+			want:       "\x01\x02",
+		},
+		{
+			name:       "Reader",
+			value:      strings.NewReader("data"),
+			wantStatus: StatusOK,
+			wantType:   MIMEOctetStream,
+			want:       "data",
+		},
+		{
+			name:       "Struct as JSON",
+			value:      user,
+			accept:     "*/*",
+			wantStatus: StatusOK,
+			wantType:   MIMEApplicationJSON,
+			want:       `{"id":7,"name":"Go Loop"}` + "\n",
+		},
+		{
+			name:       "Struct as XML",
+			value:      &user,
+			accept:     "application/xml, application/json;q=0.5",
+			wantStatus: StatusOK,
+			wantType:   MIMEApplicationXML,
+			want: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<respondUser><id>7</id><name>Go Loop</name></respondUser>`,
+		},
+		{
+			name:       "Map ignores XML",
+			value:      R{"ok": true},
+			accept:     "application/xml",
+			wantStatus: StatusOK,
+			wantType:   MIMEApplicationJSON,
+			want:       `{"ok":true}` + "\n",
+		},
+		{
+			name:       "Not acceptable falls back to JSON",
+			value:      user,
+			accept:     "image/png",
+			wantStatus: StatusOK,
+			wantType:   MIMEApplicationJSON,
+			want:       `{"id":7,"name":"Go Loop"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				req.Header.Set(HeaderAccept, tt.accept)
+			}
+
+			w := httptest.NewRecorder()
+			if err := Respond(w, req, tt.value); err != nil {
+				t.Fatalf("Respond() error = %v", err)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			ct := w.Header().Get(HeaderContentType)
+			if !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRespond_XMLError tests that the XML encoding errors
+// wrap ErrEncodingFailed.
+func TestRespond_XMLError(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(HeaderAccept, MIMEApplicationXML)
+
+	value := struct{ Fn func() }{Fn: func() {}}
+	err := Respond(httptest.NewRecorder(), req, value)
+	if !errors.Is(err, ErrEncodingFailed) {
+		t.Errorf("Respond() error = %v, want %v", err, ErrEncodingFailed)
+	}
+}
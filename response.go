@@ -2,14 +2,18 @@ package resp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
-	"github.com/goloop/g"
+	"golang.org/x/text/encoding"
 )
 
 // JSONEncodeFunc represents a function that encodes the provided data
@@ -43,11 +47,53 @@ type Response struct {
 	httpWriter     http.ResponseWriter
 	statusCode     int
 	jsonEncodeFunc JSONEncodeFunc
+	bom            bool
+	textEncoding   encoding.Encoding
+	noAutoVary     bool
+
+	localeFormatter *LocaleFormatter
+	headerDenylist  []string
+	logger          *slog.Logger
+	meta            R
+	metaKey         string
+	links           Links
+	linksKey        string
+	view            *string
+	cursorBase      string
+	rangeRequest    *http.Request
+	validators      *downloadValidators
+	digest          *contentDigest
+	sendfileHeader  string
+	unsafeHTML      bool
+	minifier        Minifier
+	progress        ProgressFunc
+	ctx             context.Context
+	flushBatch      int
+	writeTimeout    time.Duration
+	bodyLimit       *bodyLimit
+	signature       *messageSignature
+	validateHeaders bool
+	errorFormat     ErrorFormat
+	errorRequest    *http.Request
+	errorTemplate   *template.Template
+
+	createdAt   time.Time
+	afterWrite  []AfterWriteFunc
+	wroteHeader bool
+	rewritten   bool
+	sentStatus  int
+	written     int64
+	writeErr    error
+	statusErr   error
+	headerErr   error
+	finished    bool
 }
 
 // NewResponse creates a new instance of Response with the provided
-// http.ResponseWriter and options. It applies the provided options
-// to the response and returns the pointer to the created response.
+// http.ResponseWriter and options. It applies the default options
+// (see SetDefaults), the options of the route (see With) and the
+// provided options to the response and returns the pointer to the
+// created response.
 //
 // Example Usage:
 //
@@ -55,11 +101,23 @@ type Response struct {
 //	    resp.AsApplicationJSON(),
 //	    resp.ApplyJSONEncoder(customEncoder))
 func NewResponse(w http.ResponseWriter, opts ...Option) *Response {
+	d, route := defaultOptions(), routeOptions(w)
+	if len(d) > 0 || len(route) > 0 {
+		opts = append(append(append([]Option{}, d...), route...), opts...)
+	}
+
+	return newResponse(w, opts)
+}
+
+// newResponse creates a new instance of Response with the provided
+// http.ResponseWriter and options, without the default options.
+func newResponse(w http.ResponseWriter, opts []Option) *Response {
 	// Create a new response with the provided http.ResponseWriter.
 	response := &Response{
 		httpWriter:     w,
 		statusCode:     StatusUndefined,
 		jsonEncodeFunc: nil,
+		createdAt:      time.Now(),
 	}
 
 	// Apply the provided options to the response.
@@ -105,7 +163,16 @@ func (r *Response) GetJSONEncoder() JSONEncodeFunc {
 
 // SetStatus sets the status code of the response and returns
 // the modified response.
+//
+// The codes outside the 100-599 range are rejected: the status code
+// isn't changed, and the next response method returns an error
+// wrapping ErrInvalidStatus. StatusUndefined resets the status code.
 func (r *Response) SetStatus(code int) *Response {
+	if code != StatusUndefined && !validStatus(code) {
+		r.statusErr = invalidStatus(code)
+		return r
+	}
+
 	r.statusCode = code
 	return r
 }
@@ -113,8 +180,13 @@ func (r *Response) SetStatus(code int) *Response {
 // SetHeader sets the header with the provided key and value(s) and
 // returns the modified response.
 func (r *Response) SetHeader(key string, value ...string) *Response {
+	if !r.checkHeader(key, value...) {
+		return r
+	}
+
 	// If the header can contain only one value, use first value only.
-	if g.In(key, singleHeaders...) && len(value) > 0 {
+	// A single value is set as is, without joining.
+	if len(value) == 1 || (len(value) > 0 && isSingleHeader(key)) {
 		r.httpWriter.Header().Set(key, value[0])
 		return r
 	}
@@ -127,9 +199,13 @@ func (r *Response) SetHeader(key string, value ...string) *Response {
 // AddHeader adds into header with the provided key and value(s) and
 // returns the modified response.
 func (r *Response) AddHeader(key string, value ...string) *Response {
+	if !r.checkHeader(key, value...) {
+		return r
+	}
+
 	// If the header can contain only one value, use first value only.
-	if g.In(key, singleHeaders...) && len(value) > 0 {
-		r.SetHeader(key, value[0])
+	if len(value) > 0 && isSingleHeader(key) {
+		r.httpWriter.Header().Set(key, value[0])
 		return r
 	}
 
@@ -141,6 +217,25 @@ func (r *Response) AddHeader(key string, value ...string) *Response {
 	return r
 }
 
+// Header returns the header map of the response that is sent with the
+// status code; changing it after the headers are sent has no effect.
+func (r *Response) Header() http.Header {
+	return r.httpWriter.Header()
+}
+
+// GetHeader returns the first value of the header with the provided
+// key, or the empty string if the header isn't set.
+func (r *Response) GetHeader(key string) string {
+	return r.httpWriter.Header().Get(key)
+}
+
+// HasHeader reports whether the header with the provided key is set,
+// even with the empty value.
+func (r *Response) HasHeader(key string) bool {
+	_, ok := r.httpWriter.Header()[http.CanonicalHeaderKey(key)]
+	return ok
+}
+
 // DelHeader deletes the header with the provided key from the response
 // and returns the modified response.
 func (r *Response) DelHeader(key string) *Response {
@@ -158,8 +253,11 @@ func (r *Response) ClearHeaders() *Response {
 }
 
 // SetCookie sets a cookie in the response and returns the modified response.
+// The cookie larger than MaxCookieSize isn't set (see ErrCookieTooLarge).
 func (r *Response) SetCookie(cookie *http.Cookie) *Response {
-	http.SetCookie(r.httpWriter, cookie)
+	if r.checkCookie(cookie) {
+		http.SetCookie(r.httpWriter, cookie)
+	}
 	return r
 }
 
@@ -171,29 +269,40 @@ func (r *Response) SetCookie(cookie *http.Cookie) *Response {
 func (r *Response) BindCookie(cookie *http.Cookie) *Response {
 	// Add the new one.
 	r.DelCookie(cookie.Name)
-	http.SetCookie(r.httpWriter, cookie)
-	return r
+	return r.SetCookie(cookie)
 }
 
 // DelCookie deletes a cookie with the specified name from the response.
 // It removes the cookie from the response's header and returns the
-// modified response.
+// modified response. The name is compared with the names of the
+// Set-Cookie headers exactly, so deleting "id" keeps "id_token".
 //
 // Pay attention. All cookies with this name will be deleted.
 func (r *Response) DelCookie(name string) *Response {
-	// Get all existing cookies.
-	// Filter cookies by name, without the one we want to add.
-	filteredCookies := []string{}
-	for _, c := range r.httpWriter.Header().Values(HeaderSetCookie) {
-		if !strings.HasPrefix(c, name+"=") {
+	return r.DelCookies(name)
+}
+
+// DelCookies deletes the cookies with the specified names from the
+// response and returns the modified response. See DelCookie.
+func (r *Response) DelCookies(names ...string) *Response {
+	header := r.httpWriter.Header()
+	values := header.Values(HeaderSetCookie)
+	if len(values) == 0 || len(names) == 0 {
+		return r
+	}
+
+	// Filter cookies by name, without the ones we want to delete.
+	filteredCookies := make([]string, 0, len(values))
+	for _, c := range values {
+		if !slices.Contains(names, setCookieName(c)) {
 			filteredCookies = append(filteredCookies, c)
 		}
 	}
 
 	// Remove all existing cookies, and add the filtered ones back.
-	r.httpWriter.Header().Del(HeaderSetCookie)
+	header.Del(HeaderSetCookie)
 	for _, c := range filteredCookies {
-		r.httpWriter.Header().Add(HeaderSetCookie, c)
+		header.Add(HeaderSetCookie, c)
 	}
 
 	return r
@@ -206,36 +315,152 @@ func (r *Response) ClearCookies() *Response {
 	return r
 }
 
+// CookieAttrs are the attributes of the cookie expired with
+// ExpiredCookieWith. The browser deletes the cookie only if the Path
+// and the Domain match the ones the cookie was set with.
+type CookieAttrs struct {
+	Path     string        // path of the cookie, "/" by default
+	Domain   string        // domain of the cookie, the host by default
+	Secure   bool          // the cookie was set with the Secure attribute
+	HttpOnly bool          // the cookie was set with the HttpOnly attribute
+	SameSite http.SameSite // SameSite attribute of the cookie
+}
+
 // ExpiredCookie expires a cookie with the specified name from the response.
+// The cookie is expired for the path "/" of the host; use
+// ExpiredCookieWith for the cookies set with other Path or Domain.
 func (r *Response) ExpiredCookie(name string) *Response {
+	return r.ExpiredCookieWith(name, CookieAttrs{})
+}
+
+// ExpiredCookieWith expires a cookie with the specified name and
+// attributes, e.g. the cookie set on a sub-path or for the parent
+// domain. The cookies with the "__Secure-" and "__Host-" prefixes are
+// always expired with the Secure attribute (and the "__Host-" cookies
+// with the path "/" and without the domain), since the browser rejects
+// them otherwise.
+//
+// Example Usage:
+//
+//	response.ExpiredCookieWith("session", resp.CookieAttrs{
+//	    Path:   "/admin",
+//	    Domain: "example.com",
+//	    Secure: true,
+//	})
+func (r *Response) ExpiredCookieWith(name string, attrs CookieAttrs) *Response {
+	path, domain, secure := attrs.Path, attrs.Domain, attrs.Secure
+	switch {
+	case strings.HasPrefix(name, "__Host-"):
+		path, domain, secure = "/", "", true
+	case strings.HasPrefix(name, "__Secure-"):
+		secure = true
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
 	expiredCookie := &http.Cookie{
-		Name:    name,
-		Value:   "deleted",
-		Path:    "/",
-		Expires: time.Unix(0, 0),
-		MaxAge:  -1,
+		Name:     name,
+		Value:    "deleted",
+		Path:     path,
+		Domain:   domain,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Secure:   secure,
+		HttpOnly: attrs.HttpOnly,
+		SameSite: attrs.SameSite,
 	}
 
 	http.SetCookie(r.httpWriter, expiredCookie)
 	return r
 }
 
+// Unwrap returns the underlying http.ResponseWriter, following the
+// convention of http.ResponseController and the middleware that probe
+// for the concrete writer (e.g. to flush or hijack the connection).
+//
+// Example Usage:
+//
+//	response := resp.NewResponse(w)
+//	rc := http.NewResponseController(response.Unwrap())
+//	rc.SetWriteDeadline(time.Now().Add(time.Minute))
+func (r *Response) Unwrap() http.ResponseWriter {
+	return r.httpWriter
+}
+
+// WriteHeaderNow sends the status code (StatusOK if it isn't set) and
+// the headers immediately, and returns the response. It is useful
+// before handing the writer to the code that writes the body itself,
+// e.g. an image encoder. Calling it again, or after the headers are
+// sent, has no effect.
+//
+// Example Usage:
+//
+//	response := resp.NewResponse(w, resp.AddContentType("image/png"))
+//	response.WriteHeaderNow()
+//	png.Encode(w, img)
+func (r *Response) WriteHeaderNow() *Response {
+	r.prepare(StatusOK)
+	r.writeHeader(r.statusCode)
+	return r
+}
+
+// jsonData returns the data transformed by the response
+// options before it is encoded as JSON.
+func (r *Response) jsonData(data any) (any, error) {
+	if r.view != nil {
+		data = ApplyView(data, *r.view)
+	}
+
+	data, err := r.injectLinks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.injectMeta(data)
+}
+
 // JSON sends a JSON response.
 // If the status code is not set - StatusOK will be set.
 // If ContentType isn't defined - MIMEApplicationJSON will be used by default.
-func (r *Response) JSON(data any) error {
+func (r *Response) JSON(data any) (err error) {
+	defer r.finish(&err)
+
+	data, err = r.jsonData(data)
+	if err != nil {
+		return r.encodeError("failed to encode JSON response", err)
+	}
+
 	r.prepare(StatusOK, MIMEApplicationJSONCharsetUTF8)
-	r.httpWriter.WriteHeader(r.statusCode)
+	if r.minifier != nil {
+		return r.writeMinifiedJSON(data)
+	}
+
+	// The signed digest must be sent in the headers.
+	if r.signsContentDigest() {
+		var buf bytes.Buffer
+		if err := r.encodeJSON(&buf, data); err != nil {
+			return r.encodeError("failed to encode JSON response", err)
+		}
+
+		r.setBodyDigest(buf.Bytes())
+		r.writeHeader(r.statusCode)
+		_, err := r.write(buf.Bytes())
+		return err
+	}
+
+	r.writeHeader(r.statusCode)
 
 	if r.jsonEncodeFunc != nil {
-		if err := r.jsonEncodeFunc(r.httpWriter, data); err != nil {
-			return fmt.Errorf("custom JSON encoder failed: %w", err)
+		if err := r.jsonEncodeFunc(r.body(), data); err != nil {
+			return r.encodeError("custom JSON encoder failed", err)
 		}
 		return nil
 	}
 
-	if err := json.NewEncoder(r.httpWriter).Encode(data); err != nil {
-		return fmt.Errorf("failed to encode JSON response: %w", err)
+	if err := json.NewEncoder(r.body()).Encode(data); err != nil {
+		return r.encodeError("failed to encode JSON response", err)
 	}
 	return nil
 }
@@ -244,21 +469,26 @@ func (r *Response) JSON(data any) error {
 // If the status code is not set - StatusOK will be set.
 // If ContentType isn't defined - MIMEApplicationJavaScript will
 // be used by default.
-func (r *Response) JSONP(data any, callback string) error {
+func (r *Response) JSONP(data any, callback string) (err error) {
+	defer r.finish(&err)
+
+	data, err = r.jsonData(data)
+	if err != nil {
+		return r.encodeError("failed to encode JSONP data", err)
+	}
+
 	r.prepare(StatusOK, MIMEApplicationJavaScriptCharsetUTF8)
-	r.httpWriter.WriteHeader(r.statusCode)
 
 	var buf bytes.Buffer
 
-	var err error
 	if r.jsonEncodeFunc != nil {
 		err = r.jsonEncodeFunc(&buf, data)
 		if err != nil {
-			return fmt.Errorf("custom JSON encoder failed in JSONP: %w", err)
+			return r.encodeError("custom JSON encoder failed in JSONP", err)
 		}
 	} else {
 		if err := json.NewEncoder(&buf).Encode(data); err != nil {
-			return fmt.Errorf("failed to encode JSONP data: %w", err)
+			return r.encodeError("failed to encode JSONP data", err)
 		}
 	}
 
@@ -269,7 +499,10 @@ func (r *Response) JSONP(data any, callback string) error {
 	}
 
 	// Write the JSONP response.
-	_, err = fmt.Fprintf(r.httpWriter, "%s(%s);", callback, jsonData)
+	body := fmt.Sprintf("%s(%s);", callback, jsonData)
+	r.setBodyDigest([]byte(body))
+	r.writeHeader(r.statusCode)
+	_, err = io.WriteString(r.body(), body)
 	if err != nil {
 		return fmt.Errorf("failed to write JSONP response: %w", err)
 	}
@@ -277,14 +510,34 @@ func (r *Response) JSONP(data any, callback string) error {
 	return nil
 }
 
-// String sends a string response.
+// String sends a string response. Besides strings, the data can be
+// a byte slice, an error, a fmt.Stringer, an encoding.TextMarshaler
+// or any other value formatted by fmt.Sprint.
 // If the status code is not set - StatusOK will be set.
 // If ContentType isn't defined - MIMETextPlain will be used by default.
-func (r *Response) String(data string) error {
+func (r *Response) String(value any) (err error) {
+	defer r.finish(&err)
+
+	data, err := textValue(value)
+	if err != nil {
+		return err
+	}
+
 	r.prepare(StatusOK, MIMETextPlain)
-	r.httpWriter.WriteHeader(r.statusCode)
-	_, err := r.httpWriter.Write([]byte(data))
-	return err
+	if !r.bom && r.textEncoding == nil {
+		r.setBodyDigest([]byte(data))
+	}
+
+	r.writeHeader(r.statusCode)
+	return r.writeText(strings.NewReader(data))
+}
+
+// Stringf formats the text according to the format specifier (see
+// fmt.Sprintf) and sends it as a string response.
+// If the status code is not set - StatusOK will be set.
+// If ContentType isn't defined - MIMETextPlain will be used by default.
+func (r *Response) Stringf(format string, args ...any) error {
+	return r.String(fmt.Sprintf(format, args...))
 }
 
 // Error sends an error response.
@@ -293,48 +546,109 @@ func (r *Response) String(data string) error {
 // only the first one will be used.
 //
 // If the status code isn't set - StatusInternalServerError will be set.
-func (r *Response) Error(code int, message string) error {
+func (r *Response) Error(code int, message any) (err error) {
+	defer r.finish(&err)
+
 	if r.statusCode == StatusUndefined {
 		r.statusCode = StatusInternalServerError
 	}
 
-	return r.JSON(newErrorResponse(code, message))
+	text, err := textValue(message)
+	if err != nil {
+		return err
+	}
+
+	return r.writeError(code, text)
+}
+
+// Err sends the error as the error response with the status code
+// derived from the error. See the Err function for details.
+func (r *Response) Err(e error) (err error) {
+	defer r.finish(&err)
+
+	status := errorStatus(e)
+	if r.statusCode == StatusUndefined {
+		r.statusCode = status
+	}
+
+	message := StatusText(r.statusCode)
+	if e != nil {
+		message = e.Error()
+	}
+
+	return r.writeError(r.statusCode, message)
 }
 
 // Stream sends a stream response.
-func (r *Response) Stream(data io.Reader) error {
+func (r *Response) Stream(data io.Reader) (err error) {
+	defer r.finish(&err)
+
 	r.prepare(StatusOK, MIMEOctetStream)
-	r.httpWriter.WriteHeader(r.statusCode)
-	_, err := io.Copy(r.httpWriter, data)
-	return err
+
+	// The signed digest must be sent in the headers.
+	if r.signsContentDigest() && !r.bom && r.textEncoding == nil {
+		body, err := io.ReadAll(data)
+		if err != nil {
+			return fmt.Errorf("failed to read stream: %w", err)
+		}
+
+		r.setBodyDigest(body)
+		data = bytes.NewReader(body)
+	}
+
+	r.writeHeader(r.statusCode)
+	return r.writeText(data)
 }
 
 // File sends a file response.
-func (r *Response) ServeFile(req *http.Request, file string) error {
+func (r *Response) ServeFile(req *http.Request, file string) (err error) {
+	defer r.finish(&err)
+
 	r.prepare(StatusOK, MIMEOctetStream)
 
 	// The http.ServeFile function from the net/http package independently
 	// sets the response headers and status code before starting the file
-	// transfer, no need: r.httpWriter.WriteHeader(r.statusCode)
-	http.ServeFile(r.httpWriter, req, file)
+	// transfer, no need: r.writeHeader(r.statusCode)
+	http.ServeFile(responseWriter{bodyWriter{r}}, req, file)
 	return nil
 }
 
 // ServeFileAsDownload sends a file as download response.
-func (r *Response) ServeFileAsDownload(fileName string, data []byte) error {
-	r.httpWriter.Header().Set(
-		HeaderContentDisposition,
-		"attachment; filename=\""+fileName+"\"",
-	)
+func (r *Response) ServeFileAsDownload(
+	fileName string,
+	data []byte,
+) (err error) {
+	defer r.finish(&err)
+
+	r.setAttachment(fileName)
 
 	r.prepare(StatusOK, MIMEOctetStream)
-	r.httpWriter.WriteHeader(r.statusCode)
-	_, err := r.httpWriter.Write(data)
-	return err
+	modtime := r.setValidators(data)
+	if r.rangeSupported() {
+		return r.ServeContent(
+			r.rangeRequest,
+			fileName,
+			modtime,
+			bytes.NewReader(data),
+		)
+	}
+
+	if r.notModified() {
+		return r.writeNotModified()
+	}
+
+	if !r.bom && r.textEncoding == nil {
+		r.setBodyDigest(data)
+	}
+
+	r.writeHeader(r.statusCode)
+	return r.writeText(bytes.NewReader(data))
 }
 
 // Redirect sends an HTTP redirect to the specified URL.
-func (r *Response) Redirect(url string) error {
+func (r *Response) Redirect(url string) (err error) {
+	defer r.finish(&err)
+
 	r.prepare(StatusFound)
 	s := r.statusCode
 
@@ -343,22 +657,32 @@ func (r *Response) Redirect(url string) error {
 	}
 
 	r.httpWriter.Header().Set("Location", url) // redirect to the specified URL
-	r.httpWriter.WriteHeader(s)
+	r.writeHeader(s)
 	return nil
 }
 
 // NoContent sends a 204 No Content response.
-func (r *Response) NoContent() error {
+func (r *Response) NoContent() (err error) {
+	defer r.finish(&err)
+
 	r.SetStatus(StatusNoContent)
 	r.prepare(StatusNoContent)
-	r.httpWriter.WriteHeader(http.StatusNoContent)
+	r.writeHeader(http.StatusNoContent)
 	return nil
 }
 
 // HTML sends an HTML response.
-func (r *Response) HTML(html string) error {
+func (r *Response) HTML(html string) (err error) {
+	defer r.finish(&err)
+
 	r.prepare(http.StatusOK, MIMETextHTMLCharsetUTF8)
-	r.httpWriter.WriteHeader(r.statusCode)
-	_, err := r.httpWriter.Write([]byte(html))
+	data, err := r.minify(MIMETextHTML, []byte(html))
+	if err != nil {
+		return err
+	}
+
+	r.setBodyDigest(data)
+	r.writeHeader(r.statusCode)
+	_, err = r.write(data)
 	return err
 }
@@ -137,6 +137,25 @@ func TestSetHeader_SingleValue(t *testing.T) {
 	}
 }
 
+// TestSetHeader_NonCanonical tests that the single value headers
+// are recognized regardless of the case of the name.
+func TestSetHeader_NonCanonical(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewResponse(w)
+
+	r.SetHeader("etag", `"a"`, `"b"`)
+	r.AddHeader("x-xss-protection", "0", "1")
+	if got := w.Header().Values(HeaderETag); len(got) != 1 || got[0] != `"a"` {
+		t.Errorf("SetHeader() for single value header = %v, want %v",
+			got, `"a"`)
+	}
+
+	if got := w.Header().Values(HeaderXXSSProtection); len(got) != 1 {
+		t.Errorf("AddHeader() for single value header = %v, want %v",
+			got, "0")
+	}
+}
+
 // TestSetHeader_MultipleValues tests the SetHeader method for
 // a multiple value header.
 func TestSetHeader_MultipleValues(t *testing.T) {
@@ -207,6 +226,37 @@ func TestDelHeader(t *testing.T) {
 	}
 }
 
+// TestHeaderGetters tests the Header, GetHeader and HasHeader methods.
+func TestHeaderGetters(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewResponse(w, WithHeader("X-Custom-Header", "Value"))
+	r.Header()["X-Empty"] = []string{""}
+
+	if got := r.GetHeader("x-custom-header"); got != "Value" {
+		t.Errorf("GetHeader() = %q, want %q", got, "Value")
+	}
+
+	if got := r.GetHeader("X-Missing"); got != "" {
+		t.Errorf("GetHeader() = %q, want empty", got)
+	}
+
+	tests := map[string]bool{
+		"X-Custom-Header": true,
+		"x-empty":         true,
+		"X-Missing":       false,
+	}
+	for key, want := range tests {
+		if got := r.HasHeader(key); got != want {
+			t.Errorf("HasHeader(%q) = %v, want %v", key, got, want)
+		}
+	}
+
+	r.Header().Set("X-Other", "1")
+	if got := w.Header().Get("X-Other"); got != "1" {
+		t.Errorf("Header() isn't the writer's header map")
+	}
+}
+
 // TestClearHeaders tests the ClearHeaders method.
 func TestClearHeaders(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -308,6 +358,25 @@ func TestDelCookieGlobal(t *testing.T) {
 	}
 }
 
+// TestDelCookies tests the DelCookies method.
+func TestDelCookies(t *testing.T) {
+	w := httptest.NewRecorder()
+	header := w.Header()
+	header.Add(HeaderSetCookie, "id=1; Path=/")
+	header.Add(HeaderSetCookie, "id_token=2; Path=/")
+	header.Add(HeaderSetCookie, " session = 3; HttpOnly")
+	header.Add(HeaderSetCookie, "theme=dark; Path=/")
+	header.Add(HeaderSetCookie, "invalid")
+
+	NewResponse(w).DelCookies("id", "session")
+
+	got := strings.Join(header.Values(HeaderSetCookie), " | ")
+	want := "id_token=2; Path=/ | theme=dark; Path=/ | invalid"
+	if got != want {
+		t.Errorf("DelCookies() left %q, want %q", got, want)
+	}
+}
+
 // TestClearCookies tests the ClearCookies method.
 func TestClearCookies(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -342,6 +411,71 @@ func TestExpiredCookie(t *testing.T) {
 	}
 }
 
+// TestExpiredCookieWith tests the ExpiredCookieWith method.
+func TestExpiredCookieWith(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		attrs  CookieAttrs
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "Path and domain",
+			cookie: "session",
+			attrs: CookieAttrs{
+				Path:     "/admin",
+				Domain:   "example.com",
+				Secure:   true,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			},
+			want: []string{"session=deleted", "Path=/admin",
+				"Domain=example.com", "Secure", "HttpOnly",
+				"SameSite=Lax", "Max-Age=0"},
+		},
+		{
+			name:   "Default path",
+			cookie: "theme",
+			want:   []string{"theme=deleted", "Path=/"},
+			absent: []string{"Domain=", "Secure"},
+		},
+		{
+			name:   "Secure prefix",
+			cookie: "__Secure-id",
+			attrs:  CookieAttrs{Path: "/app"},
+			want:   []string{"Path=/app", "Secure"},
+		},
+		{
+			name:   "Host prefix",
+			cookie: "__Host-id",
+			attrs:  CookieAttrs{Path: "/app", Domain: "example.com"},
+			want:   []string{"Path=/;", "Secure"},
+			absent: []string{"Domain=", "/app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewResponse(w).ExpiredCookieWith(tt.cookie, tt.attrs)
+
+			cookie := w.Header().Get(HeaderSetCookie)
+			for _, part := range tt.want {
+				if !strings.Contains(cookie, part) {
+					t.Errorf("cookie = %q, want %q", cookie, part)
+				}
+			}
+
+			for _, part := range tt.absent {
+				if strings.Contains(cookie, part) {
+					t.Errorf("cookie = %q, want no %q", cookie, part)
+				}
+			}
+		})
+	}
+}
+
 // TestJSON tests the JSON method.
 func TestJSON(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -607,6 +741,94 @@ func TestString(t *testing.T) {
 	}
 }
 
+// textStringer is a fmt.Stringer used in tests.
+type textStringer struct{ name string }
+
+// String returns the name.
+func (s textStringer) String() string { return "stringer " + s.name }
+
+// textMarshaler is an encoding.TextMarshaler used in tests.
+type textMarshaler struct{ err error }
+
+// MarshalText returns the text or the error.
+func (m textMarshaler) MarshalText() ([]byte, error) {
+	return []byte("marshaler"), m.err
+}
+
+// TestString_Values tests the String method with the non-string values.
+func TestString_Values(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{"Bytes", []byte("bytes"), "bytes"},
+		{"Error", errors.New("failed"), "failed"},
+		{"Stringer", textStringer{"a"}, "stringer a"},
+		{"Pointer stringer", &textStringer{"b"}, "stringer b"},
+		{"TextMarshaler", textMarshaler{}, "marshaler"},
+		{"Number", 42, "42"},
+		{"Nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := String(w, tt.data); err != nil {
+				t.Fatalf("String() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("String() body = %q, want %q", got, tt.want)
+			}
+
+			if ct := w.Header().Get(HeaderContentType); ct != MIMETextPlain {
+				t.Errorf("Content-Type = %q, want %q", ct, MIMETextPlain)
+			}
+		})
+	}
+
+	t.Run("TextMarshaler error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		errText := errors.New("no text")
+		err := String(w, textMarshaler{err: errText})
+		if !errors.Is(err, errText) {
+			t.Errorf("String() error = %v, want %v", err, errText)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Errorf("String() body = %q, want empty", w.Body.String())
+		}
+	})
+}
+
+// TestStringf tests the Stringf function and method.
+func TestStringf(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := Stringf(w, "Hello, %s! %d", "Go Loop", 42); err != nil {
+		t.Fatalf("Stringf() error = %v", err)
+	}
+
+	if got, want := w.Body.String(), "Hello, Go Loop! 42"; got != want {
+		t.Errorf("Stringf() body = %q, want %q", got, want)
+	}
+
+	if ct := w.Header().Get(HeaderContentType); ct != MIMETextPlain {
+		t.Errorf("Content-Type = %q, want %q", ct, MIMETextPlain)
+	}
+
+	w = httptest.NewRecorder()
+	err := NewResponse(w, WithStatusAccepted()).Stringf("Job %d", 7)
+	if err != nil {
+		t.Fatalf("Response.Stringf() error = %v", err)
+	}
+
+	if w.Code != StatusAccepted || w.Body.String() != "Job 7" {
+		t.Errorf("Response.Stringf() = %d %q, want %d %q",
+			w.Code, w.Body.String(), StatusAccepted, "Job 7")
+	}
+}
+
 // TestError tests the Error method.
 func TestError(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -776,6 +998,34 @@ func TestRedirectWithInvalidStatusCode(t *testing.T) {
 	}
 }
 
+// TestWriteHeaderNow tests the WriteHeaderNow method.
+func TestWriteHeaderNow(t *testing.T) {
+	w := httptest.NewRecorder()
+	response := NewResponse(w, WithStatusAccepted(), AsTextPlain())
+
+	response.WriteHeaderNow().WriteHeaderNow()
+	if w.Code != http.StatusAccepted {
+		t.Errorf("WriteHeaderNow() status = %v, want %v",
+			w.Code, http.StatusAccepted)
+	}
+
+	// The status set after the headers are sent is ignored.
+	response.SetStatus(http.StatusTeapot)
+	response.WriteHeaderNow()
+	if got := w.Result().StatusCode; got != http.StatusAccepted {
+		t.Errorf("WriteHeaderNow() status = %v, want %v",
+			got, http.StatusAccepted)
+	}
+
+	// The status code is StatusOK by default.
+	w = httptest.NewRecorder()
+	NewResponse(w).WriteHeaderNow()
+	if got := w.Result().StatusCode; got != http.StatusOK {
+		t.Errorf("WriteHeaderNow() status = %v, want %v",
+			got, http.StatusOK)
+	}
+}
+
 // TestNoContent tests the NoContent method.
 func TestNoContent(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -0,0 +1,81 @@
+// Package respotel records the responses written by the
+// github.com/goloop/resp package on OpenTelemetry spans,
+// so traces reflect what was actually sent to the client.
+//
+// Example Usage:
+//
+//	import (
+//		"github.com/goloop/resp"
+//		"github.com/goloop/resp/respotel"
+//	)
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//		resp.JSON(w, data, respotel.WithSpan(r.Context()))
+//	}
+package respotel
+
+import (
+	"context"
+
+	"github.com/goloop/resp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys set on the span. The status code and the body size
+// follow the OpenTelemetry semantic conventions for HTTP.
+const (
+	AttrStatusCode  = attribute.Key("http.response.status_code")
+	AttrBodySize    = attribute.Key("http.response.body.size")
+	AttrContentType = attribute.Key("http.response.header.content-type")
+)
+
+// EventName is the name of the span event added
+// when the response is written.
+const EventName = "resp.write"
+
+// WithSpan returns an option that records the response on the span
+// of the context (see trace.SpanFromContext) after it is written.
+//
+// The status code, body size and content type are set as span
+// attributes and also added as an event with the write duration.
+// If the response method failed, the error is recorded and the span
+// status is set to Error; a 5xx status code sets the Error status too.
+//
+// If the context has no recording span, the option does nothing.
+func WithSpan(ctx context.Context) resp.Option {
+	span := trace.SpanFromContext(ctx)
+	return resp.WithAfterWrite(func(info resp.ResponseInfo) {
+		Record(span, info)
+	})
+}
+
+// Record records the response info on the span.
+// See WithSpan for details.
+func Record(span trace.Span, info resp.ResponseInfo) {
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		AttrStatusCode.Int(info.Status),
+		AttrBodySize.Int64(info.Bytes),
+	}
+	if info.ContentType != "" {
+		attrs = append(attrs, AttrContentType.String(info.ContentType))
+	}
+
+	span.SetAttributes(attrs...)
+	span.AddEvent(EventName, trace.WithAttributes(append(attrs,
+		attribute.Int64("resp.duration_ms", info.Duration.Milliseconds()),
+	)...))
+
+	switch {
+	case info.Err != nil:
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+	case info.Status >= 500:
+		span.SetStatus(codes.Error, "")
+	}
+}
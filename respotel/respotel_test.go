@@ -0,0 +1,100 @@
+package respotel
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goloop/resp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newSpan starts a recording span and returns its context
+// and the recorder that receives the ended spans.
+func newSpan(t *testing.T) (context.Context, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	ctx, _ := tp.Tracer("test").Start(context.Background(), "request")
+	return ctx, rec
+}
+
+// attrs converts the attributes into a map.
+func attrs(kv []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kv))
+	for _, a := range kv {
+		m[a.Key] = a.Value
+	}
+	return m
+}
+
+// TestWithSpan tests that the response is recorded on the span.
+func TestWithSpan(t *testing.T) {
+	ctx, rec := newSpan(t)
+
+	w := httptest.NewRecorder()
+	if err := resp.JSON(w, resp.R{"a": 1}, WithSpan(ctx)); err != nil {
+		t.Fatal(err)
+	}
+	span := rec.Started()[0]
+	span.(sdktrace.ReadWriteSpan).End()
+
+	ended := rec.Ended()[0]
+	got := attrs(ended.Attributes())
+	if got[AttrStatusCode].AsInt64() != 200 {
+		t.Errorf("status attribute = %v", got[AttrStatusCode])
+	}
+
+	if got[AttrBodySize].AsInt64() != int64(w.Body.Len()) {
+		t.Errorf("body size attribute = %v", got[AttrBodySize])
+	}
+
+	if got[AttrContentType].AsString() != resp.MIMEApplicationJSONCharsetUTF8 {
+		t.Errorf("content type attribute = %v", got[AttrContentType])
+	}
+
+	events := ended.Events()
+	if len(events) != 1 || events[0].Name != EventName {
+		t.Errorf("events = %v", events)
+	}
+
+	if ended.Status().Code != codes.Unset {
+		t.Errorf("status = %v, want Unset", ended.Status())
+	}
+}
+
+// TestWithSpan_Error tests that errors and 5xx statuses
+// set the Error status of the span.
+func TestWithSpan_Error(t *testing.T) {
+	ctx, rec := newSpan(t)
+
+	w := httptest.NewRecorder()
+	resp.JSON(w, make(chan int), WithSpan(ctx))
+	rec.Started()[0].(sdktrace.ReadWriteSpan).End()
+
+	ended := rec.Ended()[0]
+	if ended.Status().Code != codes.Error {
+		t.Errorf("status = %v, want Error", ended.Status())
+	}
+
+	ctx, rec = newSpan(t)
+	resp.Error(httptest.NewRecorder(), 1, "boom", WithSpan(ctx))
+	rec.Started()[0].(sdktrace.ReadWriteSpan).End()
+
+	if rec.Ended()[0].Status().Code != codes.Error {
+		t.Errorf("status = %v, want Error", rec.Ended()[0].Status())
+	}
+}
+
+// TestWithSpan_NoSpan tests that the option works without a span.
+func TestWithSpan_NoSpan(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := resp.String(w, "ok", WithSpan(context.Background()))
+	if err != nil || w.Body.String() != "ok" {
+		t.Errorf("String() = %v, body %q", err, w.Body.String())
+	}
+}
@@ -0,0 +1,88 @@
+// Package respprom provides a Prometheus adapter for the resp.Metrics
+// interface of the github.com/goloop/resp package.
+//
+// Example Usage:
+//
+//	import (
+//		"github.com/goloop/resp"
+//		"github.com/goloop/resp/respprom"
+//		"github.com/prometheus/client_golang/prometheus"
+//	)
+//
+//	var metrics = respprom.New(prometheus.DefaultRegisterer, "myapp")
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//		resp.JSON(w, data, resp.WithMetrics(metrics, r, "/users/{id}"))
+//	}
+package respprom
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/goloop/resp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is a resp.Metrics implementation that records responses
+// into Prometheus collectors.
+//
+// The following metrics are registered (with the namespace prefix):
+//   - http_responses_total: counter of responses;
+//   - http_response_duration_seconds: histogram of response durations;
+//   - http_response_size_bytes: histogram of response body sizes.
+//
+// All metrics have the method, route and status labels.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+}
+
+// Compile-time check that Metrics implements resp.Metrics.
+var _ resp.Metrics = (*Metrics)(nil)
+
+// labels is the list of labels of all metrics.
+var labels = []string{"method", "route", "status"}
+
+// New creates the collectors and registers them with the registerer.
+// It panics if the collectors can't be registered, like
+// prometheus.MustRegister.
+func New(reg prometheus.Registerer, namespace string) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_responses_total",
+			Help:      "Total number of HTTP responses.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_response_duration_seconds",
+			Help:      "Duration of HTTP responses in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_response_size_bytes",
+			Help:      "Size of HTTP response bodies in bytes.",
+			Buckets:   prometheus.ExponentialBuckets(100, 10, 7),
+		}, labels),
+	}
+
+	reg.MustRegister(m.requests, m.duration, m.size)
+	return m
+}
+
+// ObserveResponse records the response.
+func (m *Metrics) ObserveResponse(
+	method string,
+	route string,
+	status int,
+	bytes int64,
+	duration time.Duration,
+) {
+	lv := []string{method, route, strconv.Itoa(status)}
+	m.requests.WithLabelValues(lv...).Inc()
+	m.duration.WithLabelValues(lv...).Observe(duration.Seconds())
+	m.size.WithLabelValues(lv...).Observe(float64(bytes))
+}
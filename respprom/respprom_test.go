@@ -0,0 +1,56 @@
+package respprom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goloop/resp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestMetrics tests that responses are recorded.
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg, "test")
+
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		err := resp.JSON(w, resp.R{"id": 1},
+			resp.WithMetrics(m, r, "/users/{id}"))
+		if err != nil {
+			t.Fatalf("JSON() returned error: %v", err)
+		}
+	}
+
+	want := `
+# HELP test_http_responses_total Total number of HTTP responses.
+# TYPE test_http_responses_total counter
+test_http_responses_total{method="GET",route="/users/{id}",status="200"} 2
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"test_http_responses_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(m.size); n != 1 {
+		t.Errorf("size histogram has %d series, want 1", n)
+	}
+}
+
+// TestNew_Duplicate tests that registering twice panics.
+func TestNew_Duplicate(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	New(reg, "test")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("New() did not panic on duplicate registration")
+		}
+	}()
+	New(reg, "test")
+}
@@ -0,0 +1,176 @@
+// Package resptest provides utilities for testing the HTTP handlers
+// built with the github.com/goloop/resp package.
+//
+// Record runs a handler against a recorder, and the Assert functions
+// check the recorded response, reporting the mismatches with a diff
+// of the expected and the actual values.
+//
+// Example Usage:
+//
+//	func TestGetUser(t *testing.T) {
+//		w := resptest.Record(http.HandlerFunc(GetUser),
+//			httptest.NewRequest("GET", "/users/1", nil))
+//
+//		resptest.AssertStatus(t, w, http.StatusOK)
+//		resptest.AssertHeader(t, w, "Content-Type",
+//			"application/json; charset=utf-8")
+//		resptest.AssertJSONEq(t, w, `{"id": 1, "name": "Go Loop"}`)
+//	}
+package resptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Record serves the request with the handler and returns the recorded
+// response. If the request is nil, a GET request for "/" is used.
+func Record(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	if req == nil {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// AssertStatus checks the status code of the response.
+func AssertStatus(t testing.TB, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+
+	if w.Code != want {
+		t.Errorf("status = %d %s, want %d %s",
+			w.Code, http.StatusText(w.Code), want, http.StatusText(want))
+	}
+}
+
+// AssertHeader checks the value of the header of the response.
+// If the header has several values, they are joined with ", ".
+func AssertHeader(
+	t testing.TB,
+	w *httptest.ResponseRecorder,
+	key, want string,
+) {
+	t.Helper()
+
+	values, ok := w.Header()[http.CanonicalHeaderKey(key)]
+	if !ok {
+		t.Errorf("header %s is missing, want %q", key, want)
+		return
+	}
+
+	if got := strings.Join(values, ", "); got != want {
+		t.Errorf("header %s = %q, want %q", key, got, want)
+	}
+}
+
+// AssertCookie checks the value of the cookie set by the response
+// and returns the cookie, or nil if it isn't set.
+func AssertCookie(
+	t testing.TB,
+	w *httptest.ResponseRecorder,
+	name, want string,
+) *http.Cookie {
+	t.Helper()
+
+	for _, c := range w.Result().Cookies() {
+		if c.Name != name {
+			continue
+		}
+
+		if c.Value != want {
+			t.Errorf("cookie %s = %q, want %q", name, c.Value, want)
+		}
+		return c
+	}
+
+	t.Errorf("cookie %s is missing, want %q", name, want)
+	return nil
+}
+
+// AssertJSONEq checks that the body of the response is the JSON
+// equal to the expected one, ignoring the formatting and the order
+// of the object keys. The mismatch is reported with a line diff of
+// the indented documents.
+func AssertJSONEq(t testing.TB, w *httptest.ResponseRecorder, want string) {
+	t.Helper()
+
+	got, err := indentJSON(w.Body.Bytes())
+	if err != nil {
+		t.Errorf("body isn't valid JSON: %v\nbody: %s", err, w.Body)
+		return
+	}
+
+	exp, err := indentJSON([]byte(want))
+	if err != nil {
+		t.Errorf("expected value isn't valid JSON: %v", err)
+		return
+	}
+
+	if got != exp {
+		t.Errorf("JSON body mismatch (-want +got):\n%s", diff(exp, got))
+	}
+}
+
+// indentJSON returns the normalized indented JSON document.
+func indentJSON(data []byte) (string, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// diff returns the line diff of the texts: the removed lines
+// are prefixed with "-", the added ones with "+".
+func diff(a, b string) string {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the longest common
+	// subsequence of the x[i:] and y[j:] lines.
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			fmt.Fprintf(&sb, "  %s\n", x[i])
+			i, j = i+1, j+1
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&sb, "- %s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&sb, "+ %s\n", y[j])
+			j++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,126 @@
+package resptest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goloop/resp"
+)
+
+// fakeT is a testing.TB that records the reported errors.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+// Helper does nothing.
+func (t *fakeT) Helper() {}
+
+// Errorf records the error.
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+// handler is the handler used in tests.
+func handler(w http.ResponseWriter, r *http.Request) {
+	resp.JSON(w, resp.R{"id": 1, "tags": []string{"a", "b"}},
+		resp.WithStatusCreated(),
+		resp.WithHeader("X-Request-ID", "42"),
+		resp.WithCookie(&http.Cookie{Name: "session", Value: "abc"}))
+}
+
+// TestAssertions tests that the assertions pass
+// for the matching response.
+func TestAssertions(t *testing.T) {
+	w := Record(http.HandlerFunc(handler), nil)
+	ft := &fakeT{TB: t}
+
+	AssertStatus(ft, w, http.StatusCreated)
+	AssertHeader(ft, w, "x-request-id", "42")
+	AssertJSONEq(ft, w, `{"tags": ["a", "b"], "id": 1}`)
+	if c := AssertCookie(ft, w, "session", "abc"); c == nil {
+		t.Error("AssertCookie() = nil, want cookie")
+	}
+
+	if len(ft.errors) != 0 {
+		t.Errorf("errors = %q, want none", ft.errors)
+	}
+}
+
+// TestAssertionsMismatch tests that the assertions
+// report the mismatches.
+func TestAssertionsMismatch(t *testing.T) {
+	w := Record(http.HandlerFunc(handler), nil)
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		want   string
+	}{
+		{
+			name:   "Status",
+			assert: func(t testing.TB) { AssertStatus(t, w, http.StatusOK) },
+			want:   "status = 201 Created, want 200 OK",
+		},
+		{
+			name:   "Missing header",
+			assert: func(t testing.TB) { AssertHeader(t, w, "X-Trace", "1") },
+			want:   "header X-Trace is missing",
+		},
+		{
+			name: "Header value",
+			assert: func(t testing.TB) {
+				AssertHeader(t, w, "X-Request-ID", "7")
+			},
+			want: `header X-Request-ID = "42", want "7"`,
+		},
+		{
+			name: "Cookie value",
+			assert: func(t testing.TB) {
+				AssertCookie(t, w, "session", "xyz")
+			},
+			want: `cookie session = "abc", want "xyz"`,
+		},
+		{
+			name: "JSON",
+			assert: func(t testing.TB) {
+				AssertJSONEq(t, w, `{"id": 2, "tags": ["a", "b"]}`)
+			},
+			want: "-   \"id\": 2,\n+   \"id\": 1,",
+		},
+		{
+			name: "Invalid expected JSON",
+			assert: func(t testing.TB) {
+				AssertJSONEq(t, w, `{"id":`)
+			},
+			want: "expected value isn't valid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{TB: t}
+			tt.assert(ft)
+
+			if len(ft.errors) != 1 {
+				t.Fatalf("errors = %q, want one", ft.errors)
+			}
+
+			if !strings.Contains(ft.errors[0], tt.want) {
+				t.Errorf("error = %q, want it to contain %q",
+					ft.errors[0], tt.want)
+			}
+		})
+	}
+}
+
+// TestDiff tests the diff function.
+func TestDiff(t *testing.T) {
+	got := diff("a\nb\nc", "a\nx\nc\nd")
+	want := "  a\n- b\n+ x\n  c\n+ d\n"
+	if got != want {
+		t.Errorf("diff() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package resptest
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Writer is an http.ResponseWriter that records the response like
+// httptest.ResponseRecorder and also implements the optional interfaces
+// of the writers of net/http: http.Flusher, http.Hijacker and
+// io.ReaderFrom. The calls of the methods are recorded, so the streaming
+// code paths (e.g. the flushes of the server-sent events or the sendfile
+// fast path) can be tested without a real server.
+//
+// Example Usage:
+//
+//	func TestEvents(t *testing.T) {
+//		w := resptest.NewWriter()
+//		Events(w, httptest.NewRequest("GET", "/events", nil))
+//
+//		if w.Flushes() != 3 {
+//			t.Errorf("flushes = %d, want 3", w.Flushes())
+//		}
+//	}
+type Writer struct {
+	*httptest.ResponseRecorder
+
+	mu        sync.Mutex
+	calls     []string
+	flushes   int
+	readFroms int
+	hijacked  bool
+	peer      net.Conn
+}
+
+// NewWriter creates a new Writer.
+func NewWriter() *Writer {
+	return &Writer{ResponseRecorder: httptest.NewRecorder()}
+}
+
+// record records the call of the method.
+func (w *Writer) record(method string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.calls = append(w.calls, method)
+	switch method {
+	case "Flush":
+		w.flushes++
+	case "ReadFrom":
+		w.readFroms++
+	}
+}
+
+// WriteHeader records the status code.
+func (w *Writer) WriteHeader(code int) {
+	w.record("WriteHeader")
+	w.ResponseRecorder.WriteHeader(code)
+}
+
+// Write records the data. After the connection is
+// hijacked, it returns http.ErrHijacked.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.record("Write")
+	if w.Hijacked() {
+		return 0, http.ErrHijacked
+	}
+
+	return w.ResponseRecorder.Write(p)
+}
+
+// Flush records the flush.
+func (w *Writer) Flush() {
+	w.record("Flush")
+	w.ResponseRecorder.Flush()
+}
+
+// ReadFrom records the data read from the reader.
+func (w *Writer) ReadFrom(src io.Reader) (int64, error) {
+	w.record("ReadFrom")
+	if w.Hijacked() {
+		return 0, http.ErrHijacked
+	}
+
+	// Hide the ReadFrom method to avoid the recursion.
+	return io.Copy(struct{ io.Writer }{w.ResponseRecorder}, src)
+}
+
+// Hijack takes over the connection. The returned connection is one
+// end of an in-memory pipe; the other end is returned by Peer, so the
+// test can read what the handler writes and write the client data.
+// Hijacking the connection twice returns an error.
+func (w *Writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.record("Hijack")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.hijacked {
+		return nil, nil, errors.New("connection already hijacked")
+	}
+
+	conn, peer := net.Pipe()
+	w.hijacked, w.peer = true, peer
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	return conn, rw, nil
+}
+
+// Peer returns the client end of the hijacked connection,
+// or nil if the connection isn't hijacked.
+func (w *Writer) Peer() net.Conn {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.peer
+}
+
+// Hijacked reports whether the connection is hijacked.
+func (w *Writer) Hijacked() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.hijacked
+}
+
+// Flushes returns the number of the Flush calls.
+func (w *Writer) Flushes() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.flushes
+}
+
+// ReadFroms returns the number of the ReadFrom calls.
+func (w *Writer) ReadFroms() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.readFroms
+}
+
+// Calls returns the names of the methods called on the writer
+// (WriteHeader, Write, Flush, ReadFrom and Hijack), in order.
+func (w *Writer) Calls() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.calls...)
+}
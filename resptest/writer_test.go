@@ -0,0 +1,94 @@
+package resptest
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goloop/resp"
+)
+
+// TestWriter tests that the Writer records the calls
+// of the streaming code paths of resp.
+func TestWriter(t *testing.T) {
+	// The struct hides the WriteTo method of the reader,
+	// so the body is copied with the ReadFrom method.
+	w := NewWriter()
+	src := struct{ io.Reader }{strings.NewReader("Hello")}
+	if err := resp.Stream(w, src); err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+
+	if w.ReadFroms() != 1 {
+		t.Errorf("ReadFroms() = %d, want %d", w.ReadFroms(), 1)
+	}
+
+	if got := w.Body.String(); got != "Hello" {
+		t.Errorf("body = %q, want %q", got, "Hello")
+	}
+
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	w = NewWriter()
+	if err := resp.StreamJSONChan(w, ch); err != nil {
+		t.Fatalf("StreamJSONChan() error = %v", err)
+	}
+
+	if w.Flushes() != 4 {
+		t.Errorf("Flushes() = %d, want %d", w.Flushes(), 4)
+	}
+
+	want := []string{
+		"WriteHeader",
+		"Write", "Flush", "Write", "Flush", "Write", "Flush", "Write", "Flush",
+	}
+	if got := w.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %q, want %q", got, want)
+	}
+
+	if got := w.Body.String(); got != "[1,2]\n" {
+		t.Errorf("body = %q, want %q", got, "[1,2]\n")
+	}
+}
+
+// TestWriterHijack tests the Hijack method.
+func TestWriterHijack(t *testing.T) {
+	w := NewWriter()
+	if w.Peer() != nil {
+		t.Error("Peer() before Hijack = non-nil")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		t.Fatalf("Hijack() error = %v", err)
+	}
+	defer conn.Close()
+
+	if !w.Hijacked() {
+		t.Error("Hijacked() = false, want true")
+	}
+
+	go func() {
+		rw.WriteString("pong")
+		rw.Flush()
+		conn.Close()
+	}()
+
+	got, err := io.ReadAll(w.Peer())
+	if err != nil || string(got) != "pong" {
+		t.Errorf("peer read = %q, %v", got, err)
+	}
+
+	if _, err := w.Write([]byte("late")); err != http.ErrHijacked {
+		t.Errorf("Write() error = %v, want %v", err, http.ErrHijacked)
+	}
+
+	if _, _, err := w.Hijack(); err == nil {
+		t.Error("second Hijack() error = nil")
+	}
+}
@@ -0,0 +1,158 @@
+package resp
+
+import "strings"
+
+// Get returns the value at the dot-separated path, e.g. "user.name",
+// or nil if there is no value. The nested maps can be R or
+// map[string]any.
+//
+// Example Usage:
+//
+//	data := resp.R{"user": resp.R{"name": "Go Loop"}}
+//	data.Get("user.name") // "Go Loop"
+func (m R) Get(path string) any {
+	v, _ := m.Lookup(path)
+	return v
+}
+
+// Lookup returns the value at the dot-separated path and
+// reports whether it is present. See Get for details.
+func (m R) Lookup(path string) (any, bool) {
+	var current any = m
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := asMap(current)
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// Set sets the value at the dot-separated path and returns the map.
+// The missing nested maps are created as R, and the values on the
+// path that aren't maps are replaced. If the map is nil, a new map
+// is returned.
+//
+// Example Usage:
+//
+//	data := resp.R{}.
+//	    Set("user.name", "Go Loop").
+//	    Set("user.address.city", "Kyiv")
+//	// {"user": {"name": "Go Loop", "address": {"city": "Kyiv"}}}
+func (m R) Set(path string, value any) R {
+	if m == nil {
+		m = R{}
+	}
+
+	keys := strings.Split(path, ".")
+	obj := map[string]any(m)
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := asMap(obj[key])
+		if !ok {
+			next = R{}
+			obj[key] = R(next)
+		}
+		obj = next
+	}
+
+	obj[keys[len(keys)-1]] = value
+	return m
+}
+
+// Merge merges the other map into the map and returns the map: the
+// nested maps are merged recursively, and the other values replace
+// the values of the map. The nested maps of the other map are cloned,
+// so changing them later doesn't change the map. If the map is nil,
+// a new map is returned.
+//
+// Example Usage:
+//
+//	defaults := resp.R{"page": resp.R{"size": 20, "number": 1}}
+//	data := defaults.Clone().Merge(resp.R{"page": resp.R{"number": 3}})
+//	// {"page": {"size": 20, "number": 3}}
+func (m R) Merge(other R) R {
+	if m == nil {
+		m = R{}
+	}
+
+	mergeMaps(m, other)
+	return m
+}
+
+// Clone returns a deep copy of the map: the nested maps (R and
+// map[string]any) and the slices of any are copied, other values
+// are copied as is.
+func (m R) Clone() R {
+	if m == nil {
+		return nil
+	}
+
+	return cloneValue(m).(R)
+}
+
+// mergeMaps merges the src map into the dst map.
+func mergeMaps(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcIsMap := asMap(value)
+		dstMap, dstIsMap := asMap(dst[key])
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+
+		dst[key] = cloneValue(value)
+	}
+}
+
+// cloneValue returns a deep copy of the maps and
+// the slices of any, and other values as is.
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case R:
+		if v == nil {
+			return v
+		}
+		c := make(R, len(v))
+		for key, item := range v {
+			c[key] = cloneValue(item)
+		}
+		return c
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		c := make(map[string]any, len(v))
+		for key, item := range v {
+			c[key] = cloneValue(item)
+		}
+		return c
+	case []any:
+		if v == nil {
+			return v
+		}
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = cloneValue(item)
+		}
+		return c
+	}
+
+	return value
+}
+
+// asMap returns the value as the map if it is R or map[string]any.
+func asMap(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case R:
+		return v, v != nil
+	case map[string]any:
+		return v, v != nil
+	}
+
+	return nil, false
+}
@@ -0,0 +1,137 @@
+package resp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestR_Get tests the Get and Lookup methods of R.
+func TestR_Get(t *testing.T) {
+	data := R{
+		"user": R{
+			"name":    "Go Loop",
+			"address": map[string]any{"city": "Kyiv"},
+			"empty":   nil,
+		},
+		"count": 2,
+	}
+
+	tests := []struct {
+		path string
+		want any
+		ok   bool
+	}{
+		{"count", 2, true},
+		{"user.name", "Go Loop", true},
+		{"user.address.city", "Kyiv", true},
+		{"user.empty", nil, true},
+		{"user.missing", nil, false},
+		{"count.value", nil, false},
+		{"missing.value", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := data.Lookup(tt.path)
+			if !reflect.DeepEqual(got, tt.want) || ok != tt.ok {
+				t.Errorf("Lookup() = %v, %v, want %v, %v",
+					got, ok, tt.want, tt.ok)
+			}
+
+			if got := data.Get(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestR_Set tests the Set method of R.
+func TestR_Set(t *testing.T) {
+	data := R{"user": map[string]any{"id": 1}, "count": 2}.
+		Set("user.name", "Go Loop").
+		Set("user.address.city", "Kyiv").
+		Set("count.value", 3).
+		Set("total", 10)
+
+	want := R{
+		"user": map[string]any{
+			"id":      1,
+			"name":    "Go Loop",
+			"address": R{"city": "Kyiv"},
+		},
+		"count": R{"value": 3},
+		"total": 10,
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Set() = %v, want %v", data, want)
+	}
+
+	var empty R
+	if got := empty.Set("a.b", 1); !reflect.DeepEqual(got,
+		R{"a": R{"b": 1}}) {
+		t.Errorf("Set() on nil = %v", got)
+	}
+}
+
+// TestR_Merge tests the Merge method of R.
+func TestR_Merge(t *testing.T) {
+	nested := R{"number": 3}
+	data := R{
+		"page":  R{"size": 20, "number": 1},
+		"title": "Users",
+	}.Merge(R{
+		"page":  nested,
+		"title": R{"text": "Users"},
+		"meta":  R{"v": 1},
+	})
+
+	want := R{
+		"page":  R{"size": 20, "number": 3},
+		"title": R{"text": "Users"},
+		"meta":  R{"v": 1},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Merge() = %v, want %v", data, want)
+	}
+
+	// The nested maps of the other map are cloned.
+	data.Merge(R{"extra": nested})
+	nested["number"] = 4
+	if got := data.Get("extra.number"); got != 3 {
+		t.Errorf("Merge() shares the nested map: %v", got)
+	}
+
+	var empty R
+	if got := empty.Merge(R{"a": 1}); !reflect.DeepEqual(got, R{"a": 1}) {
+		t.Errorf("Merge() on nil = %v", got)
+	}
+}
+
+// TestR_Clone tests the Clone method of R.
+func TestR_Clone(t *testing.T) {
+	data := R{
+		"user": R{"name": "Go Loop"},
+		"tags": []any{R{"id": 1}, "a"},
+		"raw":  map[string]any{"x": 1},
+	}
+
+	clone := data.Clone()
+	if !reflect.DeepEqual(clone, data) {
+		t.Fatalf("Clone() = %v, want %v", clone, data)
+	}
+
+	clone.Set("user.name", "Other")
+	clone["tags"].([]any)[0].(R)["id"] = 2
+	clone["raw"].(map[string]any)["x"] = 2
+
+	if data.Get("user.name") != "Go Loop" ||
+		data["tags"].([]any)[0].(R)["id"] != 1 ||
+		data.Get("raw.x") != 1 {
+		t.Errorf("Clone() isn't deep: %v", data)
+	}
+
+	var empty R
+	if empty.Clone() != nil {
+		t.Errorf("Clone() of nil isn't nil")
+	}
+}
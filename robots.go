@@ -0,0 +1,150 @@
+package resp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Directives of the X-Robots-Tag header.
+const (
+	RobotsAll                  = "all"
+	RobotsNoIndex              = "noindex"
+	RobotsNoFollow             = "nofollow"
+	RobotsNone                 = "none"
+	RobotsNoArchive            = "noarchive"
+	RobotsNoSnippet            = "nosnippet"
+	RobotsNoImageIndex         = "noimageindex"
+	RobotsNoTranslate          = "notranslate"
+	RobotsIndexIfEmbedded      = "indexifembedded"
+	RobotsNoSiteLinksSearchBox = "nositelinkssearchbox"
+)
+
+// robotsDirectives are the directives without a value.
+var robotsDirectives = map[string]bool{
+	RobotsAll:                  true,
+	RobotsNoIndex:              true,
+	RobotsNoFollow:             true,
+	RobotsNone:                 true,
+	RobotsNoArchive:            true,
+	RobotsNoSnippet:            true,
+	RobotsNoImageIndex:         true,
+	RobotsNoTranslate:          true,
+	RobotsIndexIfEmbedded:      true,
+	RobotsNoSiteLinksSearchBox: true,
+}
+
+// RobotsMaxSnippet returns the max-snippet directive: the maximum
+// number of characters of the text snippet; -1 means no limit.
+func RobotsMaxSnippet(n int) string {
+	return "max-snippet:" + strconv.Itoa(n)
+}
+
+// RobotsMaxImagePreview returns the max-image-preview directive:
+// the maximum size of the image preview, "none", "standard" or "large".
+func RobotsMaxImagePreview(size string) string {
+	return "max-image-preview:" + size
+}
+
+// RobotsMaxVideoPreview returns the max-video-preview directive: the
+// maximum number of seconds of the video snippet; -1 means no limit.
+func RobotsMaxVideoPreview(seconds int) string {
+	return "max-video-preview:" + strconv.Itoa(seconds)
+}
+
+// RobotsUnavailableAfter returns the unavailable_after directive:
+// the time after which the page isn't shown in the search results.
+func RobotsUnavailableAfter(t time.Time) string {
+	return "unavailable_after: " + t.UTC().Format(time.RFC3339)
+}
+
+// AddXRobotsTag adds the X-Robots-Tag header with the indexing
+// directives for the search engines, e.g. RobotsNoIndex. The directive
+// names are compared case-insensitively, and the unknown directives
+// (e.g. misspelled) are skipped, since the crawlers ignore them anyway.
+//
+// Example Usage:
+//
+//	resp.ServeFile(w, r, "report.pdf", resp.AddXRobotsTag(
+//	    resp.RobotsNoIndex, resp.RobotsMaxSnippet(50)))
+//	// X-Robots-Tag: noindex, max-snippet:50
+func AddXRobotsTag(directives ...string) Option {
+	return AddXRobotsTagFor("", directives...)
+}
+
+// AddXRobotsTagFor adds the X-Robots-Tag header with the directives
+// for the crawler, e.g. "googlebot". See AddXRobotsTag for details.
+//
+// Example Usage:
+//
+//	resp.AddXRobotsTagFor("googlebot", resp.RobotsNoFollow)
+//	// X-Robots-Tag: googlebot: nofollow
+func AddXRobotsTagFor(bot string, directives ...string) Option {
+	return func(r *Response) *Response {
+		valid := make([]string, 0, len(directives))
+		for _, d := range directives {
+			if d, ok := robotsDirective(d); ok {
+				valid = append(valid, d)
+			}
+		}
+
+		if len(valid) == 0 {
+			return r
+		}
+
+		value := strings.Join(valid, ", ")
+		if name := strings.TrimSpace(bot); name != "" {
+			value = strings.ToLower(name) + ": " + value
+		}
+
+		r.httpWriter.Header().Add(HeaderXRobotsTag, value)
+		return r
+	}
+}
+
+// NoIndex adds the X-Robots-Tag header that keeps the response out
+// of the search results, e.g. for the admin or staging responses.
+func NoIndex() Option {
+	return AddXRobotsTag(RobotsNoIndex)
+}
+
+// NoIndexNoFollow adds the X-Robots-Tag header that keeps the response
+// out of the search results and forbids following its links.
+func NoIndexNoFollow() Option {
+	return AddXRobotsTag(RobotsNoIndex, RobotsNoFollow)
+}
+
+// robotsDirective returns the normalized directive,
+// or false if the directive isn't known or is invalid.
+func robotsDirective(d string) (string, bool) {
+	d = strings.TrimSpace(d)
+	name, value, hasValue := strings.Cut(d, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	value = strings.TrimSpace(value)
+
+	if !hasValue {
+		return name, robotsDirectives[name]
+	}
+
+	switch name {
+	case "max-snippet", "max-video-preview":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < -1 {
+			return "", false
+		}
+		return name + ":" + value, true
+	case "max-image-preview":
+		value = strings.ToLower(value)
+		if value != "none" && value != "standard" && value != "large" {
+			return "", false
+		}
+		return name + ":" + value, true
+	case "unavailable_after":
+		if value == "" {
+			return "", false
+		}
+		return name + ": " + value, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,70 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestAddXRobotsTag tests the AddXRobotsTag function.
+func TestAddXRobotsTag(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want []string
+	}{
+		{
+			name: "Directives",
+			opt: AddXRobotsTag(RobotsNoIndex, "NoArchive",
+				RobotsMaxSnippet(50), RobotsMaxImagePreview("Large"),
+				RobotsMaxVideoPreview(-1)),
+			want: []string{"noindex, noarchive, max-snippet:50, " +
+				"max-image-preview:large, max-video-preview:-1"},
+		},
+		{
+			name: "Unavailable after",
+			opt: AddXRobotsTag(RobotsUnavailableAfter(
+				time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))),
+			want: []string{"unavailable_after: 2030-01-02T03:04:05Z"},
+		},
+		{
+			name: "Invalid skipped",
+			opt: AddXRobotsTag("noindx", RobotsNoFollow,
+				"max-snippet:abc", "max-image-preview:huge", "foo:bar"),
+			want: []string{"nofollow"},
+		},
+		{
+			name: "Nothing valid",
+			opt:  AddXRobotsTag("noindx"),
+			want: nil,
+		},
+		{
+			name: "For bot",
+			opt:  AddXRobotsTagFor("GoogleBot", RobotsNoFollow),
+			want: []string{"googlebot: nofollow"},
+		},
+		{
+			name: "NoIndex",
+			opt:  NoIndex(),
+			want: []string{"noindex"},
+		},
+		{
+			name: "NoIndexNoFollow",
+			opt:  NoIndexNoFollow(),
+			want: []string{"noindex, nofollow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewResponse(w, tt.opt)
+
+			got := w.Header().Values(HeaderXRobotsTag)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("X-Robots-Tag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package resp
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// WithUnsafeHTML allows SafeHTML to send plain strings as is, without
+// escaping. Use it only for trusted content, e.g. the HTML generated by
+// the application itself.
+func WithUnsafeHTML() Option {
+	return func(r *Response) *Response {
+		r.unsafeHTML = true
+		return r
+	}
+}
+
+// SafeHTML sends an HTML response with the content escaped by default:
+// a template.HTML value is sent as is (it is trusted by its type), and
+// any other value (a string, a fmt.Stringer, a number) is formatted and
+// HTML-escaped, unless the WithUnsafeHTML option is used.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    // <p>Hello, &lt;script&gt;</p>
+//	    resp.SafeHTML(w, "<p>Hello, "+r.FormValue("name")+"</p>")
+//
+//	    // <p>Hello</p>
+//	    resp.SafeHTML(w, template.HTML("<p>Hello</p>"))
+//	}
+func SafeHTML(w http.ResponseWriter, content any, opts ...Option) error {
+	return NewResponse(w, opts...).SafeHTML(content)
+}
+
+// SafeHTML sends an HTML response with the content escaped by default.
+// See the SafeHTML function for details.
+func (r *Response) SafeHTML(content any) error {
+	switch v := content.(type) {
+	case template.HTML:
+		return r.HTML(string(v))
+	case string:
+		if r.unsafeHTML {
+			return r.HTML(v)
+		}
+		return r.HTML(template.HTMLEscapeString(v))
+	}
+
+	s := fmt.Sprint(content)
+	if r.unsafeHTML {
+		return r.HTML(s)
+	}
+
+	return r.HTML(template.HTMLEscapeString(s))
+}
+
+// HTMLTemplate executes the html/template template with the data and
+// sends the result as an HTML response, so the data is escaped
+// according to its context. The template is executed into a buffer,
+// so nothing is written if it fails and the error can still be sent.
+//
+// Example Usage:
+//
+//	var page = template.Must(template.New("page").Parse(
+//	    `<h1>{{ .Title }}</h1>`))
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.HTMLTemplate(w, page, Page{Title: r.FormValue("q")})
+//	}
+func HTMLTemplate(
+	w http.ResponseWriter,
+	tmpl *template.Template,
+	data any,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).HTMLTemplate(tmpl, data)
+}
+
+// HTMLTemplate executes the template with the data
+// and sends the result as an HTML response.
+// See the HTMLTemplate function for details.
+func (r *Response) HTMLTemplate(tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	return r.HTML(buf.String())
+}
@@ -0,0 +1,82 @@
+package resp
+
+import (
+	"html/template"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSafeHTML tests the SafeHTML function.
+func TestSafeHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content any
+		opts    []Option
+		want    string
+	}{
+		{
+			name:    "String is escaped",
+			content: "<p>Hello, <script>x</script></p>",
+			want:    "&lt;p&gt;Hello, &lt;script&gt;x&lt;/script&gt;&lt;/p&gt;",
+		},
+		{
+			name:    "Trusted type",
+			content: template.HTML("<p>Hello</p>"),
+			want:    "<p>Hello</p>",
+		},
+		{
+			name:    "Unsafe string",
+			content: "<p>Hello</p>",
+			opts:    []Option{WithUnsafeHTML()},
+			want:    "<p>Hello</p>",
+		},
+		{
+			name:    "Other value",
+			content: 42,
+			want:    "42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := SafeHTML(w, tt.content, tt.opts...); err != nil {
+				t.Fatalf("SafeHTML() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("SafeHTML() body = %q, want %q", got, tt.want)
+			}
+
+			want := MIMETextHTMLCharsetUTF8
+			if got := w.Header().Get(HeaderContentType); got != want {
+				t.Errorf("Content-Type = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestHTMLTemplate tests the HTMLTemplate function.
+func TestHTMLTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("page").Parse(
+		`<h1>{{ .Title }}</h1>`))
+
+	w := httptest.NewRecorder()
+	data := struct{ Title string }{"<b>Go</b>"}
+	if err := HTMLTemplate(w, tmpl, data); err != nil {
+		t.Fatalf("HTMLTemplate() error = %v", err)
+	}
+
+	want := "<h1>&lt;b&gt;Go&lt;/b&gt;</h1>"
+	if got := w.Body.String(); got != want {
+		t.Errorf("HTMLTemplate() body = %q, want %q", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	if err := HTMLTemplate(w, tmpl, 1); err == nil {
+		t.Error("HTMLTemplate() error = nil, want execution error")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("HTMLTemplate() body = %q, want empty", w.Body.String())
+	}
+}
@@ -0,0 +1,130 @@
+package resp
+
+// SecureHeadersConfig represents the set of security headers applied
+// by the WithSecureHeaders option. An empty string value (or zero
+// HSTSMaxAge) means that the corresponding header isn't set.
+//
+// Example Usage:
+//
+//	config := resp.DefaultSecureHeadersConfig()
+//	config.FrameOptions = "SAMEORIGIN"
+//	config.ContentSecurityPolicy = "default-src 'self'"
+//
+//	resp.JSON(w, data, resp.WithSecureHeaders(config))
+type SecureHeadersConfig struct {
+	// HSTSMaxAge is the max-age value (in seconds) of the
+	// Strict-Transport-Security header.
+	HSTSMaxAge int
+
+	// HSTSIncludeSubDomains adds the includeSubDomains directive
+	// to the Strict-Transport-Security header.
+	HSTSIncludeSubDomains bool
+
+	// HSTSPreload adds the preload directive to the
+	// Strict-Transport-Security header.
+	HSTSPreload bool
+
+	// ContentTypeOptions is the value of the X-Content-Type-Options header.
+	ContentTypeOptions string
+
+	// FrameOptions is the value of the X-Frame-Options header.
+	FrameOptions string
+
+	// ReferrerPolicy is the value of the Referrer-Policy header.
+	ReferrerPolicy string
+
+	// CrossOriginOpenerPolicy is the value of the
+	// Cross-Origin-Opener-Policy header.
+	CrossOriginOpenerPolicy string
+
+	// CrossOriginEmbedderPolicy is the value of the
+	// Cross-Origin-Embedder-Policy header.
+	CrossOriginEmbedderPolicy string
+
+	// CrossOriginResourcePolicy is the value of the
+	// Cross-Origin-Resource-Policy header.
+	CrossOriginResourcePolicy string
+
+	// ContentSecurityPolicy is the value of the
+	// Content-Security-Policy header.
+	ContentSecurityPolicy string
+
+	// PermissionsPolicy is the value of the Permissions-Policy header.
+	PermissionsPolicy string
+}
+
+// DefaultSecureHeadersConfig returns the configuration used by
+// WithSecureHeaders when no configuration is provided.
+//
+// The defaults are:
+//   - Strict-Transport-Security: max-age=63072000; includeSubDomains
+//   - X-Content-Type-Options: nosniff
+//   - X-Frame-Options: DENY
+//   - Referrer-Policy: strict-origin-when-cross-origin
+//   - Cross-Origin-Opener-Policy: same-origin
+//   - Cross-Origin-Embedder-Policy: require-corp
+//   - Cross-Origin-Resource-Policy: same-origin
+func DefaultSecureHeadersConfig() SecureHeadersConfig {
+	return SecureHeadersConfig{
+		HSTSMaxAge:                63072000, // two years
+		HSTSIncludeSubDomains:     true,
+		ContentTypeOptions:        "nosniff",
+		FrameOptions:              "DENY",
+		ReferrerPolicy:            "strict-origin-when-cross-origin",
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginEmbedderPolicy: "require-corp",
+		CrossOriginResourcePolicy: "same-origin",
+	}
+}
+
+// WithSecureHeaders sets a group of security related headers.
+// If no configuration is passed, the DefaultSecureHeadersConfig is
+// used. If more than one configuration is passed, only the first
+// one will be used.
+//
+// The headers are set (not added), so calling the option several
+// times doesn't produce duplicate values.
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.JSON(w, data, resp.WithSecureHeaders())
+//	}
+func WithSecureHeaders(config ...SecureHeadersConfig) Option {
+	c := DefaultSecureHeadersConfig()
+	if len(config) > 0 {
+		c = config[0]
+	}
+
+	return func(r *Response) *Response {
+		if c.HSTSMaxAge > 0 {
+			AddStrictTransportSecurity(
+				c.HSTSMaxAge,
+				c.HSTSIncludeSubDomains,
+				c.HSTSPreload,
+			)(r)
+		}
+
+		headers := []struct {
+			key   string
+			value string
+		}{
+			{HeaderXContentTypeOptions, c.ContentTypeOptions},
+			{HeaderXFrameOptions, c.FrameOptions},
+			{HeaderReferrerPolicy, c.ReferrerPolicy},
+			{HeaderCrossOriginOpenerPolicy, c.CrossOriginOpenerPolicy},
+			{HeaderCrossOriginEmbedderPolicy, c.CrossOriginEmbedderPolicy},
+			{HeaderCrossOriginResourcePolicy, c.CrossOriginResourcePolicy},
+			{HeaderContentSecurityPolicy, c.ContentSecurityPolicy},
+			{HeaderPermissionsPolicy, c.PermissionsPolicy},
+		}
+
+		for _, h := range headers {
+			if h.value != "" {
+				r.SetHeader(h.key, h.value)
+			}
+		}
+
+		return r
+	}
+}
@@ -0,0 +1,73 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithSecureHeaders tests the WithSecureHeaders function
+// with the default configuration.
+func TestWithSecureHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, WithSecureHeaders())
+
+	tests := map[string]string{
+		HeaderStrictTransportSecurity:   "max-age=63072000; includeSubDomains",
+		HeaderXContentTypeOptions:       "nosniff",
+		HeaderXFrameOptions:             "DENY",
+		HeaderReferrerPolicy:            "strict-origin-when-cross-origin",
+		HeaderCrossOriginOpenerPolicy:   "same-origin",
+		HeaderCrossOriginEmbedderPolicy: "require-corp",
+		HeaderCrossOriginResourcePolicy: "same-origin",
+		HeaderContentSecurityPolicy:     "",
+		HeaderPermissionsPolicy:         "",
+	}
+
+	for key, want := range tests {
+		if got := w.Header().Get(key); got != want {
+			t.Errorf("WithSecureHeaders() %s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+// TestWithSecureHeaders_Config tests the WithSecureHeaders function
+// with a custom configuration.
+func TestWithSecureHeaders_Config(t *testing.T) {
+	config := DefaultSecureHeadersConfig()
+	config.HSTSMaxAge = 0
+	config.FrameOptions = "SAMEORIGIN"
+	config.ContentSecurityPolicy = "default-src 'self'"
+
+	w := httptest.NewRecorder()
+	NewResponse(w, WithSecureHeaders(config))
+
+	if got := w.Header().Get(HeaderStrictTransportSecurity); got != "" {
+		t.Errorf("WithSecureHeaders() unexpected HSTS header %q", got)
+	}
+
+	if got := w.Header().Get(HeaderXFrameOptions); got != "SAMEORIGIN" {
+		t.Errorf("WithSecureHeaders() X-Frame-Options = %q", got)
+	}
+
+	got := w.Header().Get(HeaderContentSecurityPolicy)
+	if got != "default-src 'self'" {
+		t.Errorf("WithSecureHeaders() Content-Security-Policy = %q", got)
+	}
+}
+
+// TestWithSecureHeaders_Repeated tests that repeated application
+// of the option doesn't duplicate header values.
+func TestWithSecureHeaders_Repeated(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, WithSecureHeaders(), WithSecureHeaders())
+
+	for _, key := range []string{
+		HeaderStrictTransportSecurity,
+		HeaderXFrameOptions,
+		HeaderReferrerPolicy,
+	} {
+		if n := len(w.Header().Values(key)); n != 1 {
+			t.Errorf("WithSecureHeaders() %s has %d values", key, n)
+		}
+	}
+}
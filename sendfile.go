@@ -0,0 +1,64 @@
+package resp
+
+import (
+	"mime"
+	"net/http"
+	"path"
+)
+
+// WithSendfileHeader sets the header used by ServeFileViaProxy to pass
+// the file path to the proxy: HeaderXAccelRedirect (nginx, default) or
+// HeaderXSendfile (Apache, lighttpd).
+func WithSendfileHeader(name string) Option {
+	return func(r *Response) *Response {
+		r.sendfileHeader = name
+		return r
+	}
+}
+
+// ServeFileViaProxy offloads serving the file to the reverse proxy:
+// instead of streaming the bytes through Go, it sends an empty response
+// with the X-Accel-Redirect header (nginx) or the X-Sendfile header
+// (Apache, see WithSendfileHeader) pointing to the internal path of the
+// file. The Content-Type is detected from the extension of the path
+// unless it is set; use AddContentDisposition to send the file as a
+// download.
+//
+// Example Usage:
+//
+//	// nginx: location /protected/ { internal; alias /var/files/; }
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    resp.ServeFileViaProxy(w, "/protected/report.pdf",
+//	        resp.AddContentDisposition("attachment", "report.pdf"))
+//	}
+func ServeFileViaProxy(
+	w http.ResponseWriter,
+	internalPath string,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).ServeFileViaProxy(internalPath)
+}
+
+// ServeFileViaProxy offloads serving the file to the reverse proxy.
+// See the ServeFileViaProxy function for details.
+func (r *Response) ServeFileViaProxy(internalPath string) (err error) {
+	defer r.finish(&err)
+
+	name := r.sendfileHeader
+	if name == "" {
+		name = HeaderXAccelRedirect
+	}
+
+	header := r.httpWriter.Header()
+	header.Set(name, internalPath)
+
+	if _, ok := header[HeaderContentType]; !ok {
+		if ctype := mime.TypeByExtension(path.Ext(internalPath)); ctype != "" {
+			header.Set(HeaderContentType, ctype)
+		}
+	}
+
+	r.prepare(StatusOK, MIMEOctetStream)
+	r.writeHeader(r.statusCode)
+	return nil
+}
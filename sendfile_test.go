@@ -0,0 +1,66 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestServeFileViaProxy tests the ServeFileViaProxy function.
+func TestServeFileViaProxy(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		opts     []Option
+		header   string
+		wantType string
+	}{
+		{
+			name:     "nginx",
+			path:     "/protected/report.pdf",
+			header:   HeaderXAccelRedirect,
+			wantType: "application/pdf",
+		},
+		{
+			name:     "Apache",
+			path:     "/var/files/report.bin.unknownext",
+			opts:     []Option{WithSendfileHeader(HeaderXSendfile)},
+			header:   HeaderXSendfile,
+			wantType: MIMEOctetStream,
+		},
+		{
+			name:     "Explicit type",
+			path:     "/protected/report.pdf",
+			opts:     []Option{AsTextPlain()},
+			header:   HeaderXAccelRedirect,
+			wantType: MIMETextPlain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			opts := append(tt.opts,
+				AddContentDisposition("attachment", "report.pdf"))
+			if err := ServeFileViaProxy(w, tt.path, opts...); err != nil {
+				t.Fatalf("ServeFileViaProxy() error = %v", err)
+			}
+
+			if got := w.Header().Get(tt.header); got != tt.path {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.path)
+			}
+
+			if got := w.Header().Get(HeaderContentType); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+
+			want := `attachment; filename="report.pdf"`
+			if got := w.Header().Get(HeaderContentDisposition); got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+
+			if w.Code != StatusOK || w.Body.Len() != 0 {
+				t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package resp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerTimingMetric is a metric of the Server-Timing header,
+// e.g. the duration of the database query.
+type ServerTimingMetric struct {
+	Name        string        // name of the metric, e.g. "db"
+	Duration    time.Duration // duration; not sent if zero
+	Description string        // human-readable description
+}
+
+// String returns the metric as the member of the Server-Timing header,
+// e.g. `db;dur=53.2;desc="Database"`. The duration is sent in
+// milliseconds.
+func (m ServerTimingMetric) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.Name)
+	if m.Duration != 0 {
+		ms := float64(m.Duration) / float64(time.Millisecond)
+		sb.WriteString(";dur=" + strconv.FormatFloat(ms, 'f', -1, 64))
+	}
+
+	if m.Description != "" {
+		sb.WriteString(";desc=" + quoteString(m.Description))
+	}
+
+	return sb.String()
+}
+
+// AddServerTiming adds the metrics to the Server-Timing header, so the
+// browser exposes them in the Resource Timing API (developer tools).
+// The metrics of the cross-origin requests are exposed only to the
+// origins allowed by AddTimingAllowOrigin.
+//
+// Example Usage:
+//
+//	resp.JSON(w, data, resp.AddServerTiming(
+//	    resp.ServerTimingMetric{Name: "db", Duration: dbTime},
+//	    resp.ServerTimingMetric{Name: "cache", Description: "Hit"}))
+//	// Server-Timing: db;dur=53.2, cache;desc="Hit"
+func AddServerTiming(metrics ...ServerTimingMetric) Option {
+	return func(r *Response) *Response {
+		if len(metrics) == 0 {
+			return r
+		}
+
+		values := make([]string, len(metrics))
+		for i, m := range metrics {
+			values[i] = m.String()
+		}
+
+		r.httpWriter.Header().Add(HeaderServerTiming,
+			strings.Join(values, ", "))
+		return r
+	}
+}
+
+// AddTimingAllowOrigin merges the origins into the Timing-Allow-Origin
+// header: the origins that are allowed to see the detailed timing of
+// the resource (Resource Timing API), e.g. "https://example.com", or
+// "*" for any origin.
+func AddTimingAllowOrigin(origins ...string) Option {
+	return func(r *Response) *Response {
+		mergeHeaderList(r.httpWriter.Header(),
+			HeaderTimingAllowOrigin, origins...)
+		return r
+	}
+}
+
+// WithExposedServerTiming adds the metrics to the Server-Timing header
+// and allows the origins to see them (and the resource timing) with the
+// Timing-Allow-Origin header, since the browsers hide them from the
+// cross-origin pages by default.
+//
+// Example Usage:
+//
+//	resp.JSON(w, data, resp.WithExposedServerTiming(
+//	    []string{"https://app.example.com"},
+//	    resp.ServerTimingMetric{Name: "db", Duration: dbTime}))
+//	// Server-Timing: db;dur=53.2
+//	// Timing-Allow-Origin: https://app.example.com
+func WithExposedServerTiming(
+	origins []string,
+	metrics ...ServerTimingMetric,
+) Option {
+	return func(r *Response) *Response {
+		AddServerTiming(metrics...)(r)
+		return AddTimingAllowOrigin(origins...)(r)
+	}
+}
@@ -0,0 +1,53 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAddServerTiming tests the AddServerTiming function.
+func TestAddServerTiming(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w,
+		AddServerTiming(
+			ServerTimingMetric{Name: "db", Duration: 53200 * time.Microsecond},
+			ServerTimingMetric{Name: "cache", Description: `Hit "L1"`}),
+		AddServerTiming(ServerTimingMetric{Name: "app", Duration: time.Second}),
+		AddServerTiming())
+
+	want := `db;dur=53.2, cache;desc="Hit \"L1\"", app;dur=1000`
+	got := strings.Join(w.Header().Values(HeaderServerTiming), ", ")
+	if got != want {
+		t.Errorf("Server-Timing = %s, want %s", got, want)
+	}
+}
+
+// TestAddTimingAllowOrigin tests the AddTimingAllowOrigin function.
+func TestAddTimingAllowOrigin(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w,
+		AddTimingAllowOrigin("https://a.example.com"),
+		AddTimingAllowOrigin("https://b.example.com", "https://a.example.com"))
+
+	want := "https://a.example.com, https://b.example.com"
+	if got := w.Header().Get(HeaderTimingAllowOrigin); got != want {
+		t.Errorf("Timing-Allow-Origin = %q, want %q", got, want)
+	}
+}
+
+// TestWithExposedServerTiming tests the WithExposedServerTiming function.
+func TestWithExposedServerTiming(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w, WithExposedServerTiming([]string{"*"},
+		ServerTimingMetric{Name: "db", Duration: time.Millisecond}))
+
+	if got := w.Header().Get(HeaderServerTiming); got != "db;dur=1" {
+		t.Errorf("Server-Timing = %q, want %q", got, "db;dur=1")
+	}
+
+	if got := w.Header().Get(HeaderTimingAllowOrigin); got != "*" {
+		t.Errorf("Timing-Allow-Origin = %q, want %q", got, "*")
+	}
+}
@@ -0,0 +1,337 @@
+package resp
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithms of the HTTP message signatures (RFC 9421, 6.2.2).
+const (
+	SignatureAlgHMACSHA256 = "hmac-sha256"
+	SignatureAlgEd25519    = "ed25519"
+)
+
+// DefaultSignatureLabel is the label of the signature
+// if SignatureParams.Label is empty.
+const DefaultSignatureLabel = "sig1"
+
+// ErrSignatureFailed is returned by the response methods
+// if the response can't be signed.
+var ErrSignatureFailed = errors.New("failed to sign response")
+
+// MessageSigner signs the signature base of
+// the HTTP message signature (RFC 9421).
+type MessageSigner interface {
+	// KeyID returns the identifier of the key,
+	// sent as the keyid parameter.
+	KeyID() string
+
+	// Algorithm returns the algorithm of the signature, sent as the
+	// alg parameter, e.g. SignatureAlgEd25519. If it is empty, the
+	// parameter isn't sent and the verifier derives the algorithm
+	// from the key.
+	Algorithm() string
+
+	// Sign returns the signature of the signature base.
+	Sign(base []byte) ([]byte, error)
+}
+
+// SignatureParams are the parameters of the HTTP message signature.
+type SignatureParams struct {
+	// Label is the label of the signature in the Signature and the
+	// Signature-Input headers; DefaultSignatureLabel if empty.
+	Label string
+
+	// Components are the covered components: the lower-case names of
+	// the header fields and the "@status" derived component. If a header
+	// field is missing in the response, the response isn't signed (see
+	// WithSignature). Defaults to "@status", "content-type" if the
+	// response has it, and "content-digest" if the digest is sent (see
+	// WithContentDigest).
+	Components []string
+
+	// Created is the creation time of the signature;
+	// the time of sending the headers if zero.
+	Created time.Time
+
+	// TTL sets the expires parameter to the creation time plus TTL,
+	// if it isn't zero.
+	TTL time.Duration
+
+	// Nonce is the optional nonce of the signature.
+	Nonce string
+
+	// Tag is the optional application-specific tag of the signature,
+	// e.g. the name of the webhook profile.
+	Tag string
+}
+
+// messageSignature is the state of the WithSignature option.
+type messageSignature struct {
+	signer MessageSigner
+	params SignatureParams
+	err    error
+}
+
+// WithSignature signs the response with the HTTP message signature
+// (RFC 9421): the Signature-Input and the Signature headers are added
+// when the headers are sent, so the signature covers the headers set
+// by the response methods. Combine it with WithContentDigest to cover
+// the body: if the content-digest component is covered, the bodies that
+// are otherwise streamed with the digest in the trailers (JSON, JSONP,
+// Stream) are buffered, so the digest is sent in the headers.
+//
+// If the response can't be signed, e.g. a covered header field is
+// missing, it is sent without the signature and the response method
+// returns an error wrapping ErrSignatureFailed.
+//
+// Example Usage:
+//
+//	signer := resp.NewEd25519Signer("webhook-2024", privateKey)
+//
+//	// The JSON body is buffered to send its digest in the headers.
+//	resp.JSON(w, event,
+//	    resp.WithContentDigest(resp.DigestSHA256),
+//	    resp.WithSignature(signer, resp.SignatureParams{
+//	        TTL: 5 * time.Minute,
+//	        Tag: "webhook",
+//	    }))
+//	// Signature-Input: sig1=("@status" "content-type" "content-digest")
+//	//     ;created=1700000000;expires=1700000300
+//	//     ;alg="ed25519";keyid="webhook-2024";tag="webhook"
+//	// Signature: sig1=:...:
+func WithSignature(signer MessageSigner, params SignatureParams) Option {
+	return func(r *Response) *Response {
+		r.signature = &messageSignature{signer: signer, params: params}
+		return r
+	}
+}
+
+// signMessage adds the signature headers. It is called
+// when the status code is sent.
+func (r *Response) signMessage(code int) {
+	s := r.signature
+	if s == nil {
+		return
+	}
+
+	label := s.params.Label
+	if label == "" {
+		label = DefaultSignatureLabel
+	}
+
+	base, input, err := s.base(code, r.httpWriter.Header(),
+		r.signatureComponents(code))
+	if err != nil {
+		s.err = fmt.Errorf("%w: %w", ErrSignatureFailed, err)
+		return
+	}
+
+	sig, err := s.signer.Sign(base)
+	if err != nil {
+		s.err = fmt.Errorf("%w: %w", ErrSignatureFailed, err)
+		return
+	}
+
+	inputValue, err := FormatStructuredField(
+		SFDictionary{{Key: label, Value: input}})
+	if err != nil {
+		s.err = fmt.Errorf("%w: %w", ErrSignatureFailed, err)
+		return
+	}
+
+	sigValue, _ := FormatStructuredField(
+		SFDictionary{{Key: label, Value: sig}})
+
+	header := r.httpWriter.Header()
+	header.Add(HeaderSignatureInput, inputValue)
+	header.Add(HeaderSignature, sigValue)
+}
+
+// signatureComponents returns the components covered by the signature
+// of the response with the status code.
+func (r *Response) signatureComponents(code int) []string {
+	if len(r.signature.params.Components) > 0 {
+		return r.signature.params.Components
+	}
+
+	components := []string{"@status"}
+	if r.httpWriter.Header().Get(HeaderContentType) != "" {
+		components = append(components, "content-type")
+	}
+
+	if r.digest != nil && bodyAllowed(code) {
+		components = append(components, "content-digest")
+	}
+
+	return components
+}
+
+// signsContentDigest reports whether the signature of the response
+// covers the content digest, so the body must be hashed before the
+// headers are sent.
+func (r *Response) signsContentDigest() bool {
+	if r.signature == nil || r.digest == nil {
+		return false
+	}
+
+	if len(r.signature.params.Components) == 0 {
+		return true
+	}
+
+	for _, name := range r.signature.params.Components {
+		if strings.EqualFold(strings.TrimSpace(name), "content-digest") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// base returns the signature base (RFC 9421, 2.5) and the
+// signature parameters: the inner list of the covered components.
+func (s *messageSignature) base(
+	code int,
+	header http.Header,
+	components []string,
+) ([]byte, SFInnerList, error) {
+	var buf []byte
+	var input SFInnerList
+	for _, name := range components {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		var value string
+		switch {
+		case name == "@status":
+			value = strconv.Itoa(code)
+		case strings.HasPrefix(name, "@"):
+			return nil, input, fmt.Errorf(
+				"unsupported derived component %q", name)
+		default:
+			values := header.Values(name)
+			if len(values) == 0 {
+				return nil, input, fmt.Errorf(
+					"missing component %q", name)
+			}
+			value = signatureFieldValue(values)
+		}
+
+		var err error
+		if buf, err = appendSFString(buf, name); err != nil {
+			return nil, input, err
+		}
+		buf = append(buf, ": "+value+"\n"...)
+		input.Items = append(input.Items, SFItem{Value: name})
+	}
+
+	created := s.params.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	input.Params = SFParams{{Key: "created", Value: created.Unix()}}
+	if s.params.TTL != 0 {
+		input.Params = append(input.Params, SFParam{
+			Key:   "expires",
+			Value: created.Add(s.params.TTL).Unix(),
+		})
+	}
+
+	if s.params.Nonce != "" {
+		input.Params = append(input.Params,
+			SFParam{Key: "nonce", Value: s.params.Nonce})
+	}
+
+	if alg := s.signer.Algorithm(); alg != "" {
+		input.Params = append(input.Params,
+			SFParam{Key: "alg", Value: alg})
+	}
+
+	if keyID := s.signer.KeyID(); keyID != "" {
+		input.Params = append(input.Params,
+			SFParam{Key: "keyid", Value: keyID})
+	}
+
+	if s.params.Tag != "" {
+		input.Params = append(input.Params,
+			SFParam{Key: "tag", Value: s.params.Tag})
+	}
+
+	buf = append(buf, `"@signature-params": `...)
+	buf, err := input.appendSF(buf)
+	if err != nil {
+		return nil, input, err
+	}
+
+	return buf, input, nil
+}
+
+// signatureFieldValue returns the value of the header field
+// in the signature base: the trimmed values joined with ", ".
+func signatureFieldValue(values []string) string {
+	trimmed := make([]string, len(values))
+	for i, v := range values {
+		trimmed[i] = strings.TrimSpace(v)
+	}
+
+	return strings.Join(trimmed, ", ")
+}
+
+// hmacSigner is the MessageSigner with HMAC-SHA256.
+type hmacSigner struct {
+	keyID string
+	key   []byte
+}
+
+// NewHMACSigner returns the MessageSigner that signs the responses
+// with HMAC-SHA256 and the shared key.
+func NewHMACSigner(keyID string, key []byte) MessageSigner {
+	return &hmacSigner{keyID: keyID, key: key}
+}
+
+// KeyID returns the identifier of the key.
+func (s *hmacSigner) KeyID() string { return s.keyID }
+
+// Algorithm returns SignatureAlgHMACSHA256.
+func (s *hmacSigner) Algorithm() string { return SignatureAlgHMACSHA256 }
+
+// Sign returns the HMAC-SHA256 of the signature base.
+func (s *hmacSigner) Sign(base []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(base)
+	return mac.Sum(nil), nil
+}
+
+// ed25519Signer is the MessageSigner with Ed25519.
+type ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns the MessageSigner that signs
+// the responses with Ed25519 and the private key.
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) MessageSigner {
+	return &ed25519Signer{keyID: keyID, key: key}
+}
+
+// KeyID returns the identifier of the key.
+func (s *ed25519Signer) KeyID() string { return s.keyID }
+
+// Algorithm returns SignatureAlgEd25519.
+func (s *ed25519Signer) Algorithm() string { return SignatureAlgEd25519 }
+
+// Sign returns the Ed25519 signature of the signature base.
+func (s *ed25519Signer) Sign(base []byte) ([]byte, error) {
+	if len(s.key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid Ed25519 private key")
+	}
+
+	return ed25519.Sign(s.key, base), nil
+}
@@ -0,0 +1,212 @@
+package resp
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingSigner is a MessageSigner that fails to sign.
+type failingSigner struct{}
+
+// KeyID returns an empty key identifier.
+func (failingSigner) KeyID() string { return "" }
+
+// Algorithm returns an empty algorithm.
+func (failingSigner) Algorithm() string { return "" }
+
+// Sign returns an error.
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errors.New("no key")
+}
+
+// TestWithSignature tests the WithSignature option.
+func TestWithSignature(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	key := []byte("secret")
+
+	t.Run("HMAC", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := String(w, "ok",
+			WithContentDigest(DigestSHA256),
+			WithSignature(NewHMACSigner("key-1", key), SignatureParams{
+				Created: created,
+				TTL:     time.Minute,
+				Tag:     "webhook",
+			}))
+		if err != nil {
+			t.Fatalf("String() error = %v", err)
+		}
+
+		params := `("@status" "content-type" "content-digest")` +
+			`;created=1700000000;expires=1700000060` +
+			`;alg="hmac-sha256";keyid="key-1";tag="webhook"`
+		if got := w.Header().Get(HeaderSignatureInput); got != "sig1="+params {
+			t.Errorf("Signature-Input = %q, want %q", got, "sig1="+params)
+		}
+
+		base := `"@status": 200` + "\n" +
+			`"content-type": ` + MIMETextPlain + "\n" +
+			`"content-digest": ` + w.Header().Get(HeaderContentDigest) +
+			"\n" + `"@signature-params": ` + params
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(base))
+		want := "sig1=:" +
+			base64.StdEncoding.EncodeToString(mac.Sum(nil)) + ":"
+		if got := w.Header().Get(HeaderSignature); got != want {
+			t.Errorf("Signature = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		err = String(w, "ok",
+			WithStatusAccepted(),
+			WithHeader("X-Event", "created"),
+			WithHeader("X-Event", " updated "),
+			WithSignature(NewEd25519Signer("key-2", priv), SignatureParams{
+				Label:      "resp",
+				Components: []string{"@status", "X-Event"},
+				Created:    created,
+				Nonce:      "abc",
+			}))
+		if err != nil {
+			t.Fatalf("String() error = %v", err)
+		}
+
+		params := `("@status" "x-event");created=1700000000` +
+			`;nonce="abc";alg="ed25519";keyid="key-2"`
+		if got := w.Header().Get(HeaderSignatureInput); got != "resp="+params {
+			t.Fatalf("Signature-Input = %q, want %q", got, "resp="+params)
+		}
+
+		base := `"@status": 202` + "\n" +
+			`"x-event": created, updated` + "\n" +
+			`"@signature-params": ` + params
+
+		value := w.Header().Get(HeaderSignature)
+		encoded := strings.TrimSuffix(strings.TrimPrefix(value, "resp=:"), ":")
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			t.Fatalf("Signature = %q: %v", value, err)
+		}
+
+		if !ed25519.Verify(pub, []byte(base), sig) {
+			t.Errorf("Signature = %q isn't valid", value)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			signer MessageSigner
+			params SignatureParams
+		}{
+			{"Signer", failingSigner{}, SignatureParams{}},
+			{
+				"Component",
+				NewHMACSigner("key", key),
+				SignatureParams{Components: []string{"@method"}},
+			},
+			{
+				"Missing component",
+				NewHMACSigner("key", key),
+				SignatureParams{Components: []string{"x-missing"}},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				err := String(w, "ok", WithSignature(tt.signer, tt.params))
+				if !errors.Is(err, ErrSignatureFailed) {
+					t.Errorf("String() error = %v, want %v",
+						err, ErrSignatureFailed)
+				}
+
+				if w.Body.String() != "ok" {
+					t.Errorf("body = %q, want %q", w.Body.String(), "ok")
+				}
+
+				if v := w.Header().Get(HeaderSignature); v != "" {
+					t.Errorf("Signature = %q, want empty", v)
+				}
+			})
+		}
+	})
+}
+
+// TestWithSignature_StreamedDigest tests that the content digest of
+// the streamed bodies is sent in the headers and covered by the
+// signature.
+func TestWithSignature_StreamedDigest(t *testing.T) {
+	signer := NewHMACSigner("key", []byte("secret"))
+	tests := []struct {
+		name  string
+		serve func(w *httptest.ResponseRecorder, opts ...Option) error
+		body  string
+	}{
+		{
+			name: "JSON",
+			serve: func(w *httptest.ResponseRecorder, opts ...Option) error {
+				return JSON(w, R{"ok": true}, opts...)
+			},
+			body: "{\"ok\":true}\n",
+		},
+		{
+			name: "JSONP",
+			serve: func(w *httptest.ResponseRecorder, opts ...Option) error {
+				return JSONP(w, R{"ok": true}, "cb", opts...)
+			},
+			body: `cb({"ok":true});`,
+		},
+		{
+			name: "Stream",
+			serve: func(w *httptest.ResponseRecorder, opts ...Option) error {
+				return Stream(w, strings.NewReader("abc"), opts...)
+			},
+			body: "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := tt.serve(w, WithContentDigest(DigestSHA256),
+				WithSignature(signer, SignatureParams{}))
+			if err != nil {
+				t.Fatalf("serve error = %v", err)
+			}
+
+			if w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+
+			res := w.Result()
+			if got := res.Header.Get(HeaderTrailer); got != "" {
+				t.Errorf("Trailer = %q, want none", got)
+			}
+
+			got := res.Header.Get(HeaderContentDigest)
+			if want := sha256Digest(tt.body); got != want {
+				t.Errorf("Content-Digest = %q, want %q", got, want)
+			}
+
+			input := res.Header.Get(HeaderSignatureInput)
+			if !strings.Contains(input, `"content-digest"`) {
+				t.Errorf("Signature-Input = %q, want content-digest", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package resp
+
+import (
+	"net/http"
+	"sync"
+)
+
+// flightCall is the handler execution shared by the identical requests.
+type flightCall struct {
+	done chan struct{}
+	res  *CachedResponse // nil if the response can't be shared
+}
+
+// SingleFlight returns a middleware that coalesces the concurrent
+// identical GET requests (with the same key, see CacheKeyFunc; by
+// default the host and the URL of the request): the handler is executed
+// once, and the requests that arrive while it runs wait and receive the
+// same status code, headers and body. It protects the hot endpoints
+// from the stampedes, e.g. when the cached response expires.
+//
+// Only the complete 200 (OK) responses are shared. The response isn't
+// shared if it is personal (the Set-Cookie header, the private or
+// no-store directives of the Cache-Control header) or has the
+// "Vary: *" header; then the waiting requests execute the handler
+// themselves. The conditional requests and the requests with the Range
+// header are never coalesced, since their responses (e.g. 304 or 206)
+// depend on them. The waiting request returns without a response if
+// its context is canceled.
+//
+// If the response has the Vary header (e.g. set by CORS or Negotiate),
+// it is sent only to the waiting requests with the same values of the
+// request headers it names. The other waiting requests are coalesced
+// again by these values, so each variant is produced once.
+//
+// With the default key, the requests with the credentials (the
+// Authorization or Cookie header) aren't coalesced, since the response
+// of one user must not be sent to another. A custom keyFunc that
+// includes the identity of the user (e.g. the session ID) enables the
+// coalescing of such requests per user.
+//
+// Example Usage:
+//
+//	hot := resp.SingleFlight(nil)
+//	cache := resp.Cache(time.Minute, nil)
+//	mux.Handle("/stats", cache(hot(statsHandler)))
+func SingleFlight(keyFunc CacheKeyFunc) func(http.Handler) http.Handler {
+	if keyFunc == nil {
+		keyFunc = anonymousCacheKey
+	}
+
+	var mu sync.Mutex
+	calls := make(map[string]*flightCall)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				key := ""
+				if r.Method == http.MethodGet && !conditionalRequest(r) {
+					key = keyFunc(r)
+				}
+
+				if key == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				// The request waits for the call of its key, and once
+				// more for the call of its variant if the response
+				// varies by the request headers.
+				for variant := false; ; variant = true {
+					mu.Lock()
+					c, ok := calls[key]
+					if !ok {
+						break
+					}
+					mu.Unlock()
+
+					select {
+					case <-c.done:
+					case <-r.Context().Done():
+						return
+					}
+
+					if c.res == nil {
+						next.ServeHTTP(w, r)
+						return
+					}
+
+					if varyMatch(r, c.res) {
+						writeStored(w, r, c.res)
+						return
+					}
+
+					if variant {
+						next.ServeHTTP(w, r)
+						return
+					}
+					key += varyKey(r, c.res.Header)
+				}
+
+				c := &flightCall{done: make(chan struct{})}
+				calls[key] = c
+				mu.Unlock()
+
+				// The waiting requests are released
+				// even if the handler panics.
+				defer func() {
+					mu.Lock()
+					delete(calls, key)
+					mu.Unlock()
+					close(c.done)
+				}()
+
+				cw := &cacheWriter{ResponseWriter: w}
+				next.ServeHTTP(cw, r)
+				if cw.status == StatusOK && cw.shareable() {
+					c.res = cw.response()
+					c.res.RequestHeader = varyRequestHeader(r, c.res.Header)
+				}
+			})
+	}
+}
+
+// conditionalRequest reports whether the request has the preconditions
+// or the Range header, so its response depends on them.
+func conditionalRequest(r *http.Request) bool {
+	for _, name := range []string{
+		HeaderIfMatch,
+		HeaderIfNoneMatch,
+		HeaderIfModifiedSince,
+		HeaderIfUnmodifiedSince,
+		HeaderIfRange,
+		HeaderRange,
+	} {
+		if r.Header.Get(name) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// anonymousCacheKey is like defaultCacheKey, but returns the empty key
+// for the requests with the credentials.
+func anonymousCacheKey(r *http.Request) string {
+	if r.Header.Get(HeaderAuthorization) != "" ||
+		r.Header.Get(HeaderCookie) != "" {
+		return ""
+	}
+
+	return defaultCacheKey(r)
+}
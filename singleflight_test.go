@@ -0,0 +1,183 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSingleFlight tests that the concurrent identical requests
+// share one execution of the handler.
+func TestSingleFlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		header    string // the header of the requests
+		wantCode  int    // StatusOK if zero
+		wantCalls int32
+	}{
+		{name: "Shared", wantCalls: 1},
+		{
+			name:      "Personal",
+			opts:      []Option{AddCacheControl("private")},
+			wantCalls: 4,
+		},
+		{name: "Authorization", header: HeaderAuthorization, wantCalls: 4},
+		{name: "Cookie", header: HeaderCookie, wantCalls: 4},
+		{name: "Conditional", header: HeaderIfNoneMatch, wantCalls: 4},
+		{name: "Range", header: HeaderRange, wantCalls: 4},
+		{
+			name:      "Not OK",
+			opts:      []Option{WithStatusAccepted()},
+			wantCode:  StatusAccepted,
+			wantCalls: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			entered := make(chan struct{})
+			release := make(chan struct{})
+
+			h := SingleFlight(nil)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if calls.Add(1) == 1 {
+						close(entered)
+						<-release
+					}
+					String(w, "stats", tt.opts...)
+				}))
+
+			send := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest("GET", "/stats", nil)
+				if tt.header != "" {
+					req.Header.Set(tt.header, "value")
+				}
+
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, req)
+				return w
+			}
+
+			var wg sync.WaitGroup
+			results := make([]*httptest.ResponseRecorder, 4)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[0] = send()
+			}()
+			<-entered
+
+			for i := 1; i < len(results); i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i] = send()
+				}(i)
+			}
+
+			// Let the requests reach the middleware before
+			// the first handler execution completes.
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+
+			wantCode := tt.wantCode
+			if wantCode == 0 {
+				wantCode = StatusOK
+			}
+
+			for i, w := range results {
+				if w.Code != wantCode || w.Body.String() != "stats" {
+					t.Errorf("response %d = %d %q, want %d %q",
+						i, w.Code, w.Body.String(), wantCode, "stats")
+				}
+			}
+		})
+	}
+}
+
+// TestSingleFlight_Methods tests that only the GET
+// requests are coalesced.
+func TestSingleFlight_Methods(t *testing.T) {
+	var calls atomic.Int32
+	h := SingleFlight(nil)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			NoContent(w)
+		}))
+
+	for _, method := range []string{"POST", "HEAD", "GET"} {
+		h.ServeHTTP(httptest.NewRecorder(),
+			httptest.NewRequest(method, "/", nil))
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("handler calls = %d, want 3", got)
+	}
+}
+
+// TestSingleFlight_Vary tests that the waiting requests receive
+// the response only if they match its Vary header.
+func TestSingleFlight_Vary(t *testing.T) {
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	h := SingleFlight(nil)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				close(entered)
+				<-release
+			}
+			w.Header().Set(HeaderVary, "Origin")
+			String(w, r.Header.Get("Origin"))
+		}))
+
+	origins := []string{"a", "a", "b", "b"}
+	results := make([]*httptest.ResponseRecorder, len(origins))
+	send := func(i int) {
+		req := httptest.NewRequest("GET", "/stats", nil)
+		req.Header.Set("Origin", origins[i])
+		results[i] = httptest.NewRecorder()
+		h.ServeHTTP(results[i], req)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		send(0)
+	}()
+	<-entered
+
+	for i := 1; i < len(origins); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			send(i)
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, w := range results {
+		if got := w.Body.String(); got != origins[i] {
+			t.Errorf("response %d = %q, want %q", i, got, origins[i])
+		}
+	}
+
+	if got := calls.Load(); got < 2 || got > 3 {
+		t.Errorf("handler calls = %d, want 2 or 3", got)
+	}
+}
@@ -0,0 +1,107 @@
+package resp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// StaticJSON is a JSON response encoded once, e.g. at startup, for the
+// endpoints whose data never changes per request (configuration,
+// metadata, capabilities). The encoded bytes are sent as is, with the
+// Content-Length header and the strong ETag computed from the bytes.
+// It is safe for concurrent use.
+//
+// Since the data is encoded in advance, the options that change the
+// JSON body (e.g. WithMeta, WithLinks, WithView) have no effect.
+type StaticJSON struct {
+	data []byte
+	etag string
+}
+
+// NewStatic encodes the value as JSON and returns the StaticJSON.
+// The error wraps ErrEncodingFailed if the value can't be encoded.
+func NewStatic(v any) (*StaticJSON, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, &encodingError{
+			context: "failed to encode static JSON response",
+			err:     err,
+		}
+	}
+	data = append(data, '\n')
+
+	return &StaticJSON{data: data, etag: ETagStrong(data)}, nil
+}
+
+// Static is like NewStatic but panics if the value can't be encoded.
+// It simplifies the initialization of the package-level variables.
+//
+// Example Usage:
+//
+//	var version = resp.Static(resp.R{"version": "1.4.2", "go": "1.22"})
+//
+//	func main() {
+//	    http.Handle("/version", version)
+//	    // or, in a handler: version.Send(w, resp.WithStatusOK())
+//	}
+func Static(v any) *StaticJSON {
+	s, err := NewStatic(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Send sends the encoded JSON with the options applied.
+// If the status code isn't set - StatusOK will be set.
+func (s *StaticJSON) Send(w http.ResponseWriter, opts ...Option) error {
+	return NewResponse(w, opts...).writeStatic(s, nil)
+}
+
+// ServeHTTP sends the encoded JSON. The 304 (Not Modified) response is
+// sent if the If-None-Match header of the GET or HEAD request matches
+// the ETag, and the body is omitted for the HEAD requests.
+func (s *StaticJSON) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	NewResponse(w).writeStatic(s, r)
+}
+
+// Bytes returns a copy of the encoded JSON.
+func (s *StaticJSON) Bytes() []byte {
+	return append([]byte(nil), s.data...)
+}
+
+// ETag returns the strong ETag of the encoded JSON.
+func (s *StaticJSON) ETag() string {
+	return s.etag
+}
+
+// writeStatic sends the encoded JSON. If the request is provided,
+// the conditional and HEAD requests are handled.
+func (r *Response) writeStatic(s *StaticJSON, req *http.Request) (err error) {
+	defer r.finish(&err)
+
+	header := r.httpWriter.Header()
+	header.Set(HeaderETag, s.etag)
+	r.prepare(StatusOK, MIMEApplicationJSONCharsetUTF8)
+
+	safe := req != nil &&
+		(req.Method == http.MethodGet || req.Method == http.MethodHead)
+	if safe && r.statusCode == StatusOK {
+		inm := req.Header.Get(HeaderIfNoneMatch)
+		if inm != "" && etagMatch(inm, s.etag) {
+			return r.writeNotModified()
+		}
+	}
+
+	header.Set(HeaderContentLength, strconv.Itoa(len(s.data)))
+	r.setBodyDigest(s.data)
+	r.writeHeader(r.statusCode)
+	if req != nil && req.Method == http.MethodHead {
+		return nil
+	}
+
+	_, err = r.write(s.data)
+	return err
+}
@@ -0,0 +1,86 @@
+package resp
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// TestStatic tests the Static function.
+func TestStatic(t *testing.T) {
+	s := Static(R{"version": "1.4.2"})
+	want := `{"version":"1.4.2"}` + "\n"
+
+	tests := []struct {
+		name       string
+		method     string
+		inm        string
+		wantStatus int
+		want       string
+	}{
+		{"GET", "GET", "", StatusOK, want},
+		{"HEAD", "HEAD", "", StatusOK, ""},
+		{"Not modified", "GET", s.ETag(), StatusNotModified, ""},
+		{"Changed", "GET", `"old"`, StatusOK, want},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/version", nil)
+			if tt.inm != "" {
+				req.Header.Set(HeaderIfNoneMatch, tt.inm)
+			}
+
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+
+			if got := w.Header().Get(HeaderETag); got != s.ETag() {
+				t.Errorf("ETag = %q, want %q", got, s.ETag())
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	if err := s.Send(w, WithStatusCreated()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if w.Code != StatusCreated || w.Body.String() != want {
+		t.Errorf("Send() = %d %q, want %d %q",
+			w.Code, w.Body.String(), StatusCreated, want)
+	}
+
+	length := strconv.Itoa(len(want))
+	if got := w.Header().Get(HeaderContentLength); got != length {
+		t.Errorf("Content-Length = %q, want %q", got, length)
+	}
+
+	if string(s.Bytes()) != want {
+		t.Errorf("Bytes() = %q, want %q", s.Bytes(), want)
+	}
+}
+
+// TestNewStatic_Error tests that NewStatic returns the encoding error
+// and Static panics.
+func TestNewStatic_Error(t *testing.T) {
+	_, err := NewStatic(R{"fn": func() {}})
+	if !errors.Is(err, ErrEncodingFailed) {
+		t.Errorf("NewStatic() error = %v, want %v", err, ErrEncodingFailed)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Static() didn't panic")
+		}
+	}()
+	Static(R{"fn": func() {}})
+}
@@ -0,0 +1,60 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInvalidStatus is returned (wrapped) by the response methods when
+// the status code set with SetStatus (or WithStatus) is outside the
+// 100-599 range.
+var ErrInvalidStatus = errors.New("invalid status code")
+
+// StatusText returns the text of the status code, e.g. "Not Found".
+// The package messages are used first, and http.StatusText for the
+// codes the package doesn't know. The empty string is returned for
+// unknown codes.
+func StatusText(code int) string {
+	if text, ok := statusMessages[code]; ok {
+		return text
+	}
+
+	return http.StatusText(code)
+}
+
+// IsInformational reports whether the status code is 1xx.
+func IsInformational(code int) bool {
+	return code >= 100 && code < 200
+}
+
+// IsSuccess reports whether the status code is 2xx.
+func IsSuccess(code int) bool {
+	return code >= 200 && code < 300
+}
+
+// IsRedirect reports whether the status code is 3xx.
+func IsRedirect(code int) bool {
+	return code >= 300 && code < 400
+}
+
+// IsClientError reports whether the status code is 4xx.
+func IsClientError(code int) bool {
+	return code >= 400 && code < 500
+}
+
+// IsServerError reports whether the status code is 5xx.
+func IsServerError(code int) bool {
+	return code >= 500 && code < 600
+}
+
+// validStatus reports whether the status code can be sent:
+// net/http panics on the codes outside the 100-599 range.
+func validStatus(code int) bool {
+	return code >= 100 && code <= 599
+}
+
+// invalidStatus returns the error of the invalid status code.
+func invalidStatus(code int) error {
+	return fmt.Errorf("%w: %d", ErrInvalidStatus, code)
+}
@@ -0,0 +1,86 @@
+package resp
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStatusText tests the StatusText function.
+func TestStatusText(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{StatusOK, "OK"},
+		{StatusTeapot, "I'm a teapot"},
+		{StatusNetworkAuthenticationRequired,
+			"Network Authentication Required"},
+		{599, ""},
+	}
+
+	for _, tt := range tests {
+		if got := StatusText(tt.code); got != tt.want {
+			t.Errorf("StatusText(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+// TestStatusPredicates tests the status code predicates.
+func TestStatusPredicates(t *testing.T) {
+	predicates := []struct {
+		name string
+		f    func(int) bool
+		want []int
+	}{
+		{"IsInformational", IsInformational, []int{100, 199}},
+		{"IsSuccess", IsSuccess, []int{200, 299}},
+		{"IsRedirect", IsRedirect, []int{300, 399}},
+		{"IsClientError", IsClientError, []int{400, 499}},
+		{"IsServerError", IsServerError, []int{500, 599}},
+	}
+
+	for _, p := range predicates {
+		for _, code := range []int{0, 99, 100, 199, 200, 299, 300, 399,
+			400, 499, 500, 599, 600} {
+			want := code >= p.want[0] && code <= p.want[1]
+			if got := p.f(code); got != want {
+				t.Errorf("%s(%d) = %v, want %v", p.name, code, got, want)
+			}
+		}
+	}
+}
+
+// TestSetStatusInvalid tests that the invalid status codes are
+// rejected and the error is returned by the response method.
+func TestSetStatusInvalid(t *testing.T) {
+	for _, code := range []int{-1, 99, 600, 1000} {
+		w := httptest.NewRecorder()
+
+		var info ResponseInfo
+		err := JSON(w, R{"ok": true}, WithStatus(code),
+			WithAfterWrite(func(i ResponseInfo) { info = i }))
+		if !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("JSON() with status %d error = %v, want %v",
+				code, err, ErrInvalidStatus)
+		}
+
+		if !errors.Is(info.Err, ErrInvalidStatus) {
+			t.Errorf("ResponseInfo.Err = %v, want %v",
+				info.Err, ErrInvalidStatus)
+		}
+
+		if w.Code != StatusOK {
+			t.Errorf("status = %d, want %d", w.Code, StatusOK)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	err := NewResponse(w).
+		SetStatus(StatusCreated).
+		SetStatus(StatusUndefined).
+		String("ok")
+	if err != nil || w.Code != StatusOK {
+		t.Errorf("String() error = %v, status = %d", err, w.Code)
+	}
+}
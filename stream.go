@@ -0,0 +1,91 @@
+package resp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// ProgressFunc is called with the number of the body bytes
+// written so far. See the WithProgress option.
+type ProgressFunc func(written int64)
+
+// WithProgress sets the function that is called after each chunk of
+// the body is written (e.g. every 32KB of a stream), with the number of
+// the body bytes written so far, so the delivery of large responses can
+// be observed. The function is called synchronously, so it must be fast.
+//
+// The zero-copy fast path (sendfile) of the writer is
+// disabled to report the progress of the copy.
+//
+// Example Usage:
+//
+//	progress := func(written int64) {
+//	    deliveredBytes.Set(float64(written))
+//	}
+//
+//	resp.StreamN(w, video, size, resp.WithProgress(progress))
+func WithProgress(f ProgressFunc) Option {
+	return func(r *Response) *Response {
+		r.progress = f
+		return r
+	}
+}
+
+// StreamN streams the n bytes of the reader to the client, with the
+// Content-Length header set to n, so the client knows the size of the
+// response and can show the progress. If the reader ends earlier, an
+// error wrapping io.ErrUnexpectedEOF is returned; the remaining bytes
+// of a longer reader aren't read.
+//
+// If n is negative, the size is unknown and the reader is streamed to
+// the end, as with Stream. The Content-Length isn't set if the text is
+// transformed (see WithBOM and WithTextEncoding).
+//
+// Example Usage:
+//
+//	func Handler(w http.ResponseWriter, r *http.Request) {
+//	    obj, size, _ := bucket.Open(ctx, "media/intro.mp4")
+//	    defer obj.Close()
+//
+//	    resp.StreamN(w, obj, size, resp.AddContentType("video/mp4"))
+//	}
+func StreamN(
+	w http.ResponseWriter,
+	reader io.Reader,
+	n int64,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).StreamN(reader, n)
+}
+
+// StreamN streams the n bytes of the reader with the Content-Length.
+// See the StreamN function for details.
+func (r *Response) StreamN(data io.Reader, n int64) (err error) {
+	defer r.finish(&err)
+
+	var limited *io.LimitedReader
+	if n >= 0 {
+		limited = &io.LimitedReader{R: data, N: n}
+		data = limited
+		if !r.bom && r.textEncoding == nil {
+			r.httpWriter.Header().Set(HeaderContentLength,
+				strconv.FormatInt(n, 10))
+		}
+	}
+
+	r.prepare(StatusOK, MIMEOctetStream)
+	r.writeHeader(r.statusCode)
+
+	if err := r.writeText(data); err != nil {
+		return err
+	}
+
+	if limited != nil && limited.N > 0 {
+		return fmt.Errorf("stream is shorter than %d bytes: %w",
+			n, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestStreamN tests the StreamN function.
+func TestStreamN(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		n          int64
+		wantLength string
+		wantBody   string
+		wantErr    error
+	}{
+		{
+			name:       "Exact size",
+			data:       "0123456789",
+			n:          10,
+			wantLength: "10",
+			wantBody:   "0123456789",
+		},
+		{
+			name:       "Reader is longer",
+			data:       "0123456789",
+			n:          4,
+			wantLength: "4",
+			wantBody:   "0123",
+		},
+		{
+			name:       "Reader is shorter",
+			data:       "01",
+			n:          4,
+			wantLength: "4",
+			wantBody:   "01",
+			wantErr:    io.ErrUnexpectedEOF,
+		},
+		{
+			name:     "Unknown size",
+			data:     "0123456789",
+			n:        -1,
+			wantBody: "0123456789",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := StreamN(w, strings.NewReader(tt.data), tt.n)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("StreamN() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got := w.Header().Get(HeaderContentLength)
+			if got != tt.wantLength {
+				t.Errorf("Content-Length = %q, want %q", got, tt.wantLength)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("StreamN() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestWithProgress tests that the progress is reported for each chunk,
+// also when the writer has the ReadFrom fast path.
+func TestWithProgress(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 80*1024)
+	w := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	var progress []int64
+	err := StreamN(w, bytes.NewReader(data), int64(len(data)),
+		WithProgress(func(written int64) {
+			progress = append(progress, written)
+		}))
+	if err != nil {
+		t.Fatalf("StreamN() error = %v", err)
+	}
+
+	want := []int64{32 * 1024, 64 * 1024, 80 * 1024}
+	if len(progress) != len(want) {
+		t.Fatalf("progress = %v, want %v", progress, want)
+	}
+	for i := range want {
+		if progress[i] != want[i] {
+			t.Errorf("progress = %v, want %v", progress, want)
+			break
+		}
+	}
+
+	if w.used || w.Body.Len() != len(data) {
+		t.Errorf("ReadFrom used = %v, body length = %d", w.used, w.Body.Len())
+	}
+}
@@ -0,0 +1,168 @@
+package resp
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldInfo is the cached metadata of a struct field.
+type fieldInfo struct {
+	name      string // name of the field in Go
+	index     int    // index of the field in the struct
+	jsonName  string // name of the field in the JSON output
+	jsonSkip  bool   // the field is tagged with `json:"-"`
+	omitEmpty bool   // the field has the omitempty option
+	field     reflect.StructField
+	path      []int // index sequence of a promoted field
+}
+
+// structInfo is the cached metadata of a struct type.
+type structInfo struct {
+	fields []fieldInfo    // exported fields, in the declaration order
+	byName map[string]int // index in fields by the name of the field
+
+	// flat are the fields with the fields of the embedded structs
+	// promoted, and flatByName is the index in flat by the name.
+	flat       []fieldInfo
+	flatByName map[string]int
+}
+
+// structInfoCache maps reflect.Type to *structInfo.
+var structInfoCache sync.Map
+
+// cachedStructInfo returns the metadata of the struct type. The fields
+// are reflected once per type, so processing slices of structs doesn't
+// re-reflect the type for every element.
+func cachedStructInfo(rt reflect.Type) *structInfo {
+	if info, ok := structInfoCache.Load(rt); ok {
+		return info.(*structInfo)
+	}
+
+	info := &structInfo{byName: make(map[string]int, rt.NumField())}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonName, omitEmpty, ok := jsonFieldName(field)
+		info.byName[field.Name] = len(info.fields)
+		info.fields = append(info.fields, fieldInfo{
+			name:      field.Name,
+			index:     i,
+			jsonName:  jsonName,
+			jsonSkip:  !ok,
+			omitEmpty: omitEmpty,
+			field:     field,
+		})
+	}
+
+	info.flat = promotedFields(rt)
+	info.flatByName = make(map[string]int, len(info.flat))
+	for i, field := range info.flat {
+		info.flatByName[field.name] = i
+	}
+
+	actual, _ := structInfoCache.LoadOrStore(rt, info)
+	return actual.(*structInfo)
+}
+
+// promotedFields returns the exported fields of the struct type with
+// the fields of the embedded structs promoted, as encoding/json treats
+// anonymous fields: an exported embedded struct (or a pointer to it)
+// without a JSON name in the tag is replaced with its fields, the
+// shallower field hides the deeper ones, and the ambiguous fields of
+// the same depth are dropped.
+func promotedFields(rt reflect.Type) []fieldInfo {
+	type candidate struct {
+		info  fieldInfo
+		depth int
+	}
+
+	var candidates []candidate
+	visited := make(map[reflect.Type]bool)
+
+	var walk func(rt reflect.Type, path []int)
+	walk = func(rt reflect.Type, path []int) {
+		visited[rt] = true
+		defer delete(visited, rt)
+
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			index := append(append([]int(nil), path...), i)
+			jsonName, omitEmpty, ok := jsonFieldName(field)
+
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			tagName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.Anonymous && ft.Kind() == reflect.Struct &&
+				ok && tagName == "" && !visited[ft] {
+				walk(ft, index)
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				info: fieldInfo{
+					name:      field.Name,
+					index:     i,
+					jsonName:  jsonName,
+					jsonSkip:  !ok,
+					omitEmpty: omitEmpty,
+					field:     field,
+					path:      index,
+				},
+				depth: len(path),
+			})
+		}
+	}
+	walk(rt, nil)
+
+	// The shallowest depth of each name and the number of the fields
+	// of this depth.
+	depth := make(map[string]int)
+	count := make(map[string]int)
+	for _, c := range candidates {
+		d, ok := depth[c.info.name]
+		switch {
+		case !ok || c.depth < d:
+			depth[c.info.name] = c.depth
+			count[c.info.name] = 1
+		case c.depth == d:
+			count[c.info.name]++
+		}
+	}
+
+	var fields []fieldInfo
+	for _, c := range candidates {
+		name := c.info.name
+		if depth[name] == c.depth && count[name] == 1 {
+			fields = append(fields, c.info)
+		}
+	}
+
+	return fields
+}
+
+// fieldByPath returns the field of the struct value by the index
+// sequence, and false if it goes through a nil embedded pointer.
+func fieldByPath(rv reflect.Value, path []int) (reflect.Value, bool) {
+	for i, index := range path {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(index)
+	}
+
+	return rv, true
+}
@@ -0,0 +1,367 @@
+package resp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidStructuredField is returned by FormatStructuredField
+// if the value can't be serialized as the structured field.
+var ErrInvalidStructuredField = errors.New("invalid structured field")
+
+const (
+	// sfMaxInteger is the maximum absolute value
+	// of the structured field integer.
+	sfMaxInteger = 999_999_999_999_999
+
+	// sfMaxDecimal is the maximum absolute value of the integer
+	// component of the structured field decimal.
+	sfMaxDecimal = 999_999_999_999
+)
+
+// StructuredField is the value of the Structured Field header (RFC 8941,
+// RFC 9651): SFItem, SFList or SFDictionary.
+type StructuredField interface {
+	appendSF(b []byte) ([]byte, error)
+}
+
+// SFToken is the token of the structured field, e.g. the bare "self"
+// in the Permissions-Policy header; the Go strings are serialized as
+// the quoted strings.
+type SFToken string
+
+// SFParam is the parameter of the structured field item or inner list.
+// The value is a bare item; the parameter with the true value is
+// serialized as the bare key.
+type SFParam struct {
+	Key   string
+	Value any
+}
+
+// SFParams is the ordered list of the parameters.
+type SFParams []SFParam
+
+// SFItem is the item of the structured field: the bare item with the
+// parameters. The bare item is one of:
+//   - int, int64 (the integer),
+//   - float64 (the decimal),
+//   - string (the string of the printable ASCII characters),
+//   - SFToken (the token),
+//   - []byte (the byte sequence),
+//   - bool (the boolean),
+//   - time.Time (the date, with the precision of seconds).
+type SFItem struct {
+	Value  any
+	Params SFParams
+}
+
+// SFInnerList is the inner list of the items with the parameters,
+// the member of SFList or SFDictionary.
+type SFInnerList struct {
+	Items  []SFItem
+	Params SFParams
+}
+
+// SFList is the list structured field. The members are SFItem or
+// SFInnerList; the bare items are serialized as the items without
+// the parameters.
+type SFList []any
+
+// SFDictMember is the member of the dictionary. The value is SFItem,
+// SFInnerList or the bare item; the member with the true value is
+// serialized as the bare key (with the parameters, if any).
+type SFDictMember struct {
+	Key   string
+	Value any
+}
+
+// SFDictionary is the ordered dictionary structured field.
+type SFDictionary []SFDictMember
+
+// FormatStructuredField returns the structured field serialized as the
+// header value. It returns an error wrapping ErrInvalidStructuredField
+// if any value can't be serialized, e.g. the string with the non-ASCII
+// characters or the key with the upper-case letters.
+//
+// Example Usage:
+//
+//	value, err := resp.FormatStructuredField(resp.SFDictionary{
+//	    {Key: "u", Value: 5},
+//	    {Key: "i", Value: true},
+//	})
+//	// u=5, i
+func FormatStructuredField(f StructuredField) (string, error) {
+	b, err := f.appendSF(nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidStructuredField, err)
+	}
+
+	return string(b), nil
+}
+
+// WithStructuredField sets the header to the serialized structured
+// field. If the value can't be serialized, the header isn't set.
+//
+// Example Usage:
+//
+//	resp.JSON(w, data, resp.WithStructuredField("Example-List",
+//	    resp.SFList{
+//	        resp.SFToken("gzip"),
+//	        resp.SFItem{
+//	            Value:  resp.SFToken("br"),
+//	            Params: resp.SFParams{{Key: "q", Value: 0.5}},
+//	        },
+//	    }))
+//	// Example-List: gzip, br;q=0.5
+func WithStructuredField(key string, f StructuredField) Option {
+	return func(r *Response) *Response {
+		value, err := FormatStructuredField(f)
+		if err != nil {
+			return r
+		}
+
+		r.httpWriter.Header().Set(key, value)
+		return r
+	}
+}
+
+// appendSF serializes the item.
+func (i SFItem) appendSF(b []byte) ([]byte, error) {
+	b, err := appendSFBareItem(b, i.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.Params.appendSF(b)
+}
+
+// appendSF serializes the inner list.
+func (l SFInnerList) appendSF(b []byte) ([]byte, error) {
+	b = append(b, '(')
+	for n, item := range l.Items {
+		if n > 0 {
+			b = append(b, ' ')
+		}
+
+		var err error
+		if b, err = item.appendSF(b); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, ')')
+
+	return l.Params.appendSF(b)
+}
+
+// appendSF serializes the list.
+func (l SFList) appendSF(b []byte) ([]byte, error) {
+	for n, member := range l {
+		if n > 0 {
+			b = append(b, ", "...)
+		}
+
+		var err error
+		if b, err = appendSFMember(b, member); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// appendSF serializes the dictionary.
+func (d SFDictionary) appendSF(b []byte) ([]byte, error) {
+	for n, member := range d {
+		if n > 0 {
+			b = append(b, ", "...)
+		}
+
+		var err error
+		if b, err = appendSFKey(b, member.Key); err != nil {
+			return nil, err
+		}
+
+		// The member with the true value is serialized as the bare key.
+		value := member.Value
+		if item, ok := value.(SFItem); ok && item.Value == true {
+			if b, err = item.Params.appendSF(b); err != nil {
+				return nil, err
+			}
+			continue
+		} else if value == true {
+			continue
+		}
+
+		b = append(b, '=')
+		if b, err = appendSFMember(b, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// appendSF serializes the parameters.
+func (p SFParams) appendSF(b []byte) ([]byte, error) {
+	for _, param := range p {
+		b = append(b, ';')
+
+		var err error
+		if b, err = appendSFKey(b, param.Key); err != nil {
+			return nil, err
+		}
+
+		if param.Value == true {
+			continue
+		}
+
+		b = append(b, '=')
+		if b, err = appendSFBareItem(b, param.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// appendSFMember serializes the member of the list or the dictionary.
+func appendSFMember(b []byte, member any) ([]byte, error) {
+	switch m := member.(type) {
+	case SFItem:
+		return m.appendSF(b)
+	case SFInnerList:
+		return m.appendSF(b)
+	}
+
+	return appendSFBareItem(b, member)
+}
+
+// appendSFKey serializes the key of the parameter or the dictionary.
+func appendSFKey(b []byte, key string) ([]byte, error) {
+	if key == "" {
+		return nil, errors.New("empty key")
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z', c == '*':
+		case i > 0 && (c >= '0' && c <= '9' ||
+			c == '_' || c == '-' || c == '.'):
+		default:
+			return nil, fmt.Errorf("invalid key %q", key)
+		}
+	}
+
+	return append(b, key...), nil
+}
+
+// appendSFBareItem serializes the bare item.
+func appendSFBareItem(b []byte, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case int:
+		return appendSFInteger(b, int64(v))
+	case int64:
+		return appendSFInteger(b, v)
+	case float64:
+		return appendSFDecimal(b, v)
+	case string:
+		return appendSFString(b, v)
+	case SFToken:
+		return appendSFToken(b, string(v))
+	case []byte:
+		b = append(b, ':')
+		b = append(b, base64.StdEncoding.EncodeToString(v)...)
+		return append(b, ':'), nil
+	case bool:
+		if v {
+			return append(b, "?1"...), nil
+		}
+		return append(b, "?0"...), nil
+	case time.Time:
+		b = append(b, '@')
+		return appendSFInteger(b, v.Unix())
+	}
+
+	return nil, fmt.Errorf("unsupported bare item %T", value)
+}
+
+// appendSFInteger serializes the integer.
+func appendSFInteger(b []byte, v int64) ([]byte, error) {
+	if v > sfMaxInteger || v < -sfMaxInteger {
+		return nil, fmt.Errorf("integer %d out of range", v)
+	}
+
+	return strconv.AppendInt(b, v, 10), nil
+}
+
+// appendSFDecimal serializes the decimal, rounded
+// to three fractional digits (half to even).
+func appendSFDecimal(b []byte, v float64) ([]byte, error) {
+	v = math.RoundToEven(v*1000) / 1000
+	if math.IsNaN(v) || math.Abs(v) > sfMaxDecimal {
+		return nil, fmt.Errorf("decimal %v out of range", v)
+	}
+
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(s, "0")
+	if strings.HasSuffix(s, ".") {
+		s += "0"
+	}
+
+	return append(b, s...), nil
+}
+
+// appendSFString serializes the string.
+func appendSFString(b []byte, s string) ([]byte, error) {
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c > 0x7e {
+			return nil, fmt.Errorf("invalid character in string %q", s)
+		}
+
+		if c == '"' || c == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+
+	return append(b, '"'), nil
+}
+
+// appendSFToken serializes the token.
+func appendSFToken(b []byte, s string) ([]byte, error) {
+	if !isSFToken(s) {
+		return nil, fmt.Errorf("invalid token %q", s)
+	}
+
+	return append(b, s...), nil
+}
+
+// isSFToken returns true if the string is the valid token.
+func isSFToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '*':
+		case i == 0:
+			return false
+		case c >= '0' && c <= '9', c == ':', c == '/',
+			strings.IndexByte("!#$%&'+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
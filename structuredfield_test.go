@@ -0,0 +1,148 @@
+package resp
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestFormatStructuredField tests the FormatStructuredField function.
+func TestFormatStructuredField(t *testing.T) {
+	tests := []struct {
+		name  string
+		field StructuredField
+		want  string
+	}{
+		{
+			name:  "Integer item",
+			field: SFItem{Value: -42},
+			want:  "-42",
+		},
+		{
+			name:  "Decimal item",
+			field: SFItem{Value: 1.0625},
+			want:  "1.062",
+		},
+		{
+			name:  "Whole decimal",
+			field: SFItem{Value: 5.0},
+			want:  "5.0",
+		},
+		{
+			name: "String with params",
+			field: SFItem{
+				Value: `say "hi" \o/`,
+				Params: SFParams{
+					{Key: "a", Value: true},
+					{Key: "b", Value: false},
+					{Key: "c*", Value: SFToken("x/y")},
+				},
+			},
+			want: `"say \"hi\" \\o/";a;b=?0;c*=x/y`,
+		},
+		{
+			name:  "Byte sequence",
+			field: SFItem{Value: []byte("hello")},
+			want:  ":aGVsbG8=:",
+		},
+		{
+			name:  "Date",
+			field: SFItem{Value: time.Unix(1659578233, 0)},
+			want:  "@1659578233",
+		},
+		{
+			name: "List",
+			field: SFList{
+				SFToken("gzip"),
+				SFItem{
+					Value:  SFToken("br"),
+					Params: SFParams{{Key: "q", Value: 0.5}},
+				},
+				SFInnerList{
+					Items:  []SFItem{{Value: 1}, {Value: "two"}},
+					Params: SFParams{{Key: "n", Value: 2}},
+				},
+				SFInnerList{},
+			},
+			want: `gzip, br;q=0.5, (1 "two");n=2, ()`,
+		},
+		{
+			name: "Dictionary",
+			field: SFDictionary{
+				{Key: "u", Value: 5},
+				{Key: "i", Value: true},
+				{Key: "f", Value: SFItem{
+					Value:  true,
+					Params: SFParams{{Key: "p", Value: 1}},
+				}},
+				{Key: "l", Value: SFInnerList{
+					Items: []SFItem{{Value: SFToken("self")}},
+				}},
+				{Key: "z", Value: false},
+			},
+			want: `u=5, i, f;p=1, l=(self), z=?0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatStructuredField(tt.field)
+			if err != nil {
+				t.Fatalf("FormatStructuredField() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("FormatStructuredField() = %s, want %s",
+					got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatStructuredField_Invalid tests the values that
+// can't be serialized.
+func TestFormatStructuredField_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		field StructuredField
+	}{
+		{"Integer out of range", SFItem{Value: int64(1e15)}},
+		{"Decimal out of range", SFItem{Value: 1e12}},
+		{"Non-ASCII string", SFItem{Value: "café"}},
+		{"Control character", SFItem{Value: "a\nb"}},
+		{"Invalid token", SFItem{Value: SFToken("1abc")}},
+		{"Unsupported type", SFItem{Value: uint(1)}},
+		{"Upper-case key", SFDictionary{{Key: "Key", Value: 1}}},
+		{"Empty key", SFItem{Value: 1, Params: SFParams{{Value: 1}}}},
+		{"Digit first key", SFDictionary{{Key: "1a", Value: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FormatStructuredField(tt.field)
+			if !errors.Is(err, ErrInvalidStructuredField) {
+				t.Errorf("FormatStructuredField() error = %v, want %v",
+					err, ErrInvalidStructuredField)
+			}
+		})
+	}
+}
+
+// TestWithStructuredField tests the WithStructuredField option.
+func TestWithStructuredField(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w,
+		WithStructuredField("Example-Dict", SFDictionary{
+			{Key: "a", Value: 1},
+		}),
+		WithStructuredField("Example-Invalid", SFItem{Value: "café"}))
+
+	if got := w.Header().Get("Example-Dict"); got != "a=1" {
+		t.Errorf("Example-Dict = %q, want %q", got, "a=1")
+	}
+
+	if got := w.Header().Get("Example-Invalid"); got != "" {
+		t.Errorf("Example-Invalid = %q, want empty", got)
+	}
+}
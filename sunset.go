@@ -0,0 +1,95 @@
+package resp
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultSunsetWindow is the period before the sunset date during
+// which the Sunsetting middleware adds the Warning header.
+const DefaultSunsetWindow = 30 * 24 * time.Hour
+
+// timeNow returns the current time, it's a variable for testing.
+var timeNow = time.Now
+
+// SunsetMetaKey is the key of the metadata (see WithMeta) listing
+// the warnings of the JSON responses of the Sunsetting middleware.
+const SunsetMetaKey = "warnings"
+
+// Sunsetting returns a middleware that marks every response of the
+// next handler as deprecated.
+//
+// It adds the following headers to each response:
+//   - Deprecation: the deprecation date, if it isn't zero (RFC 9745);
+//   - Sunset: the sunset date (RFC 8594);
+//   - Link: the successor with rel="successor-version", if it isn't empty.
+//
+// When the sunset date is closer than the window (DefaultSunsetWindow
+// if not provided), a Warning header with code 299 is added too, so
+// clients that log warnings notice the upcoming removal, and the
+// warning is listed in the metadata of the JSON responses created by
+// the package (under SunsetMetaKey, see WithMeta). If more than one
+// window is passed, only the first one will be used.
+//
+// Example usage:
+//
+//	deprecated := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+//	sunset := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+//	http.Handle("/v1/users",
+//	    resp.Sunsetting(usersV1, deprecated, sunset, "/v2/users"))
+func Sunsetting(
+	next http.Handler,
+	deprecation time.Time,
+	sunset time.Time,
+	successor string,
+	window ...time.Duration,
+) http.Handler {
+	w := DefaultSunsetWindow
+	if len(window) > 0 {
+		w = window[0]
+	}
+
+	opts := []Option{AddSunset(sunset)}
+	if !deprecation.IsZero() {
+		opts = append(opts, AddDeprecation(deprecation))
+	}
+
+	if successor != "" {
+		opts = append(opts, AddLink(LinkHeader{
+			URI: successor,
+			Rel: "successor-version",
+		}))
+	}
+
+	warning := WarningHeader{
+		Code:  299,
+		Agent: "-",
+		Text: fmt.Sprintf(
+			"This resource is deprecated and will be removed on %s",
+			sunset.UTC().Format(http.TimeFormat),
+		),
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		headers := opts
+		near := timeNow().Add(w).After(sunset)
+		if near {
+			headers = append(headers[:len(headers):len(headers)],
+				AddWarning(warning))
+		}
+
+		// Only the headers are set here: the defaults (see SetDefaults)
+		// are applied to the responses of the handler.
+		newResponse(rw, headers)
+
+		if near {
+			route := &routeWriter{ResponseWriter: rw}
+			route.opts = append(routeOptions(rw),
+				WithMeta(SunsetMetaKey, []string{warning.Text}))
+			rw = route
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,103 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSunsetting tests the Sunsetting middleware.
+func TestSunsetting(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	sunset := now.Add(90 * 24 * time.Hour)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, R{"ok": true})
+	})
+	deprecation := time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)
+	h := Sunsetting(next, deprecation, sunset, "/v2/users")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+
+	tests := map[string]string{
+		HeaderDeprecation: "@1701388800",
+		HeaderSunset:      "Sun, 31 Mar 2024 00:00:00 GMT",
+		HeaderLink:        `</v2/users>; rel="successor-version"`,
+		HeaderWarning:     "",
+	}
+
+	for key, want := range tests {
+		if got := w.Header().Get(key); got != want {
+			t.Errorf("Sunsetting() %s = %q, want %q", key, got, want)
+		}
+	}
+
+	if w.Body.String() != "{\"ok\":true}\n" {
+		t.Errorf("Sunsetting() body = %q", w.Body.String())
+	}
+
+	// Move into the warning window.
+	now = sunset.Add(-24 * time.Hour)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+
+	got := w.Header().Get(HeaderWarning)
+	if !strings.HasPrefix(got, `299 - "This resource is deprecated`) {
+		t.Errorf("Sunsetting() Warning = %q", got)
+	}
+
+	want := `{"meta":{"warnings":["This resource is deprecated and will ` +
+		`be removed on Sun, 31 Mar 2024 00:00:00 GMT"]},"ok":true}` + "\n"
+	if w.Body.String() != want {
+		t.Errorf("Sunsetting() body = %s, want %s", w.Body.String(), want)
+	}
+}
+
+// TestSunsetting_Window tests the Sunsetting middleware
+// with a custom window and without successor.
+func TestSunsetting_Window(t *testing.T) {
+	sunset := time.Now().Add(48 * time.Hour)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NoContent(w)
+	})
+
+	w := httptest.NewRecorder()
+	Sunsetting(next, time.Time{}, sunset, "", time.Hour).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := w.Header().Get(HeaderWarning); got != "" {
+		t.Errorf("Sunsetting() unexpected Warning = %q", got)
+	}
+
+	if got := w.Header().Get(HeaderLink); got != "" {
+		t.Errorf("Sunsetting() unexpected Link = %q", got)
+	}
+
+	if got := w.Header().Get(HeaderDeprecation); got != "" {
+		t.Errorf("Sunsetting() unexpected Deprecation = %q", got)
+	}
+}
+
+// TestSunsetting_Defaults tests that the default options
+// are applied once.
+func TestSunsetting_Defaults(t *testing.T) {
+	SetDefaults(AddLink(LinkHeader{URI: "/docs", Rel: "help"}))
+	defer SetDefaults()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NoContent(w)
+	})
+
+	w := httptest.NewRecorder()
+	Sunsetting(next, time.Time{}, time.Now().Add(time.Hour), "").
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := w.Header().Values(HeaderLink); len(got) != 1 {
+		t.Errorf("Sunsetting() Link = %q, want one value", got)
+	}
+}
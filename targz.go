@@ -0,0 +1,140 @@
+package resp
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+// TarEntry is a file of the tar.gz archive sent by ServeTarGz.
+type TarEntry struct {
+	// Name is the path of the file in the archive.
+	Name string
+
+	// Size is the size of the file content. If it is negative, the
+	// content is read into memory to find out the size, because the
+	// tar header must precede the content.
+	Size int64
+
+	// Mode is the permission bits of the file; 0644 is used if zero.
+	Mode fs.FileMode
+
+	// ModTime is the modification time of the file;
+	// the current time is used if zero.
+	ModTime time.Time
+
+	// Open returns the content of the file. It is called when the
+	// entry is written, so the files are opened one at a time.
+	Open func() (io.ReadCloser, error)
+}
+
+// ServeTarGz streams the entries to the client as a gzip-compressed tar
+// archive (application/gzip) with the name for the download. Each entry
+// is opened and copied into the archive as it is read, so the archive
+// isn't built in memory.
+//
+// The status and headers are sent before the first entry, so if an
+// entry fails, the archive is truncated and the error is returned.
+//
+// Example Usage:
+//
+//	entries := []resp.TarEntry{{
+//	    Name: "report.csv",
+//	    Size: info.Size(),
+//	    Open: func() (io.ReadCloser, error) {
+//	        return os.Open("/data/report.csv")
+//	    },
+//	}}
+//
+//	resp.ServeTarGz(w, "reports.tar.gz", entries)
+func ServeTarGz(
+	w http.ResponseWriter,
+	name string,
+	entries []TarEntry,
+	opts ...Option,
+) error {
+	return NewResponse(w, opts...).ServeTarGz(name, entries)
+}
+
+// ServeTarGz streams the entries as a tar.gz archive.
+// See the ServeTarGz function for details.
+func (r *Response) ServeTarGz(name string, entries []TarEntry) (err error) {
+	defer r.finish(&err)
+
+	r.setAttachment(name)
+	r.prepare(StatusOK, MIMEApplicationGzip)
+	r.writeHeader(r.statusCode)
+
+	gw := gzip.NewWriter(r.body())
+	tw := tar.NewWriter(gw)
+
+	for _, entry := range entries {
+		if err := writeTarEntry(tw, entry); err != nil {
+			return fmt.Errorf("failed to write %q: %w", entry.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// writeTarEntry writes the header and the content of the entry.
+func writeTarEntry(tw *tar.Writer, entry TarEntry) error {
+	content, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	var data io.Reader = content
+	size := entry.Size
+	if size < 0 {
+		buf, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+
+		data, size = bytes.NewReader(buf), int64(len(buf))
+	}
+
+	mode := entry.Mode.Perm()
+	if mode == 0 {
+		mode = 0o644
+	}
+
+	modTime := entry.ModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     entry.Name,
+		Size:     size,
+		Mode:     int64(mode),
+		ModTime:  modTime,
+		Format:   tar.FormatPAX,
+	})
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(tw, io.LimitReader(data, size))
+	if err != nil {
+		return err
+	}
+
+	if n < size {
+		return io.ErrUnexpectedEOF
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package resp
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// openString returns the Open function of the entry with the content.
+func openString(s string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+// TestServeTarGz tests the ServeTarGz function.
+func TestServeTarGz(t *testing.T) {
+	entries := []TarEntry{
+		{Name: "a.txt", Size: 5, Open: openString("hello")},
+		{Name: "dir/b.txt", Size: -1, Open: openString("world!")},
+	}
+
+	w := httptest.NewRecorder()
+	if err := ServeTarGz(w, "files.tar.gz", entries); err != nil {
+		t.Fatalf("ServeTarGz() error = %v", err)
+	}
+
+	if got := w.Header().Get(HeaderContentType); got != MIMEApplicationGzip {
+		t.Errorf("Content-Type = %q, want %q", got, MIMEApplicationGzip)
+	}
+
+	want := `attachment; filename="files.tar.gz"`
+	if got := w.Header().Get(HeaderContentDisposition); got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar.Next() error = %v", err)
+		}
+
+		data, _ := io.ReadAll(tr)
+		files[header.Name] = string(data)
+
+		if header.Mode != 0o644 {
+			t.Errorf("%s mode = %o, want 644", header.Name, header.Mode)
+		}
+	}
+
+	if len(files) != 2 || files["a.txt"] != "hello" ||
+		files["dir/b.txt"] != "world!" {
+		t.Errorf("ServeTarGz() files = %v", files)
+	}
+}
+
+// TestServeTarGzErrors tests the failures of the entries.
+func TestServeTarGzErrors(t *testing.T) {
+	failed := errors.New("storage is unavailable")
+	tests := []struct {
+		name    string
+		entry   TarEntry
+		wantErr error
+	}{
+		{
+			name: "Open error",
+			entry: TarEntry{Name: "a.txt", Size: 1,
+				Open: func() (io.ReadCloser, error) { return nil, failed }},
+			wantErr: failed,
+		},
+		{
+			name:    "Short content",
+			entry:   TarEntry{Name: "a.txt", Size: 10, Open: openString("a")},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := ServeTarGz(w, "a.tar.gz", []TarEntry{tt.entry})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ServeTarGz() error = %v, want %v",
+					err, tt.wantErr)
+			}
+		})
+	}
+}
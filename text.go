@@ -0,0 +1,81 @@
+package resp
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/transform"
+)
+
+// bomUTF8 is the UTF-8 byte order mark.
+var bomUTF8 = []byte{0xEF, 0xBB, 0xBF}
+
+// byteOrderMark returns the byte order mark in the text encoding
+// of the response. If the encoding can't represent the byte order
+// mark (e.g. single-byte code pages), it returns nil.
+func (r *Response) byteOrderMark() []byte {
+	if r.textEncoding == nil {
+		return bomUTF8
+	}
+
+	bom, err := r.textEncoding.NewEncoder().Bytes([]byte("\uFEFF"))
+	if err != nil {
+		return nil
+	}
+	return bom
+}
+
+// writeText copies the data into the response body. If the byte
+// order mark or the text encoding is set for the response, the BOM
+// is written first and the data is transformed from UTF-8 into the
+// target encoding.
+func (r *Response) writeText(data io.Reader) error {
+	if r.bom {
+		if bom := r.byteOrderMark(); bom != nil {
+			if _, err := r.write(bom); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.textEncoding == nil {
+		_, err := copyBuffer(r.body(), data)
+		return err
+	}
+
+	tw := transform.NewWriter(r.body(), r.textEncoding.NewEncoder())
+	if _, err := copyBuffer(tw, data); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// textValue returns the data as the text of the response: strings and
+// byte slices as is, errors by the Error method, fmt.Stringer values by
+// the String method, encoding.TextMarshaler values by the MarshalText
+// method, nil as the empty text, and other values as formatted by
+// fmt.Sprint.
+func textValue(data any) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case error:
+		return v.Error(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return "", &encodingError{context: "failed to marshal text", err: err}
+		}
+		return string(text), nil
+	}
+
+	return fmt.Sprint(data), nil
+}
@@ -0,0 +1,81 @@
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// TestWithBOM tests the WithBOM option.
+func TestWithBOM(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := String(w, "a,b\n", WithBOM()); err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	want := append(append([]byte{}, bomUTF8...), "a,b\n"...)
+	if !bytes.Equal(w.Body.Bytes(), want) {
+		t.Errorf("String() with BOM = %q, want %q", w.Body.Bytes(), want)
+	}
+}
+
+// TestWithTextEncoding tests the WithTextEncoding option.
+func TestWithTextEncoding(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := ServeFileAsDownload(w, "report.csv", []byte("Привіт"),
+		WithTextEncoding(charmap.Windows1251), WithBOM())
+	if err != nil {
+		t.Fatalf("ServeFileAsDownload() returned error: %v", err)
+	}
+
+	// Windows-1251 can't represent the BOM, so it's skipped.
+	want := []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xB3, 0xF2}
+	if !bytes.Equal(w.Body.Bytes(), want) {
+		t.Errorf("ServeFileAsDownload() body = % X, want % X",
+			w.Body.Bytes(), want)
+	}
+}
+
+// TestWithTextEncoding_UTF16 tests that the BOM of the
+// target encoding is used.
+func TestWithTextEncoding_UTF16(t *testing.T) {
+	enc := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+
+	w := httptest.NewRecorder()
+	err := Stream(w, strings.NewReader("A"), WithTextEncoding(enc), WithBOM())
+	if err != nil {
+		t.Fatalf("Stream() returned error: %v", err)
+	}
+
+	want := []byte{0xFF, 0xFE, 'A', 0x00}
+	if !bytes.Equal(w.Body.Bytes(), want) {
+		t.Errorf("Stream() body = % X, want % X", w.Body.Bytes(), want)
+	}
+}
+
+// TestWithTextEncoding_Unsupported tests that the error is returned
+// when the text can't be represented in the target encoding.
+func TestWithTextEncoding_Unsupported(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := String(w, "日本", WithTextEncoding(charmap.Windows1251))
+	if err == nil {
+		t.Error("String() expected error for unsupported characters")
+	}
+}
+
+// TestWriteText_WriteError tests that the write error of
+// the BOM is returned.
+func TestWriteText_WriteError(t *testing.T) {
+	writeErr := errors.New("write failed")
+	w := &mockErrorWriter{err: writeErr}
+
+	err := String(w, "data", WithBOM())
+	if !errors.Is(err, writeErr) {
+		t.Errorf("String() error = %v, want %v", err, writeErr)
+	}
+}
@@ -0,0 +1,62 @@
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ErrWriteTimeout is returned (wrapped) by the response methods when
+// the client doesn't read the response within the write timeout.
+// See the WithWriteTimeout option.
+var ErrWriteTimeout = errors.New("write timeout")
+
+// WithWriteTimeout sets the write deadline of the connection before
+// each write of the response body (e.g. every 32KB of a stream) to the
+// current time plus the timeout, so a client that reads the response
+// too slowly (or stops reading it) can't hold the handler goroutine.
+// The error of the timed out write wraps ErrWriteTimeout.
+//
+// The deadline is set with http.ResponseController and replaces the
+// WriteTimeout of the server; it remains set after the response is
+// written. The option has no effect if the writer doesn't support the
+// deadlines. The zero-copy fast path (sendfile) of the writer is
+// disabled to apply the timeout to each chunk of the body.
+//
+// Example Usage:
+//
+//	err := resp.Stream(w, file, resp.WithWriteTimeout(10*time.Second))
+//	if errors.Is(err, resp.ErrWriteTimeout) {
+//	    log.Printf("slow client: %s", r.RemoteAddr)
+//	}
+func WithWriteTimeout(d time.Duration) Option {
+	return func(r *Response) *Response {
+		r.writeTimeout = d
+		return r
+	}
+}
+
+// setWriteDeadline extends the write deadline of the
+// connection, if the write timeout is set.
+func (r *Response) setWriteDeadline() {
+	if r.writeTimeout <= 0 {
+		return
+	}
+
+	// The writers that don't support the deadlines are written
+	// without them, as if the timeout isn't set.
+	rc := http.NewResponseController(r.httpWriter)
+	rc.SetWriteDeadline(time.Now().Add(r.writeTimeout))
+}
+
+// timeoutError wraps the write error with ErrWriteTimeout
+// if the write deadline is exceeded.
+func (r *Response) timeoutError(err error) error {
+	if r.writeTimeout > 0 && errors.Is(err, os.ErrDeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrWriteTimeout, err)
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deadlineRecorder is a recorder that supports the write deadlines
+// and fails the writes after the given number of writes.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+	failAfter int
+}
+
+// SetWriteDeadline records the deadline.
+func (w *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	w.deadlines = append(w.deadlines, t)
+	return nil
+}
+
+// Write fails with os.ErrDeadlineExceeded after failAfter writes.
+func (w *deadlineRecorder) Write(p []byte) (int, error) {
+	if len(w.deadlines) > w.failAfter {
+		return 0, os.ErrDeadlineExceeded
+	}
+	return w.ResponseRecorder.Write(p)
+}
+
+// TestWithWriteTimeout tests the WithWriteTimeout option.
+func TestWithWriteTimeout(t *testing.T) {
+	data := strings.Repeat("x", 64*1024+1)
+
+	// The struct hides the WriteTo method of the reader,
+	// so the data is written in 32KB chunks.
+	reader := func() io.Reader {
+		return struct{ io.Reader }{strings.NewReader(data)}
+	}
+
+	t.Run("Deadline per write", func(t *testing.T) {
+		w := &deadlineRecorder{
+			ResponseRecorder: httptest.NewRecorder(),
+			failAfter:        10,
+		}
+
+		before := time.Now()
+		err := Stream(w, reader(),
+			WithWriteTimeout(time.Minute))
+		if err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+
+		if len(w.deadlines) != 3 {
+			t.Fatalf("deadlines = %d, want %d", len(w.deadlines), 3)
+		}
+
+		if d := w.deadlines[0].Sub(before); d < time.Minute {
+			t.Errorf("deadline = now + %s, want now + %s", d, time.Minute)
+		}
+
+		if w.Body.Len() != len(data) {
+			t.Errorf("body length = %d, want %d", w.Body.Len(), len(data))
+		}
+	})
+
+	t.Run("Timeout error", func(t *testing.T) {
+		w := &deadlineRecorder{
+			ResponseRecorder: httptest.NewRecorder(),
+			failAfter:        1,
+		}
+
+		err := Stream(w, reader(),
+			WithWriteTimeout(time.Second))
+		if !errors.Is(err, ErrWriteTimeout) {
+			t.Errorf("Stream() error = %v, want %v", err, ErrWriteTimeout)
+		}
+
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("Stream() error = %v, want %v",
+				err, os.ErrDeadlineExceeded)
+		}
+	})
+
+	t.Run("Without timeout", func(t *testing.T) {
+		w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		err := Stream(w, reader())
+		if err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+
+		if len(w.deadlines) != 0 {
+			t.Errorf("deadlines = %d, want none", len(w.deadlines))
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package resp
+
+import (
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// TruncatedKey is the key of the field added by TruncateStrings to the
+// result map, listing the names of the fields that have been truncated.
+const TruncatedKey = "_truncated"
+
+// TruncateStrings clips the string fields of the provided data that are
+// longer than max bytes and returns the result as an `R` map. This keeps
+// a single huge text field (e.g. a log or a description) from ballooning
+// the size of the response. The operation can be performed on a single
+// object, a slice of objects, an array of objects, or a map, with the
+// same input handling as OnlyFields.
+//
+// A truncated string is cut at a rune boundary and ends with the suffix,
+// and the whole value (including the suffix) is not longer than max
+// bytes. If max isn't greater than the length of the suffix, the suffix
+// is omitted. The names of the truncated fields are listed in the
+// TruncatedKey field of the result map; maps without truncated fields
+// have no such field.
+//
+// The struct fields are keyed by their JSON names, as encoding/json
+// encodes them: the unexported fields and the fields tagged with
+// `json:"-"` are skipped, and the empty omitempty fields are omitted.
+// Only the top-level fields are processed. If max is negative, the data
+// is returned unchanged.
+//
+// Example Usage:
+//
+//	type Build struct {
+//		ID  int
+//		Log string
+//	}
+//
+//	func BuildData(w http.ResponseWriter, r *http.Request) {
+//		build := Build{ID: 1, Log: veryLongLog}
+//		data := resp.TruncateStrings(build, 1024, "…")
+//		// {"ID": 1, "Log": "…", "_truncated": ["Log"]}
+//		if err := resp.JSON(w, data); err != nil {
+//			// handle error
+//		}
+//	}
+func TruncateStrings(data any, max int, suffix string) any {
+	if max < 0 {
+		return data
+	}
+
+	rv := reflect.ValueOf(data)
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		rv = rv.Elem()
+		if rv.Kind() == reflect.Struct {
+			return truncateStrings(rv, max, suffix)
+		}
+	case reflect.Slice, reflect.Array:
+		length := rv.Len()
+		if length > 0 {
+			elemKind := rv.Index(0).Kind()
+			if elemKind == reflect.Ptr {
+				elemKind = rv.Index(0).Elem().Kind()
+			}
+			if elemKind == reflect.Struct {
+				result := make([]R, length)
+				for i := 0; i < length; i++ {
+					elem := rv.Index(i)
+					if elem.Kind() == reflect.Ptr {
+						elem = elem.Elem()
+					}
+					result[i] = truncateStrings(elem, max, suffix)
+				}
+				return result
+			}
+		}
+	case reflect.Struct:
+		return truncateStrings(rv, max, suffix)
+	case reflect.Map:
+		if m, ok := data.(map[string]any); ok {
+			return truncateStringsMap(m, max, suffix)
+		}
+		if m, ok := data.(R); ok {
+			return truncateStringsMap(m, max, suffix)
+		}
+	}
+
+	return data
+}
+
+// truncateStrings clips the long string fields of the provided struct
+// value and returns the fields as an `R` map keyed by the JSON names.
+func truncateStrings(rv reflect.Value, max int, suffix string) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(info.flat))
+	truncated := []string{}
+
+	for _, field := range info.flat {
+		if field.jsonSkip {
+			continue
+		}
+
+		value, ok := fieldByPath(rv, field.path)
+		if !ok || (field.omitEmpty && isEmptyValue(value)) {
+			continue
+		}
+
+		name := field.jsonName
+		if value.Kind() == reflect.String && value.Len() > max {
+			result[name] = truncateString(value.String(), max, suffix)
+			truncated = append(truncated, name)
+			continue
+		}
+		result[name] = value.Interface()
+	}
+
+	if len(truncated) > 0 {
+		result[TruncatedKey] = truncated
+	}
+
+	return result
+}
+
+// truncateStringsMap clips the long string values of the provided
+// map and returns the values as an `R` map.
+func truncateStringsMap(data map[string]any, max int, suffix string) R {
+	result := make(R, len(data))
+	truncated := []string{}
+
+	for key, value := range data {
+		if s, ok := value.(string); ok && len(s) > max {
+			result[key] = truncateString(s, max, suffix)
+			truncated = append(truncated, key)
+			continue
+		}
+		result[key] = value
+	}
+
+	if len(truncated) > 0 {
+		sort.Strings(truncated)
+		result[TruncatedKey] = truncated
+	}
+
+	return result
+}
+
+// truncateString cuts the string at a rune boundary so that the result,
+// including the suffix, is not longer than max bytes.
+func truncateString(s string, max int, suffix string) string {
+	if len(s) <= max {
+		return s
+	}
+
+	if max <= len(suffix) {
+		suffix = ""
+	}
+
+	n := max - len(suffix)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n] + suffix
+}
@@ -0,0 +1,140 @@
+package resp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// truncateTestStruct is a struct used in TruncateStrings tests.
+type truncateTestStruct struct {
+	ID    int
+	Title string
+	Log   string
+}
+
+// TestTruncateStrings_Struct tests TruncateStrings with a struct.
+func TestTruncateStrings_Struct(t *testing.T) {
+	data := truncateTestStruct{ID: 1, Title: "short", Log: "0123456789"}
+
+	got := TruncateStrings(data, 6, "...")
+	want := R{
+		"ID":         1,
+		"Title":      "short",
+		"Log":        "012...",
+		TruncatedKey: []string{"Log"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TruncateStrings() = %v, want %v", got, want)
+	}
+
+	// Pointer to struct.
+	if got := TruncateStrings(&data, 6, "..."); !reflect.DeepEqual(got, want) {
+		t.Errorf("TruncateStrings() = %v, want %v", got, want)
+	}
+}
+
+// TestTruncateStrings_Tags tests that the unexported fields and the
+// fields tagged with `json:"-"` are skipped and the JSON names are used.
+func TestTruncateStrings_Tags(t *testing.T) {
+	type build struct {
+		ID     int    `json:"id"`
+		Log    string `json:"log"`
+		Secret string `json:"-"`
+		Note   string `json:"note,omitempty"`
+		cache  string
+	}
+
+	data := build{ID: 1, Log: "0123456789", Secret: "s", cache: "c"}
+	got := TruncateStrings(data, 6, "...")
+	want := R{
+		"id":         1,
+		"log":        "012...",
+		TruncatedKey: []string{"log"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TruncateStrings() = %v, want %v", got, want)
+	}
+}
+
+// TestTruncateStrings_Slice tests TruncateStrings with a slice.
+func TestTruncateStrings_Slice(t *testing.T) {
+	data := []*truncateTestStruct{
+		{ID: 1, Log: "short"},
+		{ID: 2, Log: "very long log"},
+	}
+
+	got, ok := TruncateStrings(data, 8, "").([]R)
+	if !ok || len(got) != 2 {
+		t.Fatalf("TruncateStrings() = %v", got)
+	}
+
+	if _, ok := got[0][TruncatedKey]; ok {
+		t.Errorf("TruncateStrings() marked %v as truncated", got[0])
+	}
+
+	if got[1]["Log"] != "very lon" {
+		t.Errorf("TruncateStrings() Log = %q", got[1]["Log"])
+	}
+}
+
+// TestTruncateStrings_Map tests TruncateStrings with a map.
+func TestTruncateStrings_Map(t *testing.T) {
+	data := map[string]any{"b": "bbbbbb", "a": "aaaaaa", "n": 12345678}
+
+	got := TruncateStrings(data, 3, "~").(R)
+	if got["a"] != "aa~" || got["b"] != "bb~" || got["n"] != 12345678 {
+		t.Errorf("TruncateStrings() = %v", got)
+	}
+
+	want := []string{"a", "b"}
+	if !reflect.DeepEqual(got[TruncatedKey], want) {
+		t.Errorf("TruncateStrings() marker = %v, want %v",
+			got[TruncatedKey], want)
+	}
+}
+
+// TestTruncateStrings_Unchanged tests that unsupported data
+// and negative limits return the data unchanged.
+func TestTruncateStrings_Unchanged(t *testing.T) {
+	if got := TruncateStrings("long string", 2, ""); got != "long string" {
+		t.Errorf("TruncateStrings() = %v", got)
+	}
+
+	data := truncateTestStruct{Log: "abc"}
+	if got := TruncateStrings(data, -1, ""); got != data {
+		t.Errorf("TruncateStrings() = %v", got)
+	}
+}
+
+// TestTruncateString tests the truncateString function.
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		s      string
+		max    int
+		suffix string
+		want   string
+	}{
+		{"hello", 10, "...", "hello"},
+		{"hello world", 8, "...", "hello..."},
+		{"hello", 2, "...", "he"},
+		{"привіт", 5, "", "пр"},
+		{"привіт", 7, "…", "пр…"},
+		{"привіт", 6, "…", "п…"},
+		{"abc", 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		got := truncateString(tt.s, tt.max, tt.suffix)
+		if got != tt.want {
+			t.Errorf("truncateString(%q, %d, %q) = %q, want %q",
+				tt.s, tt.max, tt.suffix, got, tt.want)
+		}
+
+		if len(got) > tt.max && len(tt.s) > tt.max {
+			t.Errorf("truncateString(%q) result is longer than %d",
+				tt.s, tt.max)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package resp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// varyOn records that the response depends on the provided request
+// headers by merging them into the Vary header. It must be called by
+// every part of the package that makes a decision based on a request
+// header (Accept, Accept-Encoding, Accept-Language, Origin, etc.).
+//
+// Nothing is done if automatic Vary management is disabled for the
+// response with the WithoutAutoVary option.
+func (r *Response) varyOn(names ...string) {
+	if r.noAutoVary {
+		return
+	}
+	mergeVary(r.httpWriter.Header(), names...)
+}
+
+// mergeVary adds the provided header names into the Vary header
+// of h. Names that are already present (compared case-insensitively)
+// are skipped, so the Vary header never contains duplicates.
+// If the Vary header is "*", it is left unchanged.
+func mergeVary(h http.Header, names ...string) {
+	existing := make(map[string]bool)
+	for _, value := range h.Values(HeaderVary) {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return
+			}
+
+			if name != "" {
+				existing[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if existing[key] {
+			continue
+		}
+
+		existing[key] = true
+		h.Add(HeaderVary, http.CanonicalHeaderKey(name))
+	}
+}
+
+// varyNames returns the names of the request headers listed in the
+// Vary header of h, in canonical form, including "*" if it is present.
+func varyNames(h http.Header) []string {
+	var names []string
+	for _, value := range h.Values(HeaderVary) {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return names
+}
+
+// varyKey returns the values of the request headers named by the
+// Vary header of the response, encoded to be appended to a cache key,
+// or "" if the response has no Vary header.
+func varyKey(r *http.Request, h http.Header) string {
+	var b strings.Builder
+	for _, name := range varyNames(h) {
+		b.WriteString("\x00")
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package resp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestMergeVary tests the mergeVary function.
+func TestMergeVary(t *testing.T) {
+	h := http.Header{}
+	h.Add(HeaderVary, "Accept, accept-encoding")
+
+	mergeVary(h, "Accept-Encoding", "origin", "Origin")
+
+	want := []string{"Accept, accept-encoding", "Origin"}
+	if got := h.Values(HeaderVary); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeVary() = %v, want %v", got, want)
+	}
+}
+
+// TestMergeVary_Star tests that the "*" value isn't extended.
+func TestMergeVary_Star(t *testing.T) {
+	h := http.Header{}
+	h.Set(HeaderVary, "*")
+
+	mergeVary(h, HeaderOrigin)
+
+	if got := h.Values(HeaderVary); len(got) != 1 || got[0] != "*" {
+		t.Errorf("mergeVary() = %v, want [*]", got)
+	}
+}
+
+// TestVaryOn tests the varyOn method and the WithoutAutoVary option.
+func TestVaryOn(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewResponse(w).varyOn(HeaderAccept, HeaderAccept)
+	if got := w.Header().Values(HeaderVary); len(got) != 1 {
+		t.Errorf("varyOn() Vary = %v, want [Accept]", got)
+	}
+
+	w = httptest.NewRecorder()
+	NewResponse(w, WithoutAutoVary()).varyOn(HeaderAccept)
+	if got := w.Header().Get(HeaderVary); got != "" {
+		t.Errorf("varyOn() with WithoutAutoVary Vary = %q", got)
+	}
+}
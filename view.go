@@ -0,0 +1,184 @@
+package resp
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ViewTag is the name of the struct tag declaring the field policies
+// applied by WithView:
+//   - `resp:"omit"` - the field is never sent;
+//   - `resp:"only=admin"` - the field is sent only in the admin view,
+//     several views are separated by "|", e.g. `resp:"only=admin|owner"`.
+//
+// Fields without the tag are sent in all views.
+const ViewTag = "resp"
+
+// WithView sets the serialization profile of the JSON response: the
+// struct fields are filtered according to their ViewTag policies (see
+// ApplyView). Use an empty view for the public profile.
+//
+// Example Usage:
+//
+//	type User struct {
+//		ID       int    `json:"id"`
+//		Email    string `json:"email" resp:"only=admin|owner"`
+//		Password string `json:"-"`
+//		Notes    string `json:"notes" resp:"only=admin"`
+//	}
+//
+//	resp.JSON(w, user, resp.WithView("owner"))
+//	// {"email": "...", "id": 1}
+func WithView(view string) Option {
+	return func(r *Response) *Response {
+		r.view = &view
+		return r
+	}
+}
+
+// ApplyView filters the fields of the provided data according to
+// the ViewTag policies of the struct fields for the view and returns
+// the result as an `R` map keyed by the JSON names of the fields.
+// The encoding/json rules are respected: fields tagged with "-" are
+// skipped, empty fields tagged with "omitempty" are omitted, and the
+// fields of the embedded structs are promoted.
+//
+// The operation can be performed on a single struct, a pointer to a
+// struct, or a slice/array of structs (the result is a slice of `R`
+// maps). Only the top-level fields are processed; the values with their
+// own JSON representation (json.Marshaler or encoding.TextMarshaler,
+// e.g. time.Time) and other data are returned unchanged.
+func ApplyView(data any, view string) any {
+	rv := reflect.ValueOf(data)
+	if isMarshaler(rv) {
+		return data
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		rv = rv.Elem()
+		if rv.Kind() == reflect.Struct {
+			return applyView(rv, view)
+		}
+	case reflect.Slice, reflect.Array:
+		length := rv.Len()
+		if length > 0 {
+			elemKind := rv.Index(0).Kind()
+			if elemKind == reflect.Ptr {
+				elemKind = rv.Index(0).Elem().Kind()
+			}
+			if elemKind == reflect.Struct && !isMarshaler(rv.Index(0)) {
+				result := make([]R, length)
+				for i := 0; i < length; i++ {
+					elem := rv.Index(i)
+					if elem.Kind() == reflect.Ptr {
+						elem = elem.Elem()
+					}
+					result[i] = applyView(elem, view)
+				}
+				return result
+			}
+		}
+	case reflect.Struct:
+		return applyView(rv, view)
+	}
+
+	return data
+}
+
+// applyView returns the fields of the struct visible in the view.
+func applyView(rv reflect.Value, view string) R {
+	info := cachedStructInfo(rv.Type())
+	result := make(R, len(info.flat))
+
+	for _, field := range info.flat {
+		if field.jsonSkip || !visibleInView(field.field, view) {
+			continue
+		}
+
+		value, ok := fieldByPath(rv, field.path)
+		if !ok || (field.omitEmpty && isEmptyValue(value)) {
+			continue
+		}
+
+		result[field.jsonName] = value.Interface()
+	}
+
+	return result
+}
+
+// visibleInView reports whether the ViewTag policy
+// of the field allows it in the view.
+func visibleInView(field reflect.StructField, view string) bool {
+	tag, ok := field.Tag.Lookup(ViewTag)
+	if !ok {
+		return true
+	}
+
+	for _, policy := range strings.Split(tag, ",") {
+		policy = strings.TrimSpace(policy)
+		switch {
+		case policy == "omit":
+			return false
+		case strings.HasPrefix(policy, "only="):
+			found := false
+			views := strings.TrimPrefix(policy, "only=")
+			for _, v := range strings.Split(views, "|") {
+				if strings.TrimSpace(v) == view {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// jsonFieldName returns the name of the field in the JSON output
+// and whether it has the omitempty option. The ok is false if the
+// field is skipped by encoding/json.
+func jsonFieldName(
+	field reflect.StructField,
+) (name string, omitEmpty, ok bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, true
+}
+
+// isEmptyValue reports whether the value is empty
+// in terms of the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package resp
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// viewUser is a model with the view policies.
+type viewUser struct {
+	ID       int    `json:"id"`
+	Email    string `json:"email" resp:"only=admin|owner"`
+	Notes    string `json:"notes,omitempty" resp:"only=admin"`
+	Token    string `json:"token" resp:"omit"`
+	Password string `json:"-"`
+	Name     string
+	internal string
+}
+
+// TestApplyView tests the filtering of the fields by the view.
+func TestApplyView(t *testing.T) {
+	user := viewUser{
+		ID:       1,
+		Email:    "a@example.com",
+		Token:    "token",
+		Password: "secret",
+		Name:     "Go",
+		internal: "internal",
+	}
+
+	tests := []struct {
+		view string
+		want R
+	}{
+		{
+			view: "",
+			want: R{"id": 1, "Name": "Go"},
+		},
+		{
+			view: "owner",
+			want: R{"id": 1, "email": "a@example.com", "Name": "Go"},
+		},
+		{
+			// Notes are empty and omitted.
+			view: "admin",
+			want: R{"id": 1, "email": "a@example.com", "Name": "Go"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := ApplyView(&user, tt.view)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ApplyView(%q) = %v, want %v", tt.view, got, tt.want)
+		}
+	}
+
+	user.Notes = "VIP"
+	got := ApplyView([]viewUser{user}, "admin")
+	want := []R{{
+		"id":    1,
+		"email": "a@example.com",
+		"notes": "VIP",
+		"Name":  "Go",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyView() slice = %v, want %v", got, want)
+	}
+
+	if got := ApplyView(42, "admin"); got != 42 {
+		t.Errorf("ApplyView() non-struct = %v", got)
+	}
+}
+
+// TestWithView tests the JSON response with the view.
+func TestWithView(t *testing.T) {
+	user := viewUser{ID: 1, Email: "a@example.com", Token: "token"}
+
+	w := httptest.NewRecorder()
+	if err := JSON(w, user, WithView("")); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	want := `{"Name":"","id":1}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("JSON() body = %s, want %s", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	if err := JSON(w, user); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	want = `{"id":1,"email":"a@example.com","token":"token","Name":""}` +
+		"\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("JSON() without view = %s, want %s", got, want)
+	}
+}
+
+// TestWithView_JSONRules tests that the view keeps the JSON
+// representation of the marshalers and of the embedded structs.
+func TestWithView_JSONRules(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+
+	type item struct {
+		Base
+		Title  string `json:"title"`
+		Secret string `json:"secret" resp:"only=admin"`
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{"Time", created, `"2024-01-02T03:04:05Z"`},
+		{"Time pointer", &created, `"2024-01-02T03:04:05Z"`},
+		{"Time slice", []time.Time{created}, `["2024-01-02T03:04:05Z"]`},
+		{
+			"Embedded",
+			item{Base{1}, "Go", "s"},
+			`{"id":1,"title":"Go"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := JSON(w, tt.data, WithView("")); err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+
+			if got := w.Body.String(); got != tt.want+"\n" {
+				t.Errorf("JSON() body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyWritten is returned by a response method called after the
+// response is sent by another method of the same Response, e.g. JSON
+// after Error, since the status code and the body can't be replaced.
+// Nothing more is written to the body.
+var ErrAlreadyWritten = errors.New("response already written")
+
+// copyBufPool is the pool of the buffers used to copy
+// the readers to the response body.
+var copyBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 32*1024)
+		return &buf
+	},
+}
+
+// copyBuffer copies the src to the dst like io.Copy: it uses the WriteTo
+// method of the src or the ReadFrom method of the dst if available (e.g.
+// for the sendfile fast path), and a pooled buffer otherwise.
+func copyBuffer(dst io.Writer, src io.Reader) (int64, error) {
+	buf := copyBufPool.Get().(*[]byte)
+	defer copyBufPool.Put(buf)
+
+	return io.CopyBuffer(dst, src, *buf)
+}
+
+// ResponseInfo describes a response that has been written.
+// It is passed to the after-write hooks.
+type ResponseInfo struct {
+	Status      int           // status code sent to the client
+	Bytes       int64         // number of body bytes written
+	Duration    time.Duration // time since the response was created
+	ContentType string        // value of the Content-Type header
+	Err         error         // error returned by the response method
+}
+
+// AfterWriteFunc is a hook called after the response is written.
+type AfterWriteFunc func(info ResponseInfo)
+
+// writeHeader sends the status code with the headers.
+// All response methods must send the status code through it,
+// so the status is recorded for the after-write hooks.
+func (r *Response) writeHeader(code int) {
+	if r.wroteHeader {
+		r.rewritten = r.rewritten || r.finished
+		return
+	}
+
+	r.wroteHeader = true
+	r.sentStatus = code
+	r.stripHeaders()
+	r.removeInvalidHeaders()
+	r.signMessage(code)
+	r.startDigest(code)
+	r.startBodyLimit(code)
+	r.httpWriter.WriteHeader(code)
+}
+
+// write writes the data to the response body. All response methods
+// must write the body through it (or through the body writer), so the
+// written bytes are counted for the after-write hooks. If the status
+// code isn't sent yet, it is sent (StatusOK if it isn't set).
+func (r *Response) write(p []byte) (int, error) {
+	if r.finished && r.wroteHeader {
+		return 0, ErrAlreadyWritten
+	}
+
+	if !r.wroteHeader {
+		r.WriteHeaderNow()
+	}
+
+	// The data beyond the body size limit is discarded.
+	data := r.limitBody(p)
+
+	r.setWriteDeadline()
+	n, err := r.httpWriter.Write(data)
+	r.written += int64(n)
+	if r.digest != nil && r.digest.hash != nil {
+		r.digest.hash.Write(data[:n])
+	}
+	if r.progress != nil && n > 0 {
+		r.progress(r.written)
+	}
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		err = r.timeoutError(err)
+	}
+
+	if err != nil && r.writeErr == nil {
+		r.writeErr = err
+	}
+
+	if err == nil && len(data) < len(p) {
+		err = r.bodyTooLarge()
+	}
+
+	return n, err
+}
+
+// body returns the writer of the response body.
+func (r *Response) body() io.Writer {
+	return bodyWriter{r}
+}
+
+// finish calls the after-write hooks once, when the response method
+// returns. It must be deferred by every response method that writes
+// the response, with a pointer to the named error result. The errors
+// of the rejected status code, headers and signature are surfaced
+// here, if there is no other. If the response is already finished
+// and a method tried to send it again, ErrAlreadyWritten is surfaced.
+func (r *Response) finish(err *error) {
+	if r.finished {
+		if *err == nil && r.rewritten {
+			*err = ErrAlreadyWritten
+		}
+		return
+	}
+	r.finished = true
+	if *err == nil && r.statusErr != nil {
+		*err = r.statusErr
+	}
+	if *err == nil && r.headerErr != nil {
+		*err = r.headerErr
+	}
+	if *err == nil && r.signature != nil && r.signature.err != nil {
+		*err = r.signature.err
+	}
+
+	r.finishDigest()
+	r.finishBodyLimit()
+
+	if *err != nil {
+		r.logError(*err)
+	}
+
+	if len(r.afterWrite) == 0 {
+		return
+	}
+
+	info := ResponseInfo{
+		Status:      r.sentStatus,
+		Bytes:       r.written,
+		Duration:    time.Since(r.createdAt),
+		ContentType: r.httpWriter.Header().Get(HeaderContentType),
+		Err:         *err,
+	}
+
+	for _, f := range r.afterWrite {
+		f(info)
+	}
+}
+
+// Writer returns the writer of the response body for the code that
+// writes the body itself, e.g. png.Encode or csv.NewWriter. The writes
+// are accounted like the writes of the response methods (see
+// BytesWritten and the after-write hooks), and the status code and the
+// headers are sent before the first write (StatusOK if the status isn't
+// set). Call Finish when the body is written.
+//
+// Example Usage:
+//
+//	response := resp.NewResponse(w, resp.AddContentType("image/png"),
+//	    resp.WithAfterWrite(logWrite))
+//	response.Finish(png.Encode(response.Writer(), img))
+func (r *Response) Writer() io.Writer {
+	return r.body()
+}
+
+// Finish ends the response written with the Writer: the after-write
+// hooks are called with the error, and the error is returned. If the
+// status code isn't sent yet and there is no error, it is sent. The
+// hooks of the finished response aren't called again.
+func (r *Response) Finish(err error) error {
+	defer r.finish(&err)
+
+	if !r.wroteHeader && err == nil {
+		r.WriteHeaderNow()
+	}
+
+	return err
+}
+
+// BytesWritten returns the number of the body bytes written.
+func (r *Response) BytesWritten() int64 {
+	return r.written
+}
+
+// Written reports whether the status code and
+// the headers are sent to the client.
+func (r *Response) Written() bool {
+	return r.wroteHeader
+}
+
+// bodyWriter is the io.Writer of the response body.
+type bodyWriter struct {
+	r *Response
+}
+
+// Write writes the data to the response body.
+func (w bodyWriter) Write(p []byte) (int, error) {
+	return w.r.write(p)
+}
+
+// ReadFrom copies the data to the response body. It keeps the fast
+// path (e.g. sendfile) of the underlying writer if it is available.
+func (w bodyWriter) ReadFrom(src io.Reader) (int64, error) {
+	if w.r.finished && w.r.wroteHeader {
+		return 0, ErrAlreadyWritten
+	}
+
+	if !w.r.wroteHeader {
+		w.r.WriteHeaderNow()
+	}
+
+	// The fast path is skipped if the body is hashed, the progress
+	// is reported, the write timeout is set or the size is limited.
+	rf, ok := w.r.httpWriter.(io.ReaderFrom)
+	if ok && (w.r.digest == nil || w.r.digest.hash == nil) &&
+		w.r.progress == nil && w.r.writeTimeout <= 0 &&
+		w.r.bodyLimit == nil {
+		n, err := rf.ReadFrom(src)
+		w.r.written += n
+		if err != nil && w.r.writeErr == nil {
+			w.r.writeErr = err
+		}
+		return n, err
+	}
+
+	// Hide the ReadFrom method to avoid the recursion.
+	return copyBuffer(struct{ io.Writer }{w}, src)
+}
+
+// responseWriter is the http.ResponseWriter passed to functions of the
+// net/http package (e.g. http.ServeFile), so the status and the body
+// they write are recorded like the writes of the response methods.
+type responseWriter struct {
+	bodyWriter
+}
+
+// Header returns the header map of the response.
+func (w responseWriter) Header() http.Header {
+	return w.r.httpWriter.Header()
+}
+
+// WriteHeader sends the status code with the headers.
+func (w responseWriter) WriteHeader(code int) {
+	w.r.writeHeader(code)
+}
+
+// Unwrap returns the underlying http.ResponseWriter, so the writer
+// works with http.ResponseController (e.g. to flush the data).
+func (w responseWriter) Unwrap() http.ResponseWriter {
+	return w.r.httpWriter
+}
+
+// WithAfterWrite adds a hook that is called after the response
+// is written, with the status, the size of the body and the time
+// elapsed since the response was created.
+//
+// Example Usage:
+//
+//	logWrite := func(info resp.ResponseInfo) {
+//	    log.Printf("%d %d bytes in %s", info.Status, info.Bytes,
+//	        info.Duration)
+//	}
+//
+//	resp.JSON(w, data, resp.WithAfterWrite(logWrite))
+func WithAfterWrite(f AfterWriteFunc) Option {
+	return func(r *Response) *Response {
+		r.afterWrite = append(r.afterWrite, f)
+		return r
+	}
+}
@@ -0,0 +1,335 @@
+package resp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWithAfterWrite tests that the after-write hook receives
+// the status and the size of the body.
+func TestWithAfterWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		send   func(w http.ResponseWriter, opt Option) error
+		status int
+		bytes  int64
+	}{
+		{
+			"JSON",
+			func(w http.ResponseWriter, opt Option) error {
+				return JSON(w, R{"a": 1}, opt)
+			},
+			http.StatusOK, 8,
+		},
+		{
+			"Error",
+			func(w http.ResponseWriter, opt Option) error {
+				return Error(w, 1, "x", opt, WithStatusBadRequest())
+			},
+			http.StatusBadRequest, 25,
+		},
+		{
+			"String",
+			func(w http.ResponseWriter, opt Option) error {
+				return String(w, "hello", opt)
+			},
+			http.StatusOK, 5,
+		},
+		{
+			"Stream",
+			func(w http.ResponseWriter, opt Option) error {
+				return Stream(w, strings.NewReader("abc"), opt)
+			},
+			http.StatusOK, 3,
+		},
+		{
+			"Redirect",
+			func(w http.ResponseWriter, opt Option) error {
+				return Redirect(w, "/", opt)
+			},
+			http.StatusFound, 0,
+		},
+		{
+			"NoContent",
+			func(w http.ResponseWriter, opt Option) error {
+				return NoContent(w, opt)
+			},
+			http.StatusNoContent, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var info ResponseInfo
+			hook := WithAfterWrite(func(i ResponseInfo) {
+				calls++
+				info = i
+			})
+
+			w := httptest.NewRecorder()
+			if err := tt.send(w, hook); err != nil {
+				t.Fatalf("%s() returned error: %v", tt.name, err)
+			}
+
+			if calls != 1 {
+				t.Errorf("hook called %d times, want 1", calls)
+			}
+
+			if info.Status != tt.status || info.Bytes != tt.bytes {
+				t.Errorf("hook info = %+v, want status %d, bytes %d",
+					info, tt.status, tt.bytes)
+			}
+
+			if info.Bytes != int64(w.Body.Len()) {
+				t.Errorf("hook bytes = %d, body has %d bytes",
+					info.Bytes, w.Body.Len())
+			}
+		})
+	}
+}
+
+// TestWithAfterWrite_Error tests that the error of the
+// response method is passed to the hook.
+func TestWithAfterWrite_Error(t *testing.T) {
+	writeErr := errors.New("write failed")
+
+	var info ResponseInfo
+	hook := WithAfterWrite(func(i ResponseInfo) { info = i })
+	err := String(&mockErrorWriter{err: writeErr}, "data", hook)
+
+	if !errors.Is(info.Err, writeErr) || !errors.Is(err, writeErr) {
+		t.Errorf("hook error = %v, want %v", info.Err, writeErr)
+	}
+}
+
+// TestWithAfterWrite_ServeFile tests that the writes of
+// http.ServeFile are recorded.
+func TestWithAfterWrite_ServeFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(file, []byte("file data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var info ResponseInfo
+	hook := WithAfterWrite(func(i ResponseInfo) { info = i })
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/data.txt", nil)
+	if err := ServeFile(w, r, file, hook); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Status != http.StatusOK || info.Bytes != 9 {
+		t.Errorf("hook info = %+v", info)
+	}
+
+	if w.Body.String() != "file data" {
+		t.Errorf("ServeFile() body = %q", w.Body.String())
+	}
+}
+
+// TestWriteHeader_Once tests that the status code is sent once.
+func TestWriteHeader_Once(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewResponse(w)
+	r.writeHeader(http.StatusCreated)
+	r.writeHeader(http.StatusOK)
+
+	if w.Code != http.StatusCreated || r.sentStatus != http.StatusCreated {
+		t.Errorf("writeHeader() status = %d, want %d",
+			w.Code, http.StatusCreated)
+	}
+}
+
+// TestErrAlreadyWritten tests that the methods called
+// after the response is sent return ErrAlreadyWritten.
+func TestErrAlreadyWritten(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewResponse(w, WithStatusNotFound())
+	if err := r.Error(StatusNotFound, "not found"); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	body := w.Body.String()
+
+	if err := r.JSON(R{"ok": true}); !errors.Is(err, ErrAlreadyWritten) {
+		t.Errorf("JSON() error = %v, want %v", err, ErrAlreadyWritten)
+	}
+
+	if err := r.NoContent(); !errors.Is(err, ErrAlreadyWritten) {
+		t.Errorf("NoContent() error = %v, want %v", err, ErrAlreadyWritten)
+	}
+
+	if w.Code != StatusNotFound || w.Body.String() != body {
+		t.Errorf("response = %d %q, want %d %q",
+			w.Code, w.Body.String(), StatusNotFound, body)
+	}
+}
+
+// writerToReader is a reader that records the use of its WriteTo method.
+type writerToReader struct {
+	*strings.Reader
+	used bool
+}
+
+// WriteTo writes the data to the writer.
+func (r *writerToReader) WriteTo(w io.Writer) (int64, error) {
+	r.used = true
+	return r.Reader.WriteTo(w)
+}
+
+// readerFromRecorder is a recorder that records
+// the use of its ReadFrom method.
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	used bool
+}
+
+// ReadFrom copies the data to the body.
+func (w *readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	w.used = true
+	return w.ResponseRecorder.Body.ReadFrom(src)
+}
+
+// TestStream_FastPaths tests that Stream uses the WriteTo method
+// of the reader and the ReadFrom method of the writer.
+func TestStream_FastPaths(t *testing.T) {
+	t.Run("WriterTo", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		src := &writerToReader{Reader: strings.NewReader("data")}
+		if err := Stream(w, src); err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+
+		if !src.used || w.Body.String() != "data" {
+			t.Errorf("WriteTo used = %v, body = %q", src.used, w.Body)
+		}
+	})
+
+	t.Run("ReaderFrom", func(t *testing.T) {
+		w := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+		var written int64
+		err := Stream(w, struct{ io.Reader }{strings.NewReader("data")},
+			WithAfterWrite(func(info ResponseInfo) {
+				written = info.Bytes
+			}))
+		if err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+
+		if !w.used || w.Body.String() != "data" || written != 4 {
+			t.Errorf("ReadFrom used = %v, body = %q, bytes = %d",
+				w.used, w.Body, written)
+		}
+	})
+
+	t.Run("Pooled buffer", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		src := struct{ io.Reader }{strings.NewReader("data")}
+		if err := Stream(w, src); err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+
+		if w.Body.String() != "data" {
+			t.Errorf("Stream() body = %q, want %q", w.Body, "data")
+		}
+	})
+}
+
+// TestResponseWriter tests that the writes through the Writer
+// are accounted and reported to the after-write hooks.
+func TestResponseWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	calls := 0
+	var info ResponseInfo
+	response := NewResponse(w, WithStatusCreated(), AsTextPlain(),
+		WithAfterWrite(func(i ResponseInfo) {
+			calls++
+			info = i
+		}))
+
+	if response.Written() {
+		t.Error("Written() before the write = true")
+	}
+
+	if _, err := io.WriteString(response.Writer(), "Hello, "); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// The struct hides the WriteTo method of the reader.
+	src := struct{ io.Reader }{strings.NewReader("World!")}
+	if _, err := io.Copy(response.Writer(), src); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	if !response.Written() || response.BytesWritten() != 13 {
+		t.Errorf("Written() = %v, BytesWritten() = %d",
+			response.Written(), response.BytesWritten())
+	}
+
+	if err := response.Finish(nil); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	response.Finish(nil)
+
+	if calls != 1 {
+		t.Errorf("hook calls = %d, want %d", calls, 1)
+	}
+
+	if info.Status != http.StatusCreated || info.Bytes != 13 {
+		t.Errorf("ResponseInfo = %+v", info)
+	}
+
+	if w.Code != http.StatusCreated || w.Body.String() != "Hello, World!" {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+}
+
+// TestResponseFinish_Error tests that the error passed
+// to Finish is reported to the after-write hooks.
+func TestResponseFinish_Error(t *testing.T) {
+	w := httptest.NewRecorder()
+	want := errors.New("encode failed")
+
+	var info ResponseInfo
+	response := NewResponse(w, WithAfterWrite(func(i ResponseInfo) {
+		info = i
+	}))
+
+	if err := response.Finish(want); err != want {
+		t.Errorf("Finish() error = %v, want %v", err, want)
+	}
+
+	if info.Err != want || response.Written() {
+		t.Errorf("ResponseInfo.Err = %v, Written() = %v",
+			info.Err, response.Written())
+	}
+}
+
+// TestResponseUnwrap tests that the underlying writer is available
+// to http.ResponseController through the Unwrap methods.
+func TestResponseUnwrap(t *testing.T) {
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	response := NewResponse(w)
+
+	if got := response.Unwrap(); got != w {
+		t.Errorf("Unwrap() = %v, want %v", got, w)
+	}
+
+	// The wrapper passed to net/http reaches the flusher.
+	rw := responseWriter{bodyWriter{response}}
+	if err := http.NewResponseController(rw).Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if w.flushes != 1 {
+		t.Errorf("flushes = %d, want %d", w.flushes, 1)
+	}
+}